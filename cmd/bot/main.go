@@ -2,33 +2,50 @@ package main
 
 import (
     "context"
+    "flag"
     "log"
-    "time"
+    "os"
+    "os/signal"
+    "syscall"
 
     "github.com/AlibekAbdunasimov/chiptatop/internal/bot"
     "github.com/AlibekAbdunasimov/chiptatop/internal/config"
 )
 
 func main() {
+    offline := flag.Bool("offline", false, "answer railway requests from the recorded cassette instead of the network")
+    flag.Parse()
+
     cfg := config.Load()
+    if *offline {
+        cfg.CassetteMode = config.CassetteReplay
+    }
 
     b, err := bot.New(cfg)
     if err != nil {
         log.Fatalf("failed to create bot: %v", err)
     }
 
-    ctx, cancel := context.WithCancel(context.Background())
-    defer cancel()
+    // The first interrupt stops the bot gracefully, a second one kills it
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    go func() {
+        <-ctx.Done()
+        stop()
+    }()
 
+    status := 0
     if err := b.Run(ctx); err != nil {
-        // Avoid noisy error on normal shutdown
-        if err.Error() != context.Canceled.Error() {
-            log.Printf("bot stopped: %v", err)
-        }
+        log.Printf("bot stopped: %v", err)
+        status = 1
     }
 
-    // Give some time for pending operations
-    time.Sleep(200 * time.Millisecond)
-}
-
+    // Flush storage and the railway session once no work is left. After a
+    // shutdown timeout work is still in flight; storage stays usable for it
+    // and saves its changes at once.
+    if err := b.Close(); err != nil {
+        log.Printf("failed to close bot: %v", err)
+        status = 1
+    }
 
+    os.Exit(status)
+}
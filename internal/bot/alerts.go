@@ -0,0 +1,543 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxAlertsPerChat limits how many active alerts a single chat can have.
+// Paused and expired alerts do not count.
+const maxAlertsPerChat = 10
+
+// Alert creation flow steps
+const (
+	stepAlertDirection = "alert_direction"
+	stepAlertSeatTypes = "alert_seat_types"
+	stepAlertMinPrice  = "alert_min_price"
+	stepAlertMaxPrice  = "alert_max_price"
+)
+
+// Message keys of the alert flow buttons
+const (
+	buttonAnySeatType = "button.any_seat_type"
+	buttonSeatsDone   = "button.seats_done"
+	buttonSkip        = "button.skip"
+
+	buttonDirectionForward = "button.direction_forward"
+	buttonDirectionReturn  = "button.direction_return"
+	buttonDirectionBoth    = "button.direction_both"
+)
+
+// alertDirections maps the direction buttons to the legs an alert watches
+var alertDirections = map[string]string{
+	buttonDirectionForward: train.DirectionForward,
+	buttonDirectionReturn:  train.DirectionReturn,
+	buttonDirectionBoth:    train.DirectionBoth,
+}
+
+// alertSeatTypes lists the car types offered in the alert seat type step.
+// These are railway API values and are shown as is.
+var alertSeatTypes = []string{"O'rindiqli", "Plaskartli", "Kupe", "SV"}
+
+// handleAlertCommand starts the guided alert creation flow
+func (b *Bot) handleAlertCommand(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	alerts, err := b.scheduler.AlertsForChat(chatID)
+	if err != nil {
+		log.Printf("Failed to load alerts for chat %d: %v", chatID, err)
+		b.sendAlertStorageError(chatID)
+		return
+	}
+
+	if countActiveAlerts(alerts) >= maxAlertsPerChat {
+		msg := tgbotapi.NewMessage(chatID, l.N("alert.limit_reached", maxAlertsPerChat, maxAlertsPerChat))
+		b.safeSend(msg)
+		return
+	}
+
+	// Reset user state and start date selection in alert mode
+	b.resetUserState(chatID)
+	userState := b.getUserState(chatID)
+	userState.Mode = modeAlert
+	userState.CurrentStep = "select_date"
+	b.saveUserState(chatID, userState)
+
+	msg := tgbotapi.NewMessage(chatID, l.T("alert.new_intro"))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+
+	b.showCalendar(chatID, train.Today())
+}
+
+// handleAlertsCommand lists all alerts of the chat
+func (b *Bot) handleAlertsCommand(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	alerts, err := b.scheduler.AlertsForChat(chatID)
+	if err != nil {
+		log.Printf("Failed to load alerts for chat %d: %v", chatID, err)
+		b.sendAlertStorageError(chatID)
+		return
+	}
+
+	if len(alerts) == 0 {
+		msg := tgbotapi.NewMessage(chatID, l.T("alert.none"))
+		b.safeSend(msg)
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(l.N("alert.list_header", len(alerts), len(alerts)))
+
+	for i, alert := range alerts {
+		response.WriteString(b.formatAlert(alert, l))
+		if i < len(alerts)-1 {
+			response.WriteString("\n")
+		}
+	}
+
+	response.WriteString(l.T("alert.list_footer"))
+
+	b.sendLongMessage(chatID, response.String())
+}
+
+// handlePauseCommand pauses an alert
+func (b *Bot) handlePauseCommand(update tgbotapi.Update) {
+	b.setAlertActive(update, false)
+}
+
+// handleResumeCommand resumes a paused alert
+func (b *Bot) handleResumeCommand(update tgbotapi.Update) {
+	b.setAlertActive(update, true)
+}
+
+// handleDeleteCommand deletes an alert
+func (b *Bot) handleDeleteCommand(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+
+	alert, ok := b.alertFromArgs(update, "delete")
+	if !ok {
+		return
+	}
+
+	if err := b.scheduler.RemoveAlert(alert.ID); err != nil {
+		log.Printf("Failed to delete alert %s: %v", alert.ID, err)
+		b.sendAlertStorageError(chatID)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.localizer(chatID).T("alert.deleted", alert.ID))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+}
+
+func (b *Bot) setAlertActive(update tgbotapi.Update, active bool) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	command := "pause"
+	if active {
+		command = "resume"
+	}
+
+	alert, ok := b.alertFromArgs(update, command)
+	if !ok {
+		return
+	}
+
+	if err := b.scheduler.SetActive(alert.ID, active); err != nil {
+		log.Printf("Failed to update alert %s: %v", alert.ID, err)
+		b.sendAlertStorageError(chatID)
+		return
+	}
+
+	text := l.T("alert.paused", alert.ID)
+	if active {
+		text = l.T("alert.resumed", alert.ID)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+}
+
+// alertFromArgs looks up the alert referenced in the command arguments and
+// makes sure it belongs to the chat. It replies with an error message on failure.
+func (b *Bot) alertFromArgs(update tgbotapi.Update, command string) (train.TicketAlert, bool) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+	args := strings.Fields(update.Message.CommandArguments())
+
+	if len(args) < 1 {
+		msg := tgbotapi.NewMessage(chatID, l.T("alert.id_required", command))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return train.TicketAlert{}, false
+	}
+
+	alert, err := b.scheduler.GetAlert(args[0])
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to load alert %s: %v", args[0], err)
+		b.sendAlertStorageError(chatID)
+		return train.TicketAlert{}, false
+	}
+	if err != nil || alert.ChatID != chatID {
+		msg := tgbotapi.NewMessage(chatID, l.T("alert.not_found"))
+		b.safeSend(msg)
+		return train.TicketAlert{}, false
+	}
+
+	return alert, true
+}
+
+func (b *Bot) sendAlertStorageError(chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, b.localizer(chatID).T("alert.storage_error"))
+	b.safeSend(msg)
+}
+
+// formatAlert formats a single alert for the /alerts list
+func (b *Bot) formatAlert(alert train.TicketAlert, l *i18n.Localizer) string {
+	var builder strings.Builder
+
+	status := l.T("alert.status_active")
+	if !alert.IsActive {
+		status = l.T("alert.status_paused")
+	}
+
+	seatTypes := l.T("alert.any_seat_type")
+	if len(alert.SeatTypes) > 0 {
+		seatTypes = escapeMarkdown(strings.Join(alert.SeatTypes, ", "))
+	}
+
+	builder.WriteString(fmt.Sprintf("🆔 `%s` - %s\n", alert.ID, status))
+	builder.WriteString(fmt.Sprintf("🚉 %s → %s\n", escapeMarkdown(b.stationDisplayName(alert.From, l)), escapeMarkdown(b.stationDisplayName(alert.To, l))))
+	if alert.ReturnDate.IsZero() {
+		builder.WriteString(fmt.Sprintf("📅 %s\n", alert.Date.Format("2006-01-02")))
+	} else {
+		builder.WriteString(fmt.Sprintf("📅 %s ⇄ %s\n", alert.Date.Format("2006-01-02"), alert.ReturnDate.Format("2006-01-02")))
+		builder.WriteString(fmt.Sprintf("🧭 %s\n", l.T(directionButton(alert.Direction))))
+	}
+	builder.WriteString(fmt.Sprintf("💺 %s\n", seatTypes))
+	builder.WriteString(fmt.Sprintf("💰 %s\n", b.formatPriceRange(alert.MinPrice, alert.MaxPrice, l)))
+	builder.WriteString(l.T("alert.notify_count", alert.NotifyCount))
+
+	if !alert.LastChecked.IsZero() {
+		builder.WriteString(l.T("alert.last_checked", alert.LastChecked.Format("2006-01-02 15:04")))
+	}
+
+	return builder.String()
+}
+
+func (b *Bot) formatPriceRange(minPrice, maxPrice float64, l *i18n.Localizer) string {
+	switch {
+	case minPrice > 0 && maxPrice > 0:
+		return l.T("price.range", b.trainService.FormatPrice(int(minPrice)), b.trainService.FormatPrice(int(maxPrice)))
+	case minPrice > 0:
+		return l.T("price.from", b.trainService.FormatPrice(int(minPrice)))
+	case maxPrice > 0:
+		return l.T("price.up_to", b.trainService.FormatPrice(int(maxPrice)))
+	default:
+		return l.T("price.any")
+	}
+}
+
+// directionButton returns the button key describing the legs an alert watches
+func directionButton(direction string) string {
+	for button, d := range alertDirections {
+		if d == direction {
+			return button
+		}
+	}
+	return buttonDirectionForward
+}
+
+// askAlertDirection asks which legs of a round trip the alert should watch
+func (b *Bot) askAlertDirection(chatID int64, userState *UserState) {
+	l := b.localizer(chatID)
+
+	userState.CurrentStep = stepAlertDirection
+	b.saveUserState(chatID, userState)
+
+	from := b.stationDisplayName(userState.FromStation, l)
+	to := b.stationDisplayName(userState.ToStation, l)
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonDirectionForward)),
+			tgbotapi.NewKeyboardButton(l.T(buttonDirectionReturn)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonDirectionBoth)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonBack)),
+		),
+	)
+	keyboard.ResizeKeyboard = true
+	keyboard.OneTimeKeyboard = false
+
+	msg := tgbotapi.NewMessage(chatID, l.T("alert.direction_prompt",
+		from, to, userState.SearchDate.Format("2006-01-02"),
+		to, from, userState.ReturnDate.Format("2006-01-02")))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	b.safeSend(msg)
+}
+
+// startAlertSeatTypeSelection continues the alert flow after stations are chosen
+func (b *Bot) startAlertSeatTypeSelection(chatID int64, userState *UserState) {
+	l := b.localizer(chatID)
+
+	userState.CurrentStep = stepAlertSeatTypes
+	userState.SeatTypes = nil
+	b.saveUserState(chatID, userState)
+
+	msg := tgbotapi.NewMessage(chatID, l.T("alert.seat_types_prompt",
+		b.stationDisplayName(userState.FromStation, l), b.stationDisplayName(userState.ToStation, l),
+		l.T(buttonSeatsDone), l.T(buttonAnySeatType)))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = b.seatTypeKeyboard(l)
+	b.safeSend(msg)
+}
+
+// handleAlertStep handles text input for the alert creation steps
+func (b *Bot) handleAlertStep(chatID int64, text string, userState *UserState) {
+	l := b.localizer(chatID)
+	text = strings.TrimSpace(text)
+
+	switch userState.CurrentStep {
+	case stepAlertDirection:
+		direction, ok := alertDirections[i18n.Match(text, buttonDirectionForward, buttonDirectionReturn, buttonDirectionBoth)]
+		if !ok {
+			b.askAlertDirection(chatID, userState)
+			return
+		}
+		userState.Direction = direction
+		b.startAlertSeatTypeSelection(chatID, userState)
+
+	case stepAlertSeatTypes:
+		switch i18n.Match(text, buttonAnySeatType, buttonSeatsDone) {
+		case buttonAnySeatType:
+			userState.SeatTypes = nil
+			b.askAlertPrice(chatID, userState, stepAlertMinPrice)
+		case buttonSeatsDone:
+			b.askAlertPrice(chatID, userState, stepAlertMinPrice)
+		default:
+			seatType, ok := alertSeatType(text)
+			if !ok {
+				msg := tgbotapi.NewMessage(chatID, l.T("alert.seat_type_unknown", text))
+				msg.ReplyMarkup = b.seatTypeKeyboard(l)
+				b.safeSend(msg)
+				return
+			}
+			if !containsFold(userState.SeatTypes, seatType) {
+				userState.SeatTypes = append(userState.SeatTypes, seatType)
+				b.saveUserState(chatID, userState)
+			}
+			msg := tgbotapi.NewMessage(chatID,
+				l.T("alert.seat_types_selected", strings.Join(userState.SeatTypes, ", "), l.T(buttonSeatsDone)))
+			b.safeSend(msg)
+		}
+
+	case stepAlertMinPrice:
+		price, ok := b.parseAlertPrice(chatID, text)
+		if !ok {
+			return
+		}
+		userState.MinPrice = price
+		b.askAlertPrice(chatID, userState, stepAlertMaxPrice)
+
+	case stepAlertMaxPrice:
+		price, ok := b.parseAlertPrice(chatID, text)
+		if !ok {
+			return
+		}
+		if price > 0 && price < userState.MinPrice {
+			msg := tgbotapi.NewMessage(chatID, l.T("alert.max_below_min"))
+			b.safeSend(msg)
+			return
+		}
+		userState.MaxPrice = price
+		b.createAlert(chatID, userState)
+
+	default:
+		b.resetUserState(chatID)
+		b.handleMainMenuButton(chatID)
+	}
+}
+
+func (b *Bot) askAlertPrice(chatID int64, userState *UserState, step string) {
+	l := b.localizer(chatID)
+
+	userState.CurrentStep = step
+	b.saveUserState(chatID, userState)
+
+	text := l.T("alert.min_price_prompt")
+	if step == stepAlertMaxPrice {
+		text = l.T("alert.max_price_prompt")
+	}
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonSkip)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonBack)),
+		),
+	)
+	keyboard.ResizeKeyboard = true
+	keyboard.OneTimeKeyboard = false
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	b.safeSend(msg)
+}
+
+// parseAlertPrice parses a price entered by the user. Skip means no limit.
+func (b *Bot) parseAlertPrice(chatID int64, text string) (float64, bool) {
+	if i18n.Match(text, buttonSkip) != "" {
+		return 0, true
+	}
+
+	// Allow "270 000" and "270,000" style input
+	cleaned := strings.NewReplacer(" ", "", ",", "", "_", "").Replace(text)
+	price, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || price < 0 {
+		msg := tgbotapi.NewMessage(chatID, b.localizer(chatID).T("alert.invalid_price"))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return 0, false
+	}
+
+	return price, true
+}
+
+// createAlert registers the alert collected in the user state
+func (b *Bot) createAlert(chatID int64, userState *UserState) {
+	l := b.localizer(chatID)
+
+	alert := train.TicketAlert{
+		ID:         newAlertID(),
+		UserID:     chatID,
+		ChatID:     chatID,
+		From:       userState.FromStation,
+		To:         userState.ToStation,
+		Date:       userState.SearchDate,
+		SeatTypes:  userState.SeatTypes,
+		MinPrice:   userState.MinPrice,
+		MaxPrice:   userState.MaxPrice,
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+		Language:   i18n.APILanguage(l.Locale()),
+		ReturnDate: userState.ReturnDate,
+		Direction:  train.DirectionForward,
+	}
+	if !alert.ReturnDate.IsZero() && userState.Direction != "" {
+		alert.Direction = userState.Direction
+	}
+
+	if err := b.scheduler.AddAlert(alert); err != nil {
+		log.Printf("Failed to save alert for chat %d: %v", chatID, err)
+		b.sendAlertStorageError(chatID)
+		return
+	}
+	b.resetUserState(chatID)
+
+	text := l.T("alert.created_header") + b.formatAlert(alert, l) + l.T("alert.created_footer")
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = mainMenuKeyboard(l)
+	b.safeSend(msg)
+}
+
+func (b *Bot) seatTypeKeyboard(l *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for i := 0; i < len(alertSeatTypes); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(alertSeatTypes[i]))
+		if i+1 < len(alertSeatTypes) {
+			row = append(row, tgbotapi.NewKeyboardButton(alertSeatTypes[i+1]))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows,
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonAnySeatType)),
+			tgbotapi.NewKeyboardButton(l.T(buttonSeatsDone)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonBack)),
+		),
+	)
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+	keyboard.OneTimeKeyboard = false
+	return keyboard
+}
+
+// newAlertID generates a short random alert identifier
+func newAlertID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// countActiveAlerts returns how many alerts are still being checked, that is
+// not paused and not past their last travel date
+func countActiveAlerts(alerts []train.TicketAlert) int {
+	today := train.Today()
+	count := 0
+	for _, alert := range alerts {
+		if alert.IsActive && !alert.LastTravelDate().Before(today) {
+			count++
+		}
+	}
+	return count
+}
+
+// alertSeatType returns the offered seat type text names, compared the way
+// station names are, so case, script and the apostrophe do not matter
+func alertSeatType(text string) (string, bool) {
+	typed := train.NormalizeName(text)
+	if typed == "" {
+		return "", false
+	}
+	for _, seatType := range alertSeatTypes {
+		if train.NormalizeName(seatType) == typed {
+			return seatType, true
+		}
+	}
+	return "", false
+}
+
+// escapeMarkdown escapes text so that it shows literally in a Markdown message
+func escapeMarkdown(text string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+func TestAlertAcceptsOnlyOfferedSeatTypes(t *testing.T) {
+	c := newConversation(t)
+
+	now := train.Today()
+	tomorrow := now.AddDate(0, 0, 1)
+	c.say("/alert")
+	c.expect("alert.new_intro")
+	calendar := c.expect("calendar.title", c.l.T(fmt.Sprintf("month.%d", now.Month())), now.Year())
+	c.pickDate(calendar, tomorrow)
+	c.expect("calendar.date_selected", tomorrow.Format("2006-01-02"))
+	returnCalendar := c.expect("calendar.return_title_optional", c.l.T(fmt.Sprintf("month.%d", tomorrow.Month())), tomorrow.Year())
+	c.press(returnCalendar, "one_way")
+	c.expect("calendar.one_way_selected")
+	c.expect("station.select_departure")
+	c.say("Toshkent")
+	c.expect("station.departure_selected", c.station("Toshkent"))
+	c.say("Samarqand")
+	c.expect("alert.seat_types_prompt", c.station("Toshkent"), c.station("Samarqand"),
+		c.l.T(buttonSeatsDone), c.l.T(buttonAnySeatType))
+
+	c.say("*Lyuks_")
+	if reply := c.expect("alert.seat_type_unknown", "*Lyuks_"); reply.Keyboard == nil {
+		t.Errorf("unknown seat type answered without the seat type keyboard")
+	}
+
+	c.say("o‘rindiqli")
+	c.expect("alert.seat_types_selected", "O'rindiqli", c.l.T(buttonSeatsDone))
+}
+
+func TestFormatAlertEscapesMarkdown(t *testing.T) {
+	b := &Bot{trainService: train.NewService()}
+	alert := train.TicketAlert{
+		ID:        "a1",
+		From:      "Toshkent",
+		To:        "Samarqand",
+		Date:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		SeatTypes: []string{"Kupe_*"},
+		IsActive:  true,
+	}
+
+	got := b.formatAlert(alert, i18n.New(i18n.English))
+	if !strings.Contains(got, `Kupe\_\*`) {
+		t.Errorf("formatAlert() = %q, want the seat type escaped", got)
+	}
+}
+
+func TestCountActiveAlerts(t *testing.T) {
+	today := train.Today()
+	tests := []struct {
+		name   string
+		alerts []train.TicketAlert
+		want   int
+	}{
+		{"none", nil, 0},
+		{"active", []train.TicketAlert{{IsActive: true, Date: today}}, 1},
+		{"paused", []train.TicketAlert{{IsActive: false, Date: today.AddDate(0, 0, 3)}}, 0},
+		{"expired", []train.TicketAlert{{IsActive: true, Date: today.AddDate(0, 0, -1)}}, 0},
+		{"return still ahead", []train.TicketAlert{{
+			IsActive:   true,
+			Date:       today.AddDate(0, 0, -1),
+			ReturnDate: today.AddDate(0, 0, 2),
+			Direction:  train.DirectionBoth,
+		}}, 1},
+		{"mixed", []train.TicketAlert{
+			{IsActive: true, Date: today.AddDate(0, 0, 1)},
+			{IsActive: false, Date: today.AddDate(0, 0, 1)},
+			{IsActive: true, Date: today.AddDate(0, 0, -2)},
+			{IsActive: true, Date: today.AddDate(0, 0, 5)},
+		}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countActiveAlerts(tt.alerts); got != tt.want {
+				t.Errorf("countActiveAlerts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertSeatType(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"Kupe", "Kupe", true},
+		{"kupe", "Kupe", true},
+		{"O'rindiqli", "O'rindiqli", true},
+		{"O‘rindiqli", "O'rindiqli", true},
+		{"oʻrindiqli", "O'rindiqli", true},
+		{"sv", "SV", true},
+		{"Купе", "Kupe", true},
+		{"СВ", "SV", true},
+		{"*Kupe*", "Kupe", true},
+		{"Lyuks", "", false},
+		{"", "", false},
+		{"!!", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := alertSeatType(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("alertSeatType(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -2,17 +2,20 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
+	"net/http"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/AlibekAbdunasimov/chiptatop/internal/config"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/scheduler"
 	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/storage"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -20,29 +23,76 @@ type Bot struct {
 	api          *tgbotapi.BotAPI
 	cfg          config.Config
 	trainService *train.Service
-	userStates   map[int64]*UserState
+	scheduler    *scheduler.Scheduler
+	store        storage.Store
 }
 
-type UserState struct {
-	CurrentStep string
-	FromStation string
-	ToStation   string
-	SearchDate  time.Time
-}
+// UserState tracks where a user is in a multi-step conversation
+type UserState = storage.UserState
+
+// defaultShutdownTimeout is used when no shutdown timeout is configured
+const defaultShutdownTimeout = 30 * time.Second
+
+// modeAlert marks a user state that collects data for a new ticket alert
+const modeAlert = "alert"
 
 func New(cfg config.Config) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
+	endpoint := cfg.TelegramAPIEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.TelegramBotToken, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := cassetteTransport(cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	store, err := storage.Open(cfg.StorageBackend, cfg.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage: %w", err)
+	}
+
 	// Initialize train service with default language (Uzbek)
 	trainService := train.NewService()
+	if cfg.RailwayBaseURL != "" || cfg.RailwayBaseURLv1 != "" {
+		trainService.SetBaseURL(cfg.RailwayBaseURL, cfg.RailwayBaseURLv1)
+	}
+	if transport != nil {
+		trainService.SetTransport(transport)
+	}
+	trainService.SetRetryPolicy(train.RetryPolicy{
+		MaxAttempts: cfg.RetryMaxAttempts,
+		BaseDelay:   cfg.RetryBaseDelay,
+		MaxDelay:    cfg.RetryMaxDelay,
+	})
+	trainService.SetLimits(train.Limits{
+		Rate:        cfg.RequestRate,
+		Burst:       cfg.RequestBurst,
+		MaxInFlight: cfg.MaxInFlightRequests,
+	})
+	trainService.SetBreakerPolicy(train.BreakerPolicy{
+		Threshold: cfg.BreakerThreshold,
+		Cooldown:  cfg.BreakerCooldown,
+	})
+	trainService.SetSearchCacheTTL(cfg.SearchCacheTTL)
+	trainService.SetStaleResultsMaxAge(cfg.StaleResultsMaxAge)
+
+	// Restore the session of the previous run, if any
+	if err := trainService.OpenSession(cfg.SessionPath); err != nil {
+		log.Printf("Warning: Failed to restore railway session: %v", err)
+	}
 
-	// Try to use environment credentials first, otherwise initialize dynamically
+	// Try to use environment credentials first, then the restored session,
+	// otherwise initialize dynamically
 	if cfg.RailwayXSRFToken != "" && cfg.RailwayCookies != "" {
 		trainService.SetAuthCredentials(cfg.RailwayXSRFToken, cfg.RailwayCookies)
 		log.Printf("Railway API authentication configured from environment")
+	} else if trainService.HasValidSession() {
+		log.Printf("Railway API session restored from %s", cfg.SessionPath)
 	} else {
 		log.Printf("No environment credentials - initializing dynamically...")
 		// Initialize credentials dynamically
@@ -55,54 +105,210 @@ func New(cfg config.Config) (*Bot, error) {
 	}
 
 	log.Printf("Bot @%s started in %s environment", api.Self.UserName, cfg.Environment)
-	return &Bot{
+	b := &Bot{
 		api:          api,
 		cfg:          cfg,
 		trainService: trainService,
-		userStates:   make(map[int64]*UserState),
-	}, nil
+		store:        store,
+	}
+	b.scheduler = scheduler.New(trainService, store, cfg.AlertCheckInterval, b.sendAlertNotification)
+
+	return b, nil
+}
+
+// cassetteTransport returns the transport for railway requests in the
+// configured cassette mode: one recording responses to the cassette, one
+// answering from it without the network, or nil to use the network as is
+func cassetteTransport(cfg config.Config) (http.RoundTripper, error) {
+	switch cfg.CassetteMode {
+	case "":
+		return nil, nil
+	case config.CassetteRecord:
+		recorder, err := train.NewRecorder(cfg.CassettePath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cassette: %w", err)
+		}
+		log.Printf("Recording railway responses to %s", cfg.CassettePath)
+		return recorder, nil
+	case config.CassetteReplay:
+		cassette, err := train.LoadCassette(cfg.CassettePath)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Offline: answering railway requests from %s (%d recorded)", cfg.CassettePath, len(cassette.Interactions))
+		return train.NewReplayer(cassette), nil
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", cfg.CassetteMode)
+	}
+}
+
+// Close releases resources held by the bot and flushes storage. It may be
+// called while updates are still being handled after Run timed out; their
+// changes are then saved as they are made.
+func (b *Bot) Close() error {
+	if err := b.trainService.SaveSession(); err != nil {
+		log.Printf("Failed to save railway session: %v", err)
+	}
+	return b.store.Close()
 }
 
+// Run receives updates, through a webhook if one is configured and by long
+// polling otherwise, until ctx is done. It then stops receiving, lets the
+// updates being handled and the running alert check finish, with their
+// messages sent, and returns. It returns nil after a graceful stop and an
+// error if receiving failed or the work did not finish within the shutdown
+// timeout.
 func (b *Bot) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var background sync.WaitGroup
+
+	// Keep the station catalog in sync with the railway handbook
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		b.trainService.SyncStations(ctx, b.cfg.StationsCachePath, b.cfg.StationsTTL)
+	}()
+
+	// Start background alert monitoring, unless another replica does it
+	if b.cfg.AlertChecks {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			b.scheduler.Run(ctx)
+		}()
+	}
+
+	// Process updates concurrently, keeping per-chat ordering
+	workers := newDispatcher(b.cfg.UpdateWorkers, b.cfg.UpdateQueueSize, b.handleUpdate)
+	workers.Start()
+
+	var err error
+	if b.cfg.WebhookURL != "" {
+		err = b.serveWebhook(ctx, workers)
+	} else {
+		err = b.pollUpdates(ctx, workers)
+	}
+
+	// Receiving stopped; stop the background work too if it failed
+	cancel()
+	if drainErr := b.drain(workers, &background); err == nil {
+		err = drainErr
+	}
+	return err
+}
+
+// drain waits for the workers to handle the updates they have and for the
+// background work to stop, at most the shutdown timeout
+func (b *Bot) drain(workers *dispatcher, background *sync.WaitGroup) error {
+	timeout := b.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	log.Printf("Waiting up to %v for work in flight...", timeout)
+
+	done := make(chan struct{})
+	go func() {
+		workers.Stop()
+		background.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		log.Printf("Work in flight finished")
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("shutdown timed out after %v with work in flight", timeout)
+	}
+}
+
+// pollUpdates receives updates by long polling until ctx is done. Updates
+// fetched but not yet handed to the workers are left for Telegram to deliver
+// again.
+func (b *Bot) pollUpdates(ctx context.Context, workers *dispatcher) error {
+	if err := b.clearWebhook(); err != nil {
+		return err
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 30
 
 	updates := b.api.GetUpdatesChan(u)
-
-	// Graceful shutdown handling
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer b.api.StopReceivingUpdates()
 
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-stop:
 			log.Println("Shutting down bot...")
 			return nil
 		case update := <-updates:
-			// Handle callback queries (inline keyboard buttons)
-			if update.CallbackQuery != nil {
-				b.handleCallbackQuery(update)
-				continue
+			// Dispatch only fails when ctx is done
+			if workers.Dispatch(ctx, update) != nil {
+				log.Println("Shutting down bot...")
+				return nil
 			}
+		}
+	}
+}
 
-			if update.Message == nil {
-				continue
-			}
+// clearWebhook removes a webhook left behind by an earlier run in webhook
+// mode, since Telegram refuses getUpdates while one is set. A webhook kept for
+// replicas is theirs: polling would take their updates, so it is an error.
+func (b *Bot) clearWebhook() error {
+	info, err := b.api.GetWebhookInfo()
+	if err != nil {
+		log.Printf("Failed to get webhook info: %v", err)
+		return nil
+	}
+	if !info.IsSet() {
+		return nil
+	}
 
-			// Handle commands
-			if update.Message.IsCommand() {
-				b.handleCommand(update)
-				continue
-			}
+	if !b.cfg.WebhookDeleteOnStop {
+		return fmt.Errorf("webhook %s is set and shared by replicas, not polling for updates", info.URL)
+	}
+	log.Printf("Deleting webhook %s left behind to poll for updates", info.URL)
+	if err := b.deleteWebhook(); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
 
-			// Handle text messages (menu button clicks)
-			if update.Message.Text != "" {
-				b.handleTextMessage(update)
-				continue
-			}
-		}
+// handleUpdate routes a single update to the matching handler
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	b.detectLocale(update)
+
+	// Handle callback queries (inline keyboard buttons)
+	if update.CallbackQuery != nil {
+		b.handleCallbackQuery(update)
+		return
+	}
+
+	if update.Message == nil {
+		return
+	}
+
+	// Handle commands
+	if update.Message.IsCommand() {
+		b.handleCommand(update)
+		return
+	}
+
+	// Handle a shared location (nearest station button)
+	if update.Message.Location != nil {
+		b.handleLocation(update)
+		return
+	}
+
+	// Handle text messages (menu button clicks)
+	if update.Message.Text != "" {
+		b.handleTextMessage(update)
+		return
 	}
 }
 
@@ -116,6 +322,14 @@ func (b *Bot) handleCallbackQuery(update tgbotapi.Update) {
 
 	if strings.HasPrefix(data, "month_") || strings.HasPrefix(data, "date_") {
 		b.handleCalendarCallback(update)
+	} else if strings.HasPrefix(data, flexCallbackPrefix) {
+		b.handleFlexDayCallback(update)
+	} else if strings.HasPrefix(data, nearestCallbackPrefix) {
+		b.handleNearestStationCallback(update)
+	} else if strings.HasPrefix(data, stationCallbackPrefix) {
+		b.handleStationCallback(update)
+	} else if data == "one_way" {
+		b.handleOneWayCallback(update)
 	} else if data == "main_menu" {
 		// Handle main menu button from inline keyboard
 		b.handleMainMenuButton(callback.Message.Chat.ID)
@@ -137,73 +351,97 @@ func (b *Bot) handleCommand(update tgbotapi.Update) {
 		b.handleSearchCommand(update)
 	case "search_date":
 		b.handleSearchDateCommand(update)
+	case "flex":
+		b.handleFlexCommand(update)
+	case "connect":
+		b.handleConnectCommand(update)
+	case "alert":
+		b.handleAlertCommand(update)
+	case "alerts":
+		b.handleAlertsCommand(update)
+	case "pause":
+		b.handlePauseCommand(update)
+	case "resume":
+		b.handleResumeCommand(update)
+	case "delete":
+		b.handleDeleteCommand(update)
 	default:
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Unknown command. Try /help to see available commands.")
+		l := b.localizer(update.Message.Chat.ID)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("error.unknown_command"))
 		b.safeSend(msg)
 	}
 }
 
 func (b *Bot) handleStartCommand(update tgbotapi.Update) {
-	welcomeText := `🚂 *Welcome to ChiptaTop!*
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
 
-I will help you find train tickets instantly. Use the menu buttons below:`
+	msg := tgbotapi.NewMessage(chatID, l.T("start.welcome"))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = mainMenuKeyboard(l)
+	b.safeSend(msg)
+}
 
-	// Create main menu keyboard
+// mainMenuKeyboard creates the main menu reply keyboard
+func mainMenuKeyboard(l *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔍 Search Trains"),
-			tgbotapi.NewKeyboardButton("📅 Search by Date"),
+			tgbotapi.NewKeyboardButton(l.T(buttonSearchTrains)),
+			tgbotapi.NewKeyboardButton(l.T(buttonSearchByDate)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🚉 View Stations"),
-			tgbotapi.NewKeyboardButton("🌍 Change Language"),
+			tgbotapi.NewKeyboardButton(l.T(buttonRoundTrip)),
+			tgbotapi.NewKeyboardButton(l.T(buttonFlexibleDates)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("❓ Help"),
+			tgbotapi.NewKeyboardButton(l.T(buttonViewStations)),
+			tgbotapi.NewKeyboardButton(l.T(buttonChangeLanguage)),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(l.T(buttonHelp)),
 		),
 	)
 	keyboard.ResizeKeyboard = true
 	keyboard.OneTimeKeyboard = false
-
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, welcomeText)
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
-	b.safeSend(msg)
+	return keyboard
 }
 
-func (b *Bot) handleHelpCommand(update tgbotapi.Update) {
-	helpText := `🚂 *ChiptaTop Train Bot Help*
-
-🔍 *How to Use:*
-• Use the menu buttons to navigate
-• Search for trains between any stations
-• View available dates and times
-• Change language as needed
-
-📋 *Available Options:*
-• Search Trains - Find trains for today
-• Search by Date - Find trains for specific date
-• View Stations - See all available stations
-• Change Language - Switch between Uzbek/Russian/English
-
-💡 *Tips:*
-• All major cities are supported
-• Results show available seats and prices
-• Automatic language detection`
-
-	// Create help keyboard with back button
+// backKeyboard creates a reply keyboard with only the back to main menu button
+func backKeyboard(l *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
+			tgbotapi.NewKeyboardButton(l.T(buttonBack)),
 		),
 	)
 	keyboard.ResizeKeyboard = true
 	keyboard.OneTimeKeyboard = false
+	return keyboard
+}
 
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, helpText)
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
-	b.safeSend(msg)
+// stationKeyboard creates the station selection keyboard with the localized
+// names of the keyboard stations of catalog
+func stationKeyboard(l *i18n.Localizer, catalog *train.StationCatalog) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	stations := catalog.KeyboardStations()
+	for i := 0; i < len(stations); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(stations[i].LocalizedName(l.Locale())))
+		if i+1 < len(stations) {
+			row = append(row, tgbotapi.NewKeyboardButton(stations[i+1].LocalizedName(l.Locale())))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(
+		tgbotapi.NewKeyboardButton(l.T(buttonBack)),
+	))
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+	keyboard.OneTimeKeyboard = false
+	return keyboard
+}
+
+func (b *Bot) handleHelpCommand(update tgbotapi.Update) {
+	b.handleHelpButton(update.Message.Chat.ID)
 }
 
 func (b *Bot) handleTextMessage(update tgbotapi.Update) {
@@ -213,183 +451,219 @@ func (b *Bot) handleTextMessage(update tgbotapi.Update) {
 	// Get or create user state
 	userState := b.getUserState(chatID)
 
-	switch text {
-	case "🔍 Search Trains":
+	// Menu buttons are recognized in every language so a keyboard sent
+	// before a language change keeps working
+	switch i18n.Match(text, menuButtons...) {
+	case buttonSearchTrains:
 		b.handleSearchTrainsButton(chatID)
-	case "📅 Search by Date":
+		return
+	case buttonSearchByDate:
 		b.handleSearchByDateButton(chatID)
-	case "🚉 View Stations":
+		return
+	case buttonRoundTrip:
+		b.handleRoundTripButton(chatID)
+		return
+	case buttonFlexibleDates:
+		b.handleFlexibleDatesButton(chatID)
+		return
+	case buttonViewStations:
 		b.handleViewStationsButton(chatID)
-	case "🌍 Change Language":
+		return
+	case buttonChangeLanguage:
 		b.handleChangeLanguageButton(chatID)
-	case "❓ Help":
+		return
+	case buttonHelp:
 		b.handleHelpButton(chatID)
-	case "🔙 Back to Main Menu":
+		return
+	case buttonBack:
 		b.handleMainMenuButton(chatID)
-	case "🇺🇿 O'zbekcha":
-		b.handleLanguageChange(chatID, "uz")
-	case "🇷🇺 Русский":
-		b.handleLanguageChange(chatID, "ru")
-	case "🇺🇸 English":
-		b.handleLanguageChange(chatID, "en")
-	default:
-		// Handle station selection based on current step
-		if userState.CurrentStep != "" {
-			b.handleStationSelection(chatID, text, userState)
-			return
-		}
+		return
+	}
+
+	if locale, ok := languageButtons[text]; ok {
+		b.handleLanguageChange(chatID, locale)
+		return
+	}
+
+	// Handle alert creation steps
+	if strings.HasPrefix(userState.CurrentStep, "alert_") {
+		b.handleAlertStep(chatID, text, userState)
+		return
+	}
+
+	// Handle station selection based on current step
+	if userState.CurrentStep != "" {
+		b.handleStationSelection(chatID, text, userState)
+		return
+	}
 
-		// Check if it's a search request (legacy support)
-		if strings.Contains(text, " ") {
-			parts := strings.Fields(text)
-			if len(parts) == 2 {
-				// Format: "from to" - search for today
-				b.handleSearchRequest(chatID, parts[0], parts[1], time.Now())
+	// Check if it's a search request (legacy support)
+	if strings.Contains(text, " ") {
+		parts := strings.Fields(text)
+		if len(parts) == 2 {
+			// Format: "from to" - search for today
+			b.handleSearchRequest(chatID, parts[0], parts[1], train.Today())
+			return
+		} else if len(parts) == 3 {
+			// Format: "from to date" - search for specific date
+			date, err := time.Parse("2006-01-02", parts[2])
+			if err == nil {
+				b.handleSearchRequest(chatID, parts[0], parts[1], date)
 				return
-			} else if len(parts) == 3 {
-				// Format: "from to date" - search for specific date
-				date, err := time.Parse("2006-01-02", parts[2])
-				if err == nil {
-					b.handleSearchRequest(chatID, parts[0], parts[1], date)
-					return
-				}
 			}
 		}
-
-		// Unknown text, show help
-		msg := tgbotapi.NewMessage(chatID,
-			"❓ I didn't understand that. Please use the menu buttons or send a search request in the format:\n\n"+
-				"`from_station to_station`\n"+
-				"or\n"+
-				"`from_station to_station YYYY-MM-DD`")
-		msg.ParseMode = "Markdown"
-		b.safeSend(msg)
 	}
+
+	// Unknown text, show help
+	msg := tgbotapi.NewMessage(chatID, b.localizer(chatID).T("error.not_understood"))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
 }
 
 func (b *Bot) getUserState(chatID int64) *UserState {
-	if state, exists := b.userStates[chatID]; exists {
+	state, err := b.store.GetUserState(chatID)
+	if err == nil {
 		return state
 	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to load user state for chat %d: %v", chatID, err)
+	}
 
 	// Create new user state
-	state := &UserState{
-		CurrentStep: "",
-		FromStation: "",
-		ToStation:   "",
-		SearchDate:  time.Time{},
-	}
-	b.userStates[chatID] = state
-	return state
+	return &UserState{}
+}
+
+func (b *Bot) saveUserState(chatID int64, state *UserState) {
+	if err := b.store.SaveUserState(chatID, state); err != nil {
+		log.Printf("Failed to save user state for chat %d: %v", chatID, err)
+	}
+}
+
+// userLocale returns the interface locale chosen by the user
+func (b *Bot) userLocale(chatID int64) string {
+	prefs, err := b.store.GetPreferences(chatID)
+	if err != nil {
+		log.Printf("Failed to load preferences for chat %d: %v", chatID, err)
+	}
+	if !i18n.IsSupported(prefs.Language) {
+		return i18n.Default
+	}
+	return prefs.Language
+}
+
+// userLanguage returns the railway API language matching the user's locale
+func (b *Bot) userLanguage(chatID int64) string {
+	return i18n.APILanguage(b.userLocale(chatID))
+}
+
+// localizer returns the message localizer for the user's locale
+func (b *Bot) localizer(chatID int64) *i18n.Localizer {
+	return i18n.New(b.userLocale(chatID))
+}
+
+// detectLocale stores the locale of the user's Telegram client as their
+// preference the first time we hear from them
+func (b *Bot) detectLocale(update tgbotapi.Update) {
+	chat := update.FromChat()
+	user := update.SentFrom()
+	if chat == nil || user == nil {
+		return
+	}
+
+	prefs, err := b.store.GetPreferences(chat.ID)
+	if err != nil || prefs.Language != "" {
+		return
+	}
+
+	prefs.Language = i18n.FromLanguageCode(user.LanguageCode)
+	if err := b.store.SavePreferences(chat.ID, prefs); err != nil {
+		log.Printf("Failed to save preferences for chat %d: %v", chat.ID, err)
+	}
 }
 
 func (b *Bot) resetUserState(chatID int64) {
-	b.userStates[chatID] = &UserState{
-		CurrentStep: "",
-		FromStation: "",
-		ToStation:   "",
-		SearchDate:  time.Time{},
+	if err := b.store.DeleteUserState(chatID); err != nil {
+		log.Printf("Failed to reset user state for chat %d: %v", chatID, err)
 	}
 }
 
 func (b *Bot) handleStationSelection(chatID int64, text string, userState *UserState) {
-	switch userState.CurrentStep {
-	case "select_from_station":
-		// Extract station name from button text (no flag emoji anymore)
-		stationName := strings.TrimSpace(text)
+	if userState.CurrentStep != "select_from_station" && userState.CurrentStep != "select_to_station" {
+		// Unknown step, reset and show main menu
+		b.resetUserState(chatID)
+		b.handleMainMenuButton(chatID)
+		return
+	}
 
-		// Debug logging
-		log.Printf("DEBUG: Button text: '%s', Extracted station: '%s'", text, stationName)
+	// Keyboard buttons carry localized names; typed names may be misspelled or
+	// in another script. Nothing is searched until the station is certain.
+	station, err := b.trainService.Stations().Resolve(text)
+	if err != nil {
+		log.Printf("Station %q not resolved: %v", text, err)
+		b.askStationChoice(chatID, err)
+		return
+	}
 
-		// Validate station name
-		if stationName == "" {
-			msg := tgbotapi.NewMessage(chatID, "❌ Invalid station selection. Please try again.")
-			b.safeSend(msg)
-			return
-		}
+	b.selectStation(chatID, station.Name, userState)
+}
+
+// selectStation stores a resolved station for the current step and moves the
+// conversation on
+func (b *Bot) selectStation(chatID int64, stationName string, userState *UserState) {
+	l := b.localizer(chatID)
 
+	switch userState.CurrentStep {
+	case "select_from_station":
 		// Store the clean station name
 		userState.FromStation = stationName
 		userState.CurrentStep = "select_to_station"
+		b.saveUserState(chatID, userState)
 
 		// Show destination station selection
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("✅ Departure station: *%s*\n\nNow select your destination station:", stationName))
+		msg := tgbotapi.NewMessage(chatID, l.T("station.departure_selected", b.stationDisplayName(stationName, l)))
 		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Toshkent"),
-				tgbotapi.NewKeyboardButton("Samarqand"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Buxoro"),
-				tgbotapi.NewKeyboardButton("Andijon"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Qarshi"),
-				tgbotapi.NewKeyboardButton("Termiz"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Nukus"),
-				tgbotapi.NewKeyboardButton("Xiva"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Jizzax"),
-				tgbotapi.NewKeyboardButton("Navoiy"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Namangan"),
-				tgbotapi.NewKeyboardButton("Margilon"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Qo'qon"),
-				tgbotapi.NewKeyboardButton("Guliston"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Urgench"),
-				tgbotapi.NewKeyboardButton("Pop"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-			),
-		)
+		msg.ReplyMarkup = stationKeyboard(l, b.trainService.Stations())
 		b.safeSend(msg)
 
 	case "select_to_station":
-		// Extract station name from button text (no flag emoji anymore)
-		stationName := strings.TrimSpace(text)
-
-		// Debug logging
-		log.Printf("DEBUG: Button text: '%s', Extracted destination station: '%s'", text, stationName)
-
-		// Validate station name
-		if stationName == "" {
-			msg := tgbotapi.NewMessage(chatID, "❌ Invalid station selection. Please try again.")
-			b.safeSend(msg)
-			return
-		}
-
 		userState.ToStation = stationName
 
 		// Check if it's the same station
 		if userState.FromStation == userState.ToStation {
-			msg := tgbotapi.NewMessage(chatID,
-				"❌ Departure and destination stations cannot be the same. Please select a different destination station.")
+			msg := tgbotapi.NewMessage(chatID, l.T("station.same"))
 			msg.ParseMode = "Markdown"
 			b.safeSend(msg)
 			return
 		}
 
+		// Alerts collect seat types and prices instead of searching right away
+		if userState.Mode == modeAlert {
+			if !userState.ReturnDate.IsZero() {
+				b.askAlertDirection(chatID, userState)
+				return
+			}
+			b.startAlertSeatTypeSelection(chatID, userState)
+			return
+		}
+
+		if userState.Mode == modeRoundTrip {
+			b.handleRoundTripSearch(chatID, userState)
+			b.resetUserState(chatID)
+			return
+		}
+
+		if userState.Mode == modeFlexible {
+			start, end := train.DateWindow(userState.SearchDate, flexDays)
+			b.handleFlexibleSearch(chatID, userState.FromStation, userState.ToStation, start, end)
+			b.resetUserState(chatID)
+			return
+		}
+
 		// Show confirmation and search
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("✅ *Search Confirmation*\n\n"+
-				"🚉 From: *%s*\n"+
-				"🎯 To: *%s*\n"+
-				"📅 Date: *%s*\n\n"+
-				"🔍 Searching for trains...",
-				userState.FromStation,
-				userState.ToStation,
-				userState.SearchDate.Format("2006-01-02")))
+		msg := tgbotapi.NewMessage(chatID, l.T("search.confirmation",
+			b.stationDisplayName(userState.FromStation, l),
+			b.stationDisplayName(userState.ToStation, l),
+			userState.SearchDate.Format("2006-01-02")))
 		msg.ParseMode = "Markdown"
 		b.safeSend(msg)
 
@@ -411,60 +685,18 @@ func (b *Bot) handleStationSelection(chatID int64, text string, userState *UserS
 }
 
 func (b *Bot) handleSearchTrainsButton(chatID int64) {
+	l := b.localizer(chatID)
+
 	// Reset user state and start station selection
 	b.resetUserState(chatID)
 	userState := b.getUserState(chatID)
 	userState.CurrentStep = "select_from_station"
-	userState.SearchDate = time.Now()
-
-	text := `🔍 *Search Trains (Today)*
-
-Please select your departure station:`
-
-	// Create station selection keyboard with all 16 stations
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Toshkent"),
-			tgbotapi.NewKeyboardButton("Samarqand"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Buxoro"),
-			tgbotapi.NewKeyboardButton("Andijon"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Qarshi"),
-			tgbotapi.NewKeyboardButton("Termiz"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Nukus"),
-			tgbotapi.NewKeyboardButton("Xiva"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Jizzax"),
-			tgbotapi.NewKeyboardButton("Navoiy"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Namangan"),
-			tgbotapi.NewKeyboardButton("Margilon"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Qo'qon"),
-			tgbotapi.NewKeyboardButton("Guliston"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Urgench"),
-			tgbotapi.NewKeyboardButton("Pop"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-		),
-	)
-	keyboard.ResizeKeyboard = true
-	keyboard.OneTimeKeyboard = false
+	userState.SearchDate = train.Today()
+	b.saveUserState(chatID, userState)
 
-	msg := tgbotapi.NewMessage(chatID, text)
+	msg := tgbotapi.NewMessage(chatID, l.T("search.today_prompt"))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = departureKeyboard(l, b.trainService.Stations())
 	b.safeSend(msg)
 }
 
@@ -473,56 +705,16 @@ func (b *Bot) handleSearchByDateButton(chatID int64) {
 	b.resetUserState(chatID)
 	userState := b.getUserState(chatID)
 	userState.CurrentStep = "select_date"
-	userState.SearchDate = time.Now().AddDate(0, 0, 1) // Default to tomorrow
+	userState.SearchDate = train.Today().AddDate(0, 0, 1) // Default to tomorrow
+	b.saveUserState(chatID, userState)
 
 	// Show calendar for date selection
-	b.showCalendar(chatID, time.Now())
+	b.showCalendar(chatID, train.Today())
 }
 
 // showCalendar displays a calendar for date selection
 func (b *Bot) showCalendar(chatID int64, currentDate time.Time) {
-	// Get the first day of the month and the number of days
-	year, month, _ := currentDate.Date()
-	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	lastDay := firstDay.AddDate(0, 1, -1)
-
-	// Calculate the day of week for the first day (0 = Sunday, 1 = Monday, etc.)
-	// Adjust to make Monday = 0 for better UX
-	firstDayWeekday := int(firstDay.Weekday())
-	if firstDayWeekday == 0 {
-		firstDayWeekday = 7 // Sunday becomes 7
-	} else {
-		firstDayWeekday-- // Monday becomes 0, Tuesday becomes 1, etc.
-	}
-
-	// Create calendar header
-	monthNames := []string{
-		"January", "February", "March", "April", "May", "June",
-		"July", "August", "September", "October", "November", "December",
-	}
-
-	calendarText := fmt.Sprintf("📅 Select Travel Date\n\n%s %d\n\n", monthNames[month-1], year)
-
-	// Create calendar grid using the helper function
-	keyboard := b.createCalendarGrid(year, month, firstDayWeekday, lastDay.Day())
-
-	// Month navigation row
-	prevMonth := currentDate.AddDate(0, -1, 0)
-	nextMonth := currentDate.AddDate(0, 1, 0)
-
-	monthRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("month_%d_%d", prevMonth.Year(), prevMonth.Month())),
-		tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("month_%d_%d", nextMonth.Year(), nextMonth.Month())),
-	}
-	keyboard = append(keyboard, monthRow)
-
-	// Add back button
-	backRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("🔙 Back to Main Menu", "main_menu"),
-	}
-	keyboard = append(keyboard, backRow)
-
-	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	calendarText, inlineKeyboard := b.calendarMarkup(b.localizer(chatID), currentDate, b.getUserState(chatID))
 
 	msg := tgbotapi.NewMessage(chatID, calendarText)
 	msg.ReplyMarkup = inlineKeyboard
@@ -531,6 +723,17 @@ func (b *Bot) showCalendar(chatID int64, currentDate time.Time) {
 
 // showCalendarEdit edits an existing calendar message (for month navigation)
 func (b *Bot) showCalendarEdit(chatID int64, messageID int, currentDate time.Time) {
+	calendarText, inlineKeyboard := b.calendarMarkup(b.localizer(chatID), currentDate, b.getUserState(chatID))
+
+	// Edit the existing message instead of sending a new one
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, calendarText)
+	editMsg.ReplyMarkup = &inlineKeyboard
+	b.safeSendEdit(editMsg)
+}
+
+// calendarMarkup builds the calendar text and inline keyboard for the month of currentDate.
+// The title and buttons depend on which date the user is currently picking.
+func (b *Bot) calendarMarkup(l *i18n.Localizer, currentDate time.Time, userState *UserState) (string, tgbotapi.InlineKeyboardMarkup) {
 	// Get the first day of the month and the number of days
 	year, month, _ := currentDate.Date()
 	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
@@ -546,19 +749,22 @@ func (b *Bot) showCalendarEdit(chatID int64, messageID int, currentDate time.Tim
 	}
 
 	// Create calendar header
-	monthNames := []string{
-		"January", "February", "March", "April", "May", "June",
-		"July", "August", "September", "October", "November", "December",
+	titleKey := "calendar.title"
+	if userState.CurrentStep == stepSelectReturnDate {
+		titleKey = "calendar.return_title"
+		if userState.Mode == modeAlert {
+			titleKey = "calendar.return_title_optional"
+		}
 	}
-
-	calendarText := fmt.Sprintf("📅 Select Travel Date\n\n%s %d\n\n", monthNames[month-1], year)
+	calendarText := l.T(titleKey, l.T(fmt.Sprintf("month.%d", month)), year)
 
 	// Create calendar grid using the helper function
-	keyboard := b.createCalendarGrid(year, month, firstDayWeekday, lastDay.Day())
+	keyboard := b.createCalendarGrid(l, year, month, firstDayWeekday, lastDay.Day())
 
-	// Month navigation row
-	prevMonth := currentDate.AddDate(0, -1, 0)
-	nextMonth := currentDate.AddDate(0, 1, 0)
+	// Month navigation row, from the first of the month so that e.g. 31
+	// January does not skip February
+	prevMonth := firstDay.AddDate(0, -1, 0)
+	nextMonth := firstDay.AddDate(0, 1, 0)
 
 	monthRow := []tgbotapi.InlineKeyboardButton{
 		tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("month_%d_%d", prevMonth.Year(), prevMonth.Month())),
@@ -566,33 +772,30 @@ func (b *Bot) showCalendarEdit(chatID int64, messageID int, currentDate time.Tim
 	}
 	keyboard = append(keyboard, monthRow)
 
+	// The way back is optional for alerts
+	if userState.CurrentStep == stepSelectReturnDate && userState.Mode == modeAlert {
+		keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData(l.T(buttonOneWay), "one_way"),
+		})
+	}
+
 	// Add back button
 	backRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("🔙 Back to Main Menu", "main_menu"),
+		tgbotapi.NewInlineKeyboardButtonData(l.T(buttonBack), "main_menu"),
 	}
 	keyboard = append(keyboard, backRow)
 
-	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(keyboard...)
-
-	// Edit the existing message instead of sending a new one
-	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, calendarText)
-	editMsg.ReplyMarkup = &inlineKeyboard
-	b.safeSendEdit(editMsg)
+	return calendarText, tgbotapi.NewInlineKeyboardMarkup(keyboard...)
 }
 
 // createCalendarGrid creates a properly aligned calendar grid
-func (b *Bot) createCalendarGrid(year int, month time.Month, firstDayWeekday int, totalDays int) [][]tgbotapi.InlineKeyboardButton {
+func (b *Bot) createCalendarGrid(l *i18n.Localizer, year int, month time.Month, firstDayWeekday int, totalDays int) [][]tgbotapi.InlineKeyboardButton {
 	var keyboard [][]tgbotapi.InlineKeyboardButton
 
 	// Add weekday headers row
-	weekdayRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("Mon", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Tue", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Wed", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Thu", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Fri", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Sat", "header"),
-		tgbotapi.NewInlineKeyboardButtonData("Sun", "header"),
+	var weekdayRow []tgbotapi.InlineKeyboardButton
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		weekdayRow = append(weekdayRow, tgbotapi.NewInlineKeyboardButtonData(l.T("weekday."+day), "header"))
 	}
 	keyboard = append(keyboard, weekdayRow)
 
@@ -633,6 +836,7 @@ func (b *Bot) handleCalendarCallback(update tgbotapi.Update) {
 	callback := update.CallbackQuery
 	chatID := callback.Message.Chat.ID
 	data := callback.Data
+	l := b.localizer(chatID)
 
 	// Get user state
 	userState := b.getUserState(chatID)
@@ -656,175 +860,91 @@ func (b *Bot) handleCalendarCallback(update tgbotapi.Update) {
 			selectedDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 
 			// Check if date is in the past
-			if selectedDate.Before(time.Now().Truncate(24 * time.Hour)) {
-				msg := tgbotapi.NewMessage(chatID, "❌ Cannot select a date in the past. Please choose a future date.")
+			if selectedDate.Before(train.Today()) {
+				msg := tgbotapi.NewMessage(chatID, l.T("calendar.past_date"))
 				b.safeSend(msg)
 				return
 			}
 
+			if userState.CurrentStep == stepSelectReturnDate {
+				b.handleReturnDateSelected(chatID, callback.Message.MessageID, userState, selectedDate)
+				return
+			}
+
 			// Store selected date and proceed to station selection
 			userState.SearchDate = selectedDate
 			userState.CurrentStep = "select_from_station"
 
-			// Show station selection by editing the existing calendar message
-			text := fmt.Sprintf("✅ Selected date: %s", selectedDate.Format("2006-01-02"))
-
-			// Create station selection keyboard
-			keyboard := tgbotapi.NewReplyKeyboard(
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Toshkent"),
-					tgbotapi.NewKeyboardButton("Samarqand"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Buxoro"),
-					tgbotapi.NewKeyboardButton("Andijon"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Qarshi"),
-					tgbotapi.NewKeyboardButton("Termiz"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Nukus"),
-					tgbotapi.NewKeyboardButton("Xiva"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Jizzax"),
-					tgbotapi.NewKeyboardButton("Navoiy"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Namangan"),
-					tgbotapi.NewKeyboardButton("Margilon"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Qo'qon"),
-					tgbotapi.NewKeyboardButton("Guliston"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("Urgench"),
-					tgbotapi.NewKeyboardButton("Pop"),
-				),
-				tgbotapi.NewKeyboardButtonRow(
-					tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-				),
-			)
-			keyboard.ResizeKeyboard = true
-			keyboard.OneTimeKeyboard = false
+			// Round trips and alerts ask for the way back first
+			if userState.Mode == modeRoundTrip || userState.Mode == modeAlert {
+				userState.CurrentStep = stepSelectReturnDate
+			}
+			b.saveUserState(chatID, userState)
 
 			// Edit the existing calendar message to show just the date confirmation
-			editMsg := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID, text)
+			editMsg := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID,
+				l.T("calendar.date_selected", selectedDate.Format("2006-01-02")))
 			// Remove Markdown parsing to avoid formatting errors
 			b.safeSendEdit(editMsg)
 
+			if userState.CurrentStep == stepSelectReturnDate {
+				b.showCalendar(chatID, selectedDate)
+				return
+			}
+
 			// Send a separate message with the station selection prompt and keyboard
-			msg := tgbotapi.NewMessage(chatID, "Please select your departure station:")
-			msg.ReplyMarkup = keyboard
-			b.safeSend(msg)
+			b.promptDepartureStation(chatID, l)
 		}
 	}
 }
 
 func (b *Bot) handleViewStationsButton(chatID int64) {
-	// Show all 16 stations in a nice format
-	response := `🚉 *Available Railway Stations (16 total):*
-
-*Major Cities:*
-🇺🇿 **Toshkent** - Capital city
-🇺🇿 **Samarqand** - Historic center
-🇺🇿 **Buxoro** - Ancient city
-🇺🇿 **Andijon** - Eastern hub
-🇺🇿 **Qarshi** - Southern center
-🇺🇿 **Termiz** - Southern border
-🇺🇿 **Nukus** - Karakalpakstan
-🇺🇿 **Xiva** - Historic oasis
-
-*Regional Centers:*
-🇺🇿 **Jizzax** - Central region
-🇺🇿 **Navoiy** - Central mining
-🇺🇿 **Namangan** - Fergana Valley
-🇺🇿 **Margilon** - Silk city
-🇺🇿 **Qo'qon** - Fergana hub
-🇺🇿 **Guliston** - Sirdaryo region
-🇺🇿 **Urgench** - Khorezm center
-🇺🇿 **Pop** - Namangan region
-
-💡 *All stations support train connections!*`
-
-	// Use ReplyKeyboard for consistency
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-		),
-	)
-	keyboard.ResizeKeyboard = true
-	keyboard.OneTimeKeyboard = false
+	l := b.localizer(chatID)
 
-	msg := tgbotapi.NewMessage(chatID, response)
+	msg := tgbotapi.NewMessage(chatID, l.T("stations.overview"))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = backKeyboard(l)
 	b.safeSend(msg)
 }
 
 func (b *Bot) handleChangeLanguageButton(chatID int64) {
-	text := `🌍 *Change Language*
-
-Choose your preferred language for the bot interface:`
+	l := b.localizer(chatID)
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🇺🇿 O'zbekcha"),
-			tgbotapi.NewKeyboardButton("🇷🇺 Русский"),
+			tgbotapi.NewKeyboardButton(languageButtonUzbek),
+			tgbotapi.NewKeyboardButton(languageButtonUzbekCyrillic),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🇺🇸 English"),
+			tgbotapi.NewKeyboardButton(languageButtonRussian),
+			tgbotapi.NewKeyboardButton(languageButtonEnglish),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
+			tgbotapi.NewKeyboardButton(l.T(buttonBack)),
 		),
 	)
 	keyboard.ResizeKeyboard = true
 	keyboard.OneTimeKeyboard = false
 
-	msg := tgbotapi.NewMessage(chatID, text)
+	msg := tgbotapi.NewMessage(chatID, l.T("language.prompt"))
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
 	b.safeSend(msg)
 }
 
 func (b *Bot) handleHelpButton(chatID int64) {
-	helpText := `🚂 *ChiptaTop Train Bot Help*
+	l := b.localizer(chatID)
 
-🔍 *How to Use:*
-• Use the buttons below to navigate
-• Search for trains between any stations
-• View available dates and times
-• Change language as needed
-
-📋 *Available Options:*
-• Search Trains - Find trains for today
-• Search by Date - Find trains for specific date
-• View Stations - See all available stations
-• Change Language - Switch between Uzbek/Russian/English
-
-💡 *Tips:*
-• All major cities are supported
-• Results show available seats and prices
-• Automatic language detection`
-
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-		),
-	)
-	keyboard.ResizeKeyboard = true
-	keyboard.OneTimeKeyboard = false
-
-	msg := tgbotapi.NewMessage(chatID, helpText)
+	msg := tgbotapi.NewMessage(chatID, l.T("help.text"))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = backKeyboard(l)
 	b.safeSend(msg)
 }
 
 func (b *Bot) handleMainMenuButton(chatID int64) {
+	// Leaving to the main menu abandons any flow in progress
+	b.resetUserState(chatID)
+
 	// Send the main menu again
 	b.handleStartCommand(tgbotapi.Update{
 		Message: &tgbotapi.Message{
@@ -834,10 +954,11 @@ func (b *Bot) handleMainMenuButton(chatID int64) {
 }
 
 func (b *Bot) handleSearchRequest(chatID int64, from, to string, date time.Time) {
+	l := b.localizer(chatID)
+
 	// Send "searching" message
-	searchingMsg := tgbotapi.NewMessage(chatID,
-		fmt.Sprintf("🔍 Searching trains from %s to %s on %s...",
-			from, to, date.Format("2006-01-02")))
+	searchingMsg := tgbotapi.NewMessage(chatID, l.T("search.searching",
+		b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")))
 	// Remove Markdown parsing to avoid formatting errors
 	b.safeSend(searchingMsg)
 
@@ -846,35 +967,18 @@ func (b *Bot) handleSearchRequest(chatID int64, from, to string, date time.Time)
 	defer cancel()
 
 	searchParams := train.TrainSearchParams{
-		From: from,
-		To:   to,
-		Date: date,
+		From:     from,
+		To:       to,
+		Date:     date,
+		Language: i18n.APILanguage(l.Locale()),
 	}
 
-	// Get all trains from API response with retry logic
-	response, err := b.searchTrainsWithRetry(ctx, searchParams)
+	// Get all trains from API response; the client retries transient failures
+	response, err := b.trainService.SearchTrains(ctx, searchParams)
 	if err != nil {
-		log.Printf("Train search error after retries: %v", err)
-
-		var errorMsg string
-		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "CSRF") {
-			errorMsg = "❌ Authentication Error\n\n" +
-				"Unable to authenticate with railway service. Please try again later.\n\n" +
-				"If this problem persists, the railway service may be temporarily unavailable."
-		} else if strings.Contains(err.Error(), "failed to search trains") {
-			errorMsg = "❌ Search Failed\n\n" +
-				"Could not connect to railway service after multiple attempts. This might be because:\n" +
-				"• Network connection issues\n" +
-				"• Railway service is temporarily unavailable\n" +
-				"• High server load\n\n" +
-				"Please try again in a few moments."
-		} else {
-			errorMsg = "❌ Search Error\n\n" +
-				"An unexpected error occurred while searching for trains. Please try again later."
-		}
+		log.Printf("Train search error: %v", err)
 
-		msg := tgbotapi.NewMessage(chatID, errorMsg)
-		b.safeSend(msg)
+		b.sendSearchError(chatID, searchParams, err, l)
 		return
 	}
 
@@ -889,124 +993,126 @@ func (b *Bot) handleSearchRequest(chatID int64, from, to string, date time.Time)
 		// Reset user state since search is complete
 		b.resetUserState(chatID)
 
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("❌ No available trains found from *%s* to *%s* on *%s*.\n\n"+
-				"Try:\n• Different dates\n• Alternative station names\n• Use the View Stations button to see available stations",
-				from, to, date.Format("2006-01-02")))
+		msg := tgbotapi.NewMessage(chatID, l.T("search.no_trains_menu",
+			b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")))
 		msg.ParseMode = "Markdown"
 
 		// Send main menu
-		keyboard := tgbotapi.NewReplyKeyboard(
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("🔍 Search Trains"),
-				tgbotapi.NewKeyboardButton("📅 Search by Date"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("🚉 View Stations"),
-				tgbotapi.NewKeyboardButton("🌍 Change Language"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("❓ Help"),
-			),
-		)
-		keyboard.ResizeKeyboard = true
-		keyboard.OneTimeKeyboard = false
-		msg.ReplyMarkup = keyboard
+		msg.ReplyMarkup = mainMenuKeyboard(l)
 
 		b.safeSend(msg)
+
+		// There may be no direct train on this route; try changing at a hub
+		b.suggestConnections(chatID, from, to, date)
 		return
 	}
 
 	// Format search results
-	results := b.trainService.FormatSearchResults(trains)
+	results := b.trainService.FormatSearchResults(trains, l.Locale())
 
 	// Send results with main menu
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔍 Search Trains"),
-			tgbotapi.NewKeyboardButton("📅 Search by Date"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🚉 View Stations"),
-			tgbotapi.NewKeyboardButton("🌍 Change Language"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("❓ Help"),
-		),
-	)
-	keyboard.ResizeKeyboard = true
-	keyboard.OneTimeKeyboard = false
-
 	msg := tgbotapi.NewMessage(chatID, results)
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = mainMenuKeyboard(l)
 	b.safeSend(msg)
 }
 
-func (b *Bot) handleLanguageChange(chatID int64, language string) {
-	// Change the train service language
-	b.trainService.SetLanguage(language)
-
-	var text string
-	switch language {
-	case "uz":
-		text = "🇺🇿 *Til o'zgartirildi!*\n\nO'zbek tiliga o'tkazildi. Endi barcha API so'rovlari o'zbek tilida bo'ladi."
-	case "ru":
-		text = "🇷🇺 *Язык изменен!*\n\nПереключено на русский язык. Теперь все API запросы будут на русском языке."
-	case "en":
-		text = "🇺🇸 *Language changed!*\n\nSwitched to English. Now all API requests will be in English."
-	default:
-		text = "❌ Unknown language"
+// sendSearchError tells the user why a search failed. While the railway
+// service is down the last trains found for the search are shown instead,
+// with their age.
+func (b *Bot) sendSearchError(chatID int64, params train.TrainSearchParams, err error, l *i18n.Localizer) {
+	var downErr *train.UnavailableError
+	if errors.As(err, &downErr) {
+		if trains, fetchedAt, ok := b.trainService.LastAvailableTrains(params); ok && len(trains) > 0 {
+			minutes := max(1, int(time.Since(fetchedAt)/time.Minute))
+			results := b.trainService.FormatSearchResults(trains, l.Locale())
+			b.sendLongMessage(chatID, l.N("search.stale_results", minutes, minutes)+results)
+			return
+		}
 	}
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 Back to Main Menu", "main_menu"),
-		),
+	msg := tgbotapi.NewMessage(chatID, searchErrorMessage(err, l))
+	b.safeSend(msg)
+}
+
+// searchErrorMessage returns the user-facing message for a failed search
+func searchErrorMessage(err error, l *i18n.Localizer) string {
+	var (
+		stationErr  *train.StationError
+		downErr     *train.UnavailableError
+		authErr     *train.AuthError
+		rateErr     *train.RateLimitError
+		timeoutErr  *train.TimeoutError
+		networkErr  *train.NetworkError
+		upstreamErr *train.UpstreamError
 	)
 
-	msg := tgbotapi.NewMessage(chatID, text)
+	switch {
+	case errors.As(err, &stationErr):
+		return stationErrorMessage(stationErr, l)
+	case errors.As(err, &downErr):
+		// Checked before the failure it wraps
+		return l.T("error.service_down")
+	case errors.As(err, &authErr):
+		return l.T("error.auth")
+	case errors.As(err, &rateErr):
+		return l.T("error.rate_limited")
+	case errors.As(err, &timeoutErr), errors.Is(err, context.DeadlineExceeded):
+		return l.T("error.timeout")
+	case errors.As(err, &networkErr), errors.As(err, &upstreamErr):
+		return l.T("error.search_failed")
+	default:
+		return l.T("error.search_unexpected")
+	}
+}
+
+func (b *Bot) handleLanguageChange(chatID int64, locale string) {
+	// Language is a per-user preference used for this user's interface and API requests only
+	prefs, err := b.store.GetPreferences(chatID)
+	if err != nil {
+		log.Printf("Failed to load preferences for chat %d: %v", chatID, err)
+	}
+	prefs.Language = locale
+	if err := b.store.SavePreferences(chatID, prefs); err != nil {
+		log.Printf("Failed to save preferences for chat %d: %v", chatID, err)
+	}
+
+	// Confirm in the newly selected language and refresh the menu keyboard
+	l := i18n.New(locale)
+
+	msg := tgbotapi.NewMessage(chatID, l.T("language.changed"))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = mainMenuKeyboard(l)
 	b.safeSend(msg)
 }
 
 func (b *Bot) handleStationsCommand(update tgbotapi.Update) {
-	stations := b.trainService.GetStationSuggestions("")
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
 
 	var response strings.Builder
-	response.WriteString("🚉 *Available Railway Stations:*\n\n")
+	response.WriteString(l.T("stations.list_header"))
 
+	stations := b.trainService.Stations().KeyboardStations()
 	for i, station := range stations {
-		response.WriteString(fmt.Sprintf("• %s", station))
+		response.WriteString(fmt.Sprintf("• %s", station.LocalizedName(l.Locale())))
 		if i < len(stations)-1 {
 			response.WriteString("\n")
 		}
 	}
 
-	response.WriteString("\n\n💡 Use these names in your search requests!")
+	response.WriteString(l.T("stations.list_footer"))
 
-	// Add back button
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🔙 Back to Main Menu"),
-		),
-	)
-	keyboard.ResizeKeyboard = true
-	keyboard.OneTimeKeyboard = false
-
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, response.String())
+	msg := tgbotapi.NewMessage(chatID, response.String())
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = backKeyboard(l)
 	b.safeSend(msg)
 }
 
 func (b *Bot) handleSearchCommand(update tgbotapi.Update) {
 	args := strings.Fields(update.Message.CommandArguments())
 	if len(args) < 2 {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"❌ Please provide departure and arrival stations.\n\n"+
-				"Example: `/search Toshkent Samarqand`")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.localizer(update.Message.Chat.ID).T("search.usage"))
 		msg.ParseMode = "Markdown"
 		b.safeSend(msg)
 		return
@@ -1014,17 +1120,17 @@ func (b *Bot) handleSearchCommand(update tgbotapi.Update) {
 
 	from := args[0]
 	to := args[1]
-	date := time.Now()
+	date := train.Today()
 
 	b.performTrainSearch(update.Message.Chat.ID, from, to, date)
 }
 
 func (b *Bot) handleSearchDateCommand(update tgbotapi.Update) {
+	l := b.localizer(update.Message.Chat.ID)
+
 	args := strings.Fields(update.Message.CommandArguments())
 	if len(args) < 3 {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"❌ Please provide departure, arrival stations and date.\n\n"+
-				"Example: `/search_date Toshkent Samarqand 2025-01-15`")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("search_date.usage"))
 		msg.ParseMode = "Markdown"
 		b.safeSend(msg)
 		return
@@ -1036,9 +1142,7 @@ func (b *Bot) handleSearchDateCommand(update tgbotapi.Update) {
 
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"❌ Invalid date format. Please use YYYY-MM-DD format.\n\n"+
-				"Example: `2025-01-15`")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, l.T("error.invalid_date"))
 		msg.ParseMode = "Markdown"
 		b.safeSend(msg)
 		return
@@ -1048,10 +1152,11 @@ func (b *Bot) handleSearchDateCommand(update tgbotapi.Update) {
 }
 
 func (b *Bot) performTrainSearch(chatID int64, from, to string, date time.Time) {
+	l := b.localizer(chatID)
+
 	// Send "searching" message
-	searchingMsg := tgbotapi.NewMessage(chatID,
-		fmt.Sprintf("🔍 Searching trains from %s to %s on %s...",
-			from, to, date.Format("2006-01-02")))
+	searchingMsg := tgbotapi.NewMessage(chatID, l.T("search.searching",
+		b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")))
 	// Remove Markdown parsing to avoid formatting errors
 	b.safeSend(searchingMsg)
 
@@ -1060,50 +1165,33 @@ func (b *Bot) performTrainSearch(chatID int64, from, to string, date time.Time)
 	defer cancel()
 
 	searchParams := train.TrainSearchParams{
-		From: from,
-		To:   to,
-		Date: date,
+		From:     from,
+		To:       to,
+		Date:     date,
+		Language: i18n.APILanguage(l.Locale()),
 	}
 
-	trains, err := b.findAvailableTrainsWithRetry(ctx, searchParams)
+	trains, err := b.trainService.FindAvailableTrains(ctx, searchParams)
 	if err != nil {
-		log.Printf("Train search error after retries: %v", err)
-
-		var errorMsg string
-		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "CSRF") {
-			errorMsg = "❌ Authentication Error\n\n" +
-				"Unable to authenticate with railway service. Please try again later.\n\n" +
-				"If this problem persists, the railway service may be temporarily unavailable."
-		} else if strings.Contains(err.Error(), "failed to search trains") {
-			errorMsg = "❌ Search Failed\n\n" +
-				"Could not connect to railway service after multiple attempts. This might be because:\n" +
-				"• Network connection issues\n" +
-				"• Railway service is temporarily unavailable\n" +
-				"• High server load\n\n" +
-				"Please try again in a few moments."
-		} else {
-			errorMsg = "❌ Search Error\n\n" +
-				"An unexpected error occurred while searching for trains. Please try again later."
-		}
+		log.Printf("Train search error: %v", err)
 
-		msg := tgbotapi.NewMessage(chatID, errorMsg)
-		b.safeSend(msg)
+		b.sendSearchError(chatID, searchParams, err, l)
 		return
 	}
 
 	// Format and send results
 	if len(trains) == 0 {
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("❌ No available trains found from *%s* to *%s* on *%s*.\n\n"+
-				"Try:\n• Different dates\n• Alternative station names\n• Use /stations to see available stations",
-				from, to, date.Format("2006-01-02")))
+		msg := tgbotapi.NewMessage(chatID, l.T("search.no_trains_command",
+			b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")))
 		msg.ParseMode = "Markdown"
 		b.safeSend(msg)
+
+		b.suggestConnections(chatID, from, to, date)
 		return
 	}
 
 	// Send results (split if too long)
-	results := b.trainService.FormatSearchResults(trains)
+	results := b.trainService.FormatSearchResults(trains, l.Locale())
 	b.sendLongMessage(chatID, results)
 }
 
@@ -1174,115 +1262,3 @@ func (b *Bot) safeSendEdit(msg tgbotapi.EditMessageTextConfig) {
 		time.Sleep(200 * time.Millisecond)
 	}
 }
-
-// searchTrainsWithRetry performs train search with automatic retry logic
-func (b *Bot) searchTrainsWithRetry(ctx context.Context, params train.TrainSearchParams) (*train.SearchTrainsResponse, error) {
-	const maxRetries = 3
-	var lastErr error
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		// Log retry attempt
-		if attempt > 1 {
-			log.Printf("Retrying train search (attempt %d/%d)...", attempt, maxRetries)
-		}
-
-		// Perform the search
-		response, err := b.trainService.SearchTrains(ctx, params)
-		if err == nil {
-			// Success - return the response
-			if attempt > 1 {
-				log.Printf("Train search succeeded on attempt %d", attempt)
-			}
-			return response, nil
-		}
-
-		lastErr = err
-
-		// Don't retry on authentication errors (403/CSRF) - these won't be fixed by retrying
-		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "CSRF") {
-			log.Printf("Authentication error, not retrying: %v", err)
-			break
-		}
-
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			log.Printf("Context cancelled, not retrying: %v", ctx.Err())
-			break
-		}
-
-		// If this is the last attempt, don't sleep
-		if attempt == maxRetries {
-			break
-		}
-
-		// Calculate delay with exponential backoff: 1s, 2s, 4s
-		delay := time.Duration(attempt) * time.Second
-		log.Printf("Search failed (attempt %d/%d), retrying in %v: %v", attempt, maxRetries, delay, err)
-
-		// Wait before retrying
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(delay):
-			continue
-		}
-	}
-
-	return nil, fmt.Errorf("failed to search trains after %d attempts: %w", maxRetries, lastErr)
-}
-
-// findAvailableTrainsWithRetry performs available trains search with automatic retry logic
-func (b *Bot) findAvailableTrainsWithRetry(ctx context.Context, params train.TrainSearchParams) ([]train.Train, error) {
-	const maxRetries = 3
-	var lastErr error
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		// Log retry attempt
-		if attempt > 1 {
-			log.Printf("Retrying available trains search (attempt %d/%d)...", attempt, maxRetries)
-		}
-
-		// Perform the search
-		trains, err := b.trainService.FindAvailableTrains(ctx, params)
-		if err == nil {
-			// Success - return the trains
-			if attempt > 1 {
-				log.Printf("Available trains search succeeded on attempt %d", attempt)
-			}
-			return trains, nil
-		}
-
-		lastErr = err
-
-		// Don't retry on authentication errors (403/CSRF) - these won't be fixed by retrying
-		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "CSRF") {
-			log.Printf("Authentication error, not retrying: %v", err)
-			break
-		}
-
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			log.Printf("Context cancelled, not retrying: %v", ctx.Err())
-			break
-		}
-
-		// If this is the last attempt, don't sleep
-		if attempt == maxRetries {
-			break
-		}
-
-		// Calculate delay with exponential backoff: 1s, 2s, 4s
-		delay := time.Duration(attempt) * time.Second
-		log.Printf("Available trains search failed (attempt %d/%d), retrying in %v: %v", attempt, maxRetries, delay, err)
-
-		// Wait before retrying
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(delay):
-			continue
-		}
-	}
-
-	return nil, fmt.Errorf("failed to find available trains after %d attempts: %w", maxRetries, lastErr)
-}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/config"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+)
+
+// trainOn returns an Afrosiyob from Toshkent to Samarqand running on date
+func trainOn(date time.Time, number string) train.Train {
+	day := date.Format("02.01.2006")
+	return train.Train{
+		Type:          "СКРСТ",
+		Number:        number,
+		DepartureDate: day + " 06:03",
+		ArrivalDate:   day + " 08:21",
+		TimeOnWay:     "02:18",
+		Brand:         "Afrosiyob",
+		SubRoute: train.SubRoute{
+			DepStationName: "TOSHKENT",
+			DepStationCode: "2900000",
+			ArvStationName: "SAMARQAND",
+			ArvStationCode: "2900700",
+		},
+		Cars: []train.Car{{
+			Type:      "O'rindiqli",
+			FreeSeats: 42,
+			Tariffs:   []train.Tariff{{ClassServiceType: "2Е", FreeSeats: 42, Tariff: 270000}},
+		}},
+	}
+}
+
+func TestSearchByDateConversation(t *testing.T) {
+	c := newConversation(t)
+
+	now := train.Today()
+	tomorrow := now.AddDate(0, 0, 1)
+	date := tomorrow.Format("2006-01-02")
+	c.railway.SetTrains("2900000", "2900700", date, trainOn(tomorrow, "778Ф"))
+
+	c.say("/start")
+	menu := c.expect("start.welcome")
+	if !menu.HasButton(c.l.T(buttonSearchByDate)) {
+		t.Fatalf("main menu %v lacks the search by date button", menu.Buttons())
+	}
+
+	c.tap(buttonSearchByDate)
+	calendar := c.expect("calendar.title", c.l.T(fmt.Sprintf("month.%d", now.Month())), now.Year())
+
+	calendar = c.pickDate(calendar, tomorrow)
+	if selected := c.expect("calendar.date_selected", date); !selected.IsEdit() || selected.MessageID != calendar.MessageID {
+		t.Errorf("date selection did not replace the calendar")
+	}
+	departure := c.expect("station.select_departure")
+	if departure.Keyboard == nil {
+		t.Fatalf("departure prompt has no station keyboard")
+	}
+
+	c.say("Toshkent")
+	c.expect("station.departure_selected", c.station("Toshkent"))
+
+	c.say("Samarqand")
+	c.expect("search.confirmation", c.station("Toshkent"), c.station("Samarqand"), date)
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), date)
+	results := c.expectText("778Ф")
+	if !results.HasButton(c.l.T(buttonSearchByDate)) {
+		t.Errorf("results are not followed by the main menu")
+	}
+
+	if got := len(c.telegram.AnsweredCallbacks()); got < 1 {
+		t.Errorf("bot answered %d callback queries, want every tap answered", got)
+	}
+	if got := c.railway.SearchRequests(); got != 1 {
+		t.Errorf("got %d railway searches, want 1", got)
+	}
+}
+
+func TestSearchTrainsLooksAtToday(t *testing.T) {
+	c := newConversation(t)
+
+	today := train.Today()
+	date := today.Format("2006-01-02")
+	c.railway.SetTrains("2900000", "2900700", date, trainOn(today, "760Ф"))
+
+	c.say("/start")
+	c.expect("start.welcome")
+
+	c.tap(buttonSearchTrains)
+	if prompt := c.expect("search.today_prompt"); prompt.Keyboard == nil {
+		t.Fatalf("today prompt has no station keyboard")
+	}
+
+	c.say("Toshkent")
+	c.expect("station.departure_selected", c.station("Toshkent"))
+
+	c.say("Samarqand")
+	c.expect("search.confirmation", c.station("Toshkent"), c.station("Samarqand"), date)
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), date)
+	c.expectText("760Ф")
+}
+
+func TestUnknownStationIsNotSearched(t *testing.T) {
+	c := newConversation(t)
+
+	c.say("/start")
+	c.expect("start.welcome")
+
+	c.tap(buttonSearchByDate)
+	tomorrow := train.Today().AddDate(0, 0, 1)
+	c.pickDate(c.expectText(""), tomorrow)
+	c.expect("calendar.date_selected", tomorrow.Format("2006-01-02"))
+	c.expect("station.select_departure")
+
+	c.say("Atlantis")
+	if reply := c.expectText(""); reply.Keyboard == nil && reply.InlineKeyboard == nil {
+		t.Errorf("unknown station answered without a way to pick another: %q", reply.Text)
+	}
+	if got := c.railway.SearchRequests(); got != 0 {
+		t.Errorf("got %d railway searches for an unknown station, want 0", got)
+	}
+}
+
+func TestShutdownFinishesInFlightSearch(t *testing.T) {
+	c := newConversation(t)
+
+	tomorrow := train.Today().AddDate(0, 0, 1)
+	date := tomorrow.Format("2006-01-02")
+	c.railway.SetTrains("2900000", "2900700", date, trainOn(tomorrow, "778Ф"))
+	c.railway.QueueSearchReplies(railwaytest.Reply{Delay: 300 * time.Millisecond})
+
+	c.say("/search_date Toshkent Samarqand " + date)
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), date)
+
+	// Asked to stop while railway.uz is still answering
+	if err := c.shutdown(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	c.expectText("778Ф")
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	c := newConversation(t, func(cfg *config.Config) {
+		cfg.ShutdownTimeout = 50 * time.Millisecond
+	})
+	c.railway.QueueSearchReplies(railwaytest.Reply{Delay: time.Second})
+
+	date := train.Today().AddDate(0, 0, 1).Format("2006-01-02")
+	c.say("/search_date Toshkent Samarqand " + date)
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), date)
+
+	if err := c.shutdown(); err == nil {
+		t.Fatalf("Run returned nil, want a shutdown timeout")
+	}
+}
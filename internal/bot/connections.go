@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleConnectCommand handles /connect from to [YYYY-MM-DD]
+func (b *Bot) handleConnectCommand(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	args := strings.Fields(update.Message.CommandArguments())
+	if len(args) < 2 {
+		msg := tgbotapi.NewMessage(chatID, l.T("connect.usage"))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return
+	}
+
+	date := train.Today()
+	if len(args) > 2 {
+		var err error
+		date, err = time.Parse("2006-01-02", args[2])
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, l.T("error.invalid_date"))
+			msg.ParseMode = "Markdown"
+			b.safeSend(msg)
+			return
+		}
+	}
+
+	b.suggestConnections(chatID, b.canonicalStationName(args[0]), b.canonicalStationName(args[1]), date)
+}
+
+// suggestConnections plans journeys with a change of trains at a hub station
+// and sends the best itineraries
+func (b *Bot) suggestConnections(chatID int64, from, to string, date time.Time) {
+	l := b.localizer(chatID)
+
+	searchingMsg := tgbotapi.NewMessage(chatID, l.T("connect.searching",
+		b.stationDisplayName(from, l), b.stationDisplayName(to, l)))
+	b.safeSend(searchingMsg)
+
+	// Every hub adds a search for each leg, so allow more time than a direct search
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	itineraries, err := b.trainService.PlanJourney(ctx, train.TrainSearchParams{
+		From:     from,
+		To:       to,
+		Date:     date,
+		Language: i18n.APILanguage(l.Locale()),
+	}, train.JourneyOptions{MinTransfer: b.cfg.MinTransferTime})
+	if err != nil {
+		log.Printf("Journey planner error: %v", err)
+
+		msg := tgbotapi.NewMessage(chatID, searchErrorMessage(err, l))
+		b.safeSend(msg)
+		return
+	}
+
+	if len(itineraries) == 0 {
+		msg := tgbotapi.NewMessage(chatID, l.T("connect.none",
+			b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return
+	}
+
+	text := l.T("connect.header", b.stationDisplayName(from, l), b.stationDisplayName(to, l), date.Format("2006-01-02")) +
+		b.trainService.FormatItineraries(itineraries, l.Locale())
+	b.sendLongMessage(chatID, text)
+}
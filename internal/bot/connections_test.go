@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+func TestConnectSuggestsChangeAtHub(t *testing.T) {
+	c := newConversation(t)
+
+	date := train.Today().AddDate(0, 0, 2)
+	day := date.Format("02.01.2006")
+	first := trainOn(date, "056Ф")
+	first.DepartureDate, first.ArrivalDate = day+" 02:00", day+" 10:00"
+	second := trainOn(date, "766Ф")
+	second.DepartureDate, second.ArrivalDate = day+" 11:00", day+" 12:30"
+
+	// No direct train; Nukus to Buxoro and on to Samarqand an hour later
+	c.railway.SetTrains("2900970", "2900800", date.Format("2006-01-02"), first)
+	c.railway.SetTrains("2900800", "2900700", date.Format("2006-01-02"), second)
+
+	c.say("/connect Nukus Samarqand " + date.Format("2006-01-02"))
+	c.expect("connect.searching", c.station("Nukus"), c.station("Samarqand"))
+	results := c.expect("connect.header", c.station("Nukus"), c.station("Samarqand"), date.Format("2006-01-02"))
+	for _, want := range []string{"056Ф", "766Ф", c.station("Buxoro")} {
+		if !strings.Contains(results.Text, want) {
+			t.Errorf("connections %q lack %s", results.Text, want)
+		}
+	}
+}
+
+func TestConnectWithoutConnections(t *testing.T) {
+	c := newConversation(t)
+
+	c.say("/connect Nukus")
+	c.expect("connect.usage")
+
+	today := train.Today()
+	c.say("/connect Nukus Samarqand")
+	c.expect("connect.searching", c.station("Nukus"), c.station("Samarqand"))
+	c.expect("connect.none", c.station("Nukus"), c.station("Samarqand"), today.Format("2006-01-02"))
+}
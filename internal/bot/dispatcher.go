@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Default worker pool settings
+const (
+	defaultWorkers   = 8
+	defaultQueueSize = 64
+)
+
+// dispatcher processes updates concurrently on a fixed pool of workers.
+// Every chat is pinned to a single worker, so updates from different chats
+// run in parallel while updates from the same chat stay strictly ordered.
+type dispatcher struct {
+	queues []chan tgbotapi.Update
+	handle func(tgbotapi.Update)
+	wg     sync.WaitGroup
+}
+
+// newDispatcher creates a dispatcher with the given number of workers, each
+// with a bounded queue of queueSize updates
+func newDispatcher(workers, queueSize int, handle func(tgbotapi.Update)) *dispatcher {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	d := &dispatcher{
+		queues: make([]chan tgbotapi.Update, workers),
+		handle: handle,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+	}
+
+	return d
+}
+
+// Start launches the worker goroutines
+func (d *dispatcher) Start() {
+	for i, queue := range d.queues {
+		d.wg.Add(1)
+		go d.work(i, queue)
+	}
+}
+
+// Dispatch queues an update on the worker owning its chat. When the queue is
+// full it blocks until there is room or the context is cancelled, which slows
+// down update polling instead of dropping updates.
+func (d *dispatcher) Dispatch(ctx context.Context, update tgbotapi.Update) error {
+	queue := d.queues[d.shard(update)]
+
+	select {
+	case queue <- update:
+		return nil
+	default:
+	}
+
+	log.Printf("Update queue full, waiting for workers (update %d)", update.UpdateID)
+
+	select {
+	case queue <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop closes the queues and waits for workers to finish queued updates.
+// Dispatch must not be called after Stop.
+func (d *dispatcher) Stop() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
+
+func (d *dispatcher) work(id int, queue <-chan tgbotapi.Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		d.process(id, update)
+	}
+}
+
+// process handles one update, recovering from panics so a single bad
+// update cannot take down the worker
+func (d *dispatcher) process(id int, update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("worker %d: panic while handling update %d: %v\n%s", id, update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	d.handle(update)
+}
+
+// shard returns the worker index for the chat the update belongs to
+func (d *dispatcher) shard(update tgbotapi.Update) int {
+	var key int64
+	if chat := update.FromChat(); chat != nil {
+		key = chat.ID
+	} else if user := update.SentFrom(); user != nil {
+		key = user.ID
+	}
+
+	// Group chat IDs are negative
+	return int(uint64(key) % uint64(len(d.queues)))
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// chatUpdate returns a text message update from the chat
+func chatUpdate(id int, chatID int64) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: id,
+		Message:  &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}, Text: "hi"},
+	}
+}
+
+func TestDispatcherShard(t *testing.T) {
+	d := newDispatcher(4, 1, nil)
+
+	tests := []struct {
+		name   string
+		update tgbotapi.Update
+		want   int
+	}{
+		{"private chat", chatUpdate(1, 1001), 1},
+		{"group chat", chatUpdate(2, -1001), 3},
+		{"callback", tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			From:    &tgbotapi.User{ID: 7},
+			Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1002}},
+		}}, 2},
+		{"inline query", tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{From: &tgbotapi.User{ID: 7}}}, 3},
+		{"no chat", tgbotapi.Update{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.shard(tt.update)
+			if got != tt.want {
+				t.Errorf("shard() = %d, want %d", got, tt.want)
+			}
+			if again := d.shard(tt.update); again != got {
+				t.Errorf("shard() = %d then %d, want the same worker every time", got, again)
+			}
+		})
+	}
+}
+
+func TestDispatcherKeepsChatOrder(t *testing.T) {
+	const chats, perChat = 5, 50
+
+	var mu sync.Mutex
+	seen := make(map[int64][]int)
+	d := newDispatcher(3, 4, func(update tgbotapi.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		chatID := update.Message.Chat.ID
+		seen[chatID] = append(seen[chatID], update.UpdateID)
+	})
+	d.Start()
+
+	for i := 0; i < perChat; i++ {
+		for chat := int64(1); chat <= chats; chat++ {
+			if err := d.Dispatch(context.Background(), chatUpdate(i, chat)); err != nil {
+				t.Fatalf("Dispatch: %v", err)
+			}
+		}
+	}
+	d.Stop()
+
+	for chat := int64(1); chat <= chats; chat++ {
+		ids := seen[chat]
+		if len(ids) != perChat {
+			t.Fatalf("chat %d got %d updates, want %d", chat, len(ids), perChat)
+		}
+		for i, id := range ids {
+			if id != i {
+				t.Fatalf("chat %d got update %d in position %d, want updates in order", chat, id, i)
+			}
+		}
+	}
+}
+
+func TestDispatcherRecoversFromPanics(t *testing.T) {
+	var handled []int
+	d := newDispatcher(1, 4, func(update tgbotapi.Update) {
+		if update.UpdateID == 1 {
+			panic("bad update")
+		}
+		handled = append(handled, update.UpdateID)
+	})
+	d.Start()
+
+	for id := 1; id <= 3; id++ {
+		if err := d.Dispatch(context.Background(), chatUpdate(id, 1)); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+	d.Stop()
+
+	if len(handled) != 2 || handled[0] != 2 || handled[1] != 3 {
+		t.Errorf("handled updates %v, want [2 3] after the panic", handled)
+	}
+}
+
+func TestDispatchWaitsForRoom(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	d := newDispatcher(1, 1, func(tgbotapi.Update) {
+		started <- struct{}{}
+		<-release
+	})
+	d.Start()
+	defer d.Stop()
+	defer close(release)
+
+	// One update is being handled and one fills the queue
+	if err := d.Dispatch(context.Background(), chatUpdate(1, 1)); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	<-started
+	if err := d.Dispatch(context.Background(), chatUpdate(2, 1)); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Dispatch(ctx, chatUpdate(3, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Dispatch to a full queue = %v, want the context error", err)
+	}
+}
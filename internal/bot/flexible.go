@@ -0,0 +1,213 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// modeFlexible marks a user state that collects a flexible date search
+const modeFlexible = "flexible"
+
+// Days searched around the chosen date, and ahead of today when /flex has no date
+const (
+	flexDays     = 3
+	flexWeekDays = 6
+)
+
+// flexCallbackPrefix starts the callback data of a day in the price calendar:
+// flex|<from>|<to>|<YYYY-MM-DD>
+const flexCallbackPrefix = "flex|"
+
+// maxCallbackData is the Telegram limit for inline button callback data in bytes
+const maxCallbackData = 64
+
+// handleFlexibleDatesButton starts the flexible date flow: center date, then stations
+func (b *Bot) handleFlexibleDatesButton(chatID int64) {
+	l := b.localizer(chatID)
+
+	b.resetUserState(chatID)
+	userState := b.getUserState(chatID)
+	userState.Mode = modeFlexible
+	userState.CurrentStep = "select_date"
+	b.saveUserState(chatID, userState)
+
+	msg := tgbotapi.NewMessage(chatID, l.T("flex.prompt", flexDays))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+
+	b.showCalendar(chatID, train.Today())
+}
+
+// handleFlexCommand handles /flex from to [YYYY-MM-DD] [days]
+func (b *Bot) handleFlexCommand(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	args := strings.Fields(update.Message.CommandArguments())
+	if len(args) < 2 {
+		msg := tgbotapi.NewMessage(chatID, l.T("flex.usage"))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return
+	}
+
+	from := b.canonicalStationName(args[0])
+	to := b.canonicalStationName(args[1])
+
+	// Without a date, look at the coming week
+	if len(args) < 3 {
+		today := train.Today()
+		b.handleFlexibleSearch(chatID, from, to, today, today.AddDate(0, 0, flexWeekDays))
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", args[2])
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, l.T("error.invalid_date"))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return
+	}
+
+	if date.Before(train.Today()) {
+		msg := tgbotapi.NewMessage(chatID, l.T("calendar.past_date"))
+		b.safeSend(msg)
+		return
+	}
+
+	days := flexDays
+	if len(args) > 3 {
+		days, err = strconv.Atoi(args[3])
+		if err != nil || days < 0 || 2*days+1 > train.MaxRangeDays {
+			msg := tgbotapi.NewMessage(chatID, l.T("flex.invalid_days", (train.MaxRangeDays-1)/2))
+			b.safeSend(msg)
+			return
+		}
+	}
+
+	start, end := train.DateWindow(date, days)
+	b.handleFlexibleSearch(chatID, from, to, start, end)
+}
+
+// handleFlexibleSearch searches every day between start and end and replies
+// with the cheapest price per day and a calendar of the days with seats
+func (b *Bot) handleFlexibleSearch(chatID int64, from, to string, start, end time.Time) {
+	l := b.localizer(chatID)
+
+	// The searching message brings back the main menu; the results carry the price calendar
+	searchingMsg := tgbotapi.NewMessage(chatID, l.T("flex.searching",
+		b.stationDisplayName(from, l), b.stationDisplayName(to, l),
+		start.Format("2006-01-02"), end.Format("2006-01-02")))
+	searchingMsg.ReplyMarkup = mainMenuKeyboard(l)
+	b.safeSend(searchingMsg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	days, err := b.trainService.SearchDateRange(ctx, train.TrainSearchParams{
+		From:     from,
+		To:       to,
+		Date:     start,
+		Language: i18n.APILanguage(l.Locale()),
+	}, end)
+	if err != nil {
+		log.Printf("Date range search error: %v", err)
+
+		msg := tgbotapi.NewMessage(chatID, searchErrorMessage(err, l))
+		b.safeSend(msg)
+		return
+	}
+
+	if train.CheapestDay(days) == -1 {
+		msg := tgbotapi.NewMessage(chatID, l.T("flex.no_trains",
+			b.stationDisplayName(from, l), b.stationDisplayName(to, l),
+			start.Format("2006-01-02"), end.Format("2006-01-02")))
+		msg.ParseMode = "Markdown"
+		b.safeSend(msg)
+		return
+	}
+
+	text := l.T("flex.header", b.stationDisplayName(from, l), b.stationDisplayName(to, l)) +
+		b.trainService.FormatDateRangeResults(days, l.Locale()) +
+		l.T("flex.footer")
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = flexCalendarKeyboard(l, from, to, days)
+	b.safeSend(msg)
+}
+
+// flexCalendarKeyboard lays out the searched days as a calendar starting on Monday.
+// Days with seats can be tapped to see their trains; the cheapest day is starred.
+func flexCalendarKeyboard(l *i18n.Localizer, from, to string, days []train.DaySummary) tgbotapi.InlineKeyboardMarkup {
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+
+	var weekdayRow []tgbotapi.InlineKeyboardButton
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		weekdayRow = append(weekdayRow, tgbotapi.NewInlineKeyboardButtonData(l.T("weekday."+day), "header"))
+	}
+	keyboard = append(keyboard, weekdayRow)
+
+	cheapest := train.CheapestDay(days)
+	offset := (int(days[0].Date.Weekday()) + 6) % 7 // Monday = 0
+
+	for week := 0; week*7 < offset+len(days); week++ {
+		var row []tgbotapi.InlineKeyboardButton
+		for weekday := 0; weekday < 7; weekday++ {
+			i := week*7 + weekday - offset
+			if i < 0 || i >= len(days) {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(" ", "empty"))
+				continue
+			}
+
+			day := days[i]
+			if !day.HasSeats() {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("❌%d", day.Date.Day()), "empty"))
+				continue
+			}
+
+			text := fmt.Sprintf("✅%d", day.Date.Day())
+			if i == cheapest {
+				text = fmt.Sprintf("⭐%d", day.Date.Day())
+			}
+
+			data := flexCallbackPrefix + from + "|" + to + "|" + day.Date.Format("2006-01-02")
+			if len(data) > maxCallbackData {
+				data = "empty"
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(text, data))
+		}
+		keyboard = append(keyboard, row)
+	}
+
+	keyboard = append(keyboard, []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData(l.T(buttonBack), "main_menu"),
+	})
+
+	return tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+}
+
+// handleFlexDayCallback shows the trains of a day tapped in the price calendar
+func (b *Bot) handleFlexDayCallback(update tgbotapi.Update) {
+	chatID := update.CallbackQuery.Message.Chat.ID
+
+	parts := strings.Split(strings.TrimPrefix(update.CallbackQuery.Data, flexCallbackPrefix), "|")
+	if len(parts) != 3 {
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", parts[2])
+	if err != nil {
+		return
+	}
+
+	b.handleSearchRequest(chatID, parts[0], parts[1], date)
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// withPrice returns the train with every tariff at price
+func withPrice(t train.Train, price int) train.Train {
+	cars := make([]train.Car, len(t.Cars))
+	for i, car := range t.Cars {
+		car.Tariffs = append([]train.Tariff(nil), car.Tariffs...)
+		for j := range car.Tariffs {
+			car.Tariffs[j].Tariff = price
+		}
+		cars[i] = car
+	}
+	t.Cars = cars
+	return t
+}
+
+func TestFlexibleDatesConversation(t *testing.T) {
+	c := newConversation(t)
+
+	center := train.Today().AddDate(0, 0, 5)
+	start, end := center.AddDate(0, 0, -flexDays), center.AddDate(0, 0, flexDays)
+	pricey, cheap := center.AddDate(0, 0, -1), center.AddDate(0, 0, 1)
+	c.railway.SetTrains("2900000", "2900700", pricey.Format("2006-01-02"), trainOn(pricey, "778Ф"))
+	c.railway.SetTrains("2900000", "2900700", cheap.Format("2006-01-02"), withPrice(trainOn(cheap, "762Ф"), 150000))
+
+	c.say("/start")
+	c.expect("start.welcome")
+
+	c.tap(buttonFlexibleDates)
+	c.expect("flex.prompt", flexDays)
+	calendar := c.expect("calendar.title", c.l.T(fmt.Sprintf("month.%d", train.Today().Month())), train.Today().Year())
+	c.pickDate(calendar, center)
+	c.expect("calendar.date_selected", center.Format("2006-01-02"))
+	c.expect("station.select_departure")
+
+	c.say("Toshkent")
+	c.expect("station.departure_selected", c.station("Toshkent"))
+	c.say("Samarqand")
+	c.expect("flex.searching", c.station("Toshkent"), c.station("Samarqand"),
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	prices := c.expect("flex.header", c.station("Toshkent"), c.station("Samarqand"))
+	if got := c.railway.SearchRequests(); got != 2*flexDays+1 {
+		t.Errorf("got %d railway searches, want one per day (%d)", got, 2*flexDays+1)
+	}
+
+	// Only the days with seats can be tapped; the cheapest is starred
+	cheapDay := flexCallbackPrefix + "Toshkent|Samarqand|" + cheap.Format("2006-01-02")
+	if !prices.HasCallback(flexCallbackPrefix+"Toshkent|Samarqand|"+pricey.Format("2006-01-02")) || !prices.HasCallback(cheapDay) {
+		t.Fatalf("price calendar lacks the days with seats")
+	}
+	if prices.HasCallback(flexCallbackPrefix + "Toshkent|Samarqand|" + center.Format("2006-01-02")) {
+		t.Errorf("sold out day can be tapped")
+	}
+	if data, ok := prices.InlineButton(fmt.Sprintf("⭐%d", cheap.Day())); !ok || data != cheapDay {
+		t.Errorf("cheapest day is not starred, starred button leads to %q", data)
+	}
+
+	c.press(prices, cheapDay)
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), cheap.Format("2006-01-02"))
+	c.expectText("762Ф")
+}
+
+func TestFlexCommandLooksAtComingWeek(t *testing.T) {
+	c := newConversation(t)
+	today := train.Today()
+
+	c.say("/flex Toshkent Samarqand")
+	c.expect("flex.searching", c.station("Toshkent"), c.station("Samarqand"),
+		today.Format("2006-01-02"), today.AddDate(0, 0, flexWeekDays).Format("2006-01-02"))
+	c.expect("flex.no_trains", c.station("Toshkent"), c.station("Samarqand"),
+		today.Format("2006-01-02"), today.AddDate(0, 0, flexWeekDays).Format("2006-01-02"))
+
+	c.say("/flex Toshkent Samarqand " + today.AddDate(0, 0, -1).Format("2006-01-02"))
+	c.expect("calendar.past_date")
+}
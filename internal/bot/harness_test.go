@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/bot/telegramtest"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/config"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+)
+
+// replyTimeout is how long a conversation waits for the bot to answer
+const replyTimeout = 5 * time.Second
+
+// conversation is a running bot talking to fake Telegram and railway APIs.
+// Tests play the user in one private chat.
+type conversation struct {
+	t        *testing.T
+	telegram *telegramtest.Server
+	railway  *railwaytest.Server
+	bot      *Bot
+	chatID   int64
+	l        *i18n.Localizer // Localizer of the test user, English
+
+	cancel  context.CancelFunc // Stops the bot
+	stopped chan struct{}      // Closed when Run returned
+	runErr  error
+}
+
+// testConfig returns a config pointed at the fakes, keeping all files in dir
+func testConfig(telegram *telegramtest.Server, railway *railwaytest.Server, dir string) config.Config {
+	return config.Config{
+		TelegramBotToken:    telegramtest.Token,
+		TelegramAPIEndpoint: telegram.Endpoint(),
+		Environment:         "test",
+
+		RailwayBaseURL:   railway.BaseURL(),
+		RailwayBaseURLv1: railway.BaseURLv1(),
+		SessionPath:      filepath.Join(dir, "session.json"),
+
+		RetryMaxAttempts: 2,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    10 * time.Millisecond,
+
+		RequestRate:         1000,
+		RequestBurst:        1000,
+		MaxInFlightRequests: 100,
+
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+
+		SearchCacheTTL:     time.Minute,
+		StaleResultsMaxAge: time.Hour,
+
+		AlertCheckInterval: time.Hour,
+		AlertChecks:        true,
+		MinTransferTime:    45 * time.Minute,
+
+		StorageBackend: "memory",
+
+		StationsCachePath: filepath.Join(dir, "stations.json"),
+		StationsTTL:       time.Hour,
+
+		UpdateWorkers:   2,
+		UpdateQueueSize: 16,
+	}
+}
+
+// newConversation starts a bot against fresh fakes, with the config changed
+// by configure if given. The railway fake can be scripted before the user
+// says anything; the bot stops when the test ends.
+func newConversation(t *testing.T, configure ...func(*config.Config)) *conversation {
+	t.Helper()
+
+	telegram := telegramtest.NewServer()
+	railway := railwaytest.NewServer()
+
+	cfg := testConfig(telegram, railway, t.TempDir())
+	for _, change := range configure {
+		change(&cfg)
+	}
+
+	b, err := New(cfg)
+	if err != nil {
+		telegram.Close()
+		railway.Close()
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &conversation{
+		t:        t,
+		telegram: telegram,
+		railway:  railway,
+		bot:      b,
+		chatID:   1001,
+		l:        i18n.New(i18n.English),
+		cancel:   cancel,
+		stopped:  make(chan struct{}),
+	}
+	go func() {
+		defer close(c.stopped)
+		c.runErr = b.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		c.shutdown()
+		b.Close()
+		telegram.Close()
+		railway.Close()
+	})
+
+	return c
+}
+
+// shutdown stops the bot and returns what Run returned
+func (c *conversation) shutdown() error {
+	c.cancel()
+	<-c.stopped
+	return c.runErr
+}
+
+// station returns the name of a station of the bot as the user sees it
+func (c *conversation) station(name string) string {
+	c.t.Helper()
+	station := c.bot.trainService.Stations().ByName(name)
+	if station == nil {
+		c.t.Fatalf("no station %q", name)
+	}
+	return station.LocalizedName(c.l.Locale())
+}
+
+// say sends a text message as the user
+func (c *conversation) say(text string) {
+	c.telegram.SendText(c.chatID, text)
+}
+
+// tap presses a reply keyboard button as the user
+func (c *conversation) tap(key string) {
+	c.say(c.l.T(key))
+}
+
+// press taps an inline button of a message the bot sent
+func (c *conversation) press(message telegramtest.Sent, data string) {
+	c.t.Helper()
+	if !message.HasCallback(data) {
+		c.t.Fatalf("message %q has no button %q", message.Text, data)
+	}
+	c.telegram.Press(c.chatID, message.MessageID, data)
+}
+
+// pickDate presses a day on the calendar, paging to its month first. It
+// returns the calendar message the day was picked on.
+func (c *conversation) pickDate(calendar telegramtest.Sent, day time.Time) telegramtest.Sent {
+	c.t.Helper()
+	data := fmt.Sprintf("date_%d_%d_%d", day.Year(), day.Month(), day.Day())
+	if !calendar.HasCallback(data) {
+		c.press(calendar, fmt.Sprintf("month_%d_%d", day.Year(), day.Month()))
+		calendar = c.expect("calendar.title", c.l.T(fmt.Sprintf("month.%d", day.Month())), day.Year())
+		if !calendar.IsEdit() {
+			c.t.Errorf("month navigation sent a new calendar instead of editing it")
+		}
+	}
+	c.press(calendar, data)
+	return calendar
+}
+
+// expect returns the next message the bot sent or edited, failing the test
+// if it is not the one with the message key
+func (c *conversation) expect(key string, args ...interface{}) telegramtest.Sent {
+	c.t.Helper()
+	return c.expectText(c.l.T(key, args...))
+}
+
+// expectText returns the next message the bot sent or edited, failing the
+// test unless it contains text
+func (c *conversation) expectText(text string) telegramtest.Sent {
+	c.t.Helper()
+	sent, ok := c.telegram.Next(replyTimeout)
+	if !ok {
+		c.t.Fatalf("no reply from the bot, want %q", text)
+	}
+	if sent.ChatID != c.chatID {
+		c.t.Fatalf("reply sent to chat %d, want %d", sent.ChatID, c.chatID)
+	}
+	if !strings.Contains(sent.Text, text) {
+		c.t.Fatalf("got reply %q, want %q", sent.Text, text)
+	}
+	return sent
+}
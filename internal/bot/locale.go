@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// Message keys of reply keyboard buttons
+const (
+	buttonSearchTrains   = "button.search_trains"
+	buttonSearchByDate   = "button.search_by_date"
+	buttonRoundTrip      = "button.round_trip"
+	buttonFlexibleDates  = "button.flexible_dates"
+	buttonViewStations   = "button.view_stations"
+	buttonChangeLanguage = "button.change_language"
+	buttonHelp           = "button.help"
+	buttonBack           = "button.back"
+
+	// buttonNearestStation shares the user's location instead of sending text
+	buttonNearestStation = "button.nearest_station"
+)
+
+// menuButtons lists the buttons recognized in any language by handleTextMessage
+var menuButtons = []string{
+	buttonSearchTrains,
+	buttonSearchByDate,
+	buttonRoundTrip,
+	buttonFlexibleDates,
+	buttonViewStations,
+	buttonChangeLanguage,
+	buttonHelp,
+	buttonBack,
+}
+
+// Language buttons are shown in their own language regardless of the current locale
+const (
+	languageButtonUzbek         = "🇺🇿 O'zbekcha"
+	languageButtonUzbekCyrillic = "🇺🇿 Ўзбекча"
+	languageButtonRussian       = "🇷🇺 Русский"
+	languageButtonEnglish       = "🇺🇸 English"
+)
+
+var languageButtons = map[string]string{
+	languageButtonUzbek:         i18n.Uzbek,
+	languageButtonUzbekCyrillic: i18n.UzbekCyrillic,
+	languageButtonRussian:       i18n.Russian,
+	languageButtonEnglish:       i18n.English,
+}
+
+// stationDisplayName returns the station name in the localizer's language.
+// Unknown stations are returned unchanged.
+func (b *Bot) stationDisplayName(name string, l *i18n.Localizer) string {
+	station := b.trainService.Stations().ByName(name)
+	if station == nil {
+		return name
+	}
+	return station.LocalizedName(l.Locale())
+}
+
+// canonicalStationName maps a station name in any supported language to the
+// canonical name used for searches. Unknown input is returned trimmed.
+func (b *Bot) canonicalStationName(text string) string {
+	name := strings.TrimSpace(text)
+	if station, err := b.trainService.Stations().Resolve(name); err == nil {
+		return station.Name
+	}
+	return name
+}
+
+// stationErrorMessage explains a station that could not be resolved, listing
+// the closest stations in the localizer's language
+func stationErrorMessage(err *train.StationError, l *i18n.Localizer) string {
+	var names []string
+	for _, candidate := range err.Candidates {
+		names = append(names, candidate.LocalizedName(l.Locale()))
+	}
+
+	switch {
+	case err.Ambiguous:
+		return l.T("error.ambiguous_station", err.Query, strings.Join(names, ", "))
+	case len(names) > 0:
+		return l.T("error.unknown_station_suggest", err.Query, strings.Join(names, ", "))
+	default:
+		return l.T("error.unknown_station", err.Query)
+	}
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// sendAlertNotification delivers a ticket availability alert to the alert owner
+func (b *Bot) sendAlertNotification(payload train.NotificationPayload) error {
+	l := b.localizer(payload.Alert.ChatID)
+
+	msg := tgbotapi.NewMessage(payload.Alert.ChatID, b.formatAlertNotification(payload, l))
+	msg.ParseMode = "Markdown"
+
+	if _, err := b.api.Send(msg); err != nil {
+		return fmt.Errorf("failed to send alert notification: %w", err)
+	}
+	return nil
+}
+
+// formatAlertNotification formats an alert notification message
+func (b *Bot) formatAlertNotification(payload train.NotificationPayload, l *i18n.Localizer) string {
+	var builder strings.Builder
+
+	alert := payload.Alert
+	from, to, date := alert.From, alert.To, alert.Date
+	if payload.Direction == train.DirectionReturn {
+		from, to, date = alert.To, alert.From, alert.ReturnDate
+	}
+
+	builder.WriteString(l.T("notification.header"))
+	if alert.WatchesReturn() {
+		if payload.Direction == train.DirectionReturn {
+			builder.WriteString(l.T("notification.leg_return"))
+		} else {
+			builder.WriteString(l.T("notification.leg_forward"))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("🚉 %s → %s\n", b.stationDisplayName(from, l), b.stationDisplayName(to, l)))
+	builder.WriteString(fmt.Sprintf("📅 %s\n\n", date.Format("2006-01-02")))
+
+	t := payload.Train
+	builder.WriteString(fmt.Sprintf("🚂 *%s* (%s)\n", t.Brand, t.Number))
+	builder.WriteString(fmt.Sprintf("🕐 %s - %s (%s)\n", t.GetDepartureTime(), t.GetArrivalTime(), t.TimeOnWay))
+
+	if len(payload.Seats) > 0 {
+		builder.WriteString(l.T("notification.seats_header"))
+		for _, seat := range payload.Seats {
+			builder.WriteString(l.N("notification.seat", seat.Available,
+				seat.Name, seat.Type, seat.Available, b.trainService.FormatPrice(int(seat.Price))))
+		}
+	}
+
+	builder.WriteString(l.T("notification.alert_id", alert.ID))
+
+	return builder.String()
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// modeRoundTrip marks a user state that collects a round trip search
+const modeRoundTrip = "round_trip"
+
+// stepSelectReturnDate is the calendar step that picks the way back
+const stepSelectReturnDate = "select_return_date"
+
+// buttonOneWay skips the return date when creating an alert
+const buttonOneWay = "button.one_way"
+
+// handleRoundTripButton starts the round trip flow: outbound date, return date, then stations
+func (b *Bot) handleRoundTripButton(chatID int64) {
+	l := b.localizer(chatID)
+
+	b.resetUserState(chatID)
+	userState := b.getUserState(chatID)
+	userState.Mode = modeRoundTrip
+	userState.CurrentStep = "select_date"
+	b.saveUserState(chatID, userState)
+
+	msg := tgbotapi.NewMessage(chatID, l.T("roundtrip.prompt"))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+
+	b.showCalendar(chatID, train.Today())
+}
+
+// handleReturnDateSelected stores the way back and continues with station selection
+func (b *Bot) handleReturnDateSelected(chatID int64, messageID int, userState *UserState, returnDate time.Time) {
+	l := b.localizer(chatID)
+
+	if returnDate.Before(userState.SearchDate) {
+		msg := tgbotapi.NewMessage(chatID, l.T("calendar.return_before_outbound", userState.SearchDate.Format("2006-01-02")))
+		b.safeSend(msg)
+		return
+	}
+
+	userState.ReturnDate = returnDate
+	userState.CurrentStep = "select_from_station"
+	b.saveUserState(chatID, userState)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID,
+		l.T("calendar.return_date_selected", returnDate.Format("2006-01-02")))
+	b.safeSendEdit(editMsg)
+
+	b.promptDepartureStation(chatID, l)
+}
+
+// handleOneWayCallback skips the return date of an alert
+func (b *Bot) handleOneWayCallback(update tgbotapi.Update) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	userState := b.getUserState(chatID)
+	if userState.CurrentStep != stepSelectReturnDate {
+		return
+	}
+
+	userState.ReturnDate = time.Time{}
+	userState.CurrentStep = "select_from_station"
+	b.saveUserState(chatID, userState)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID, l.T("calendar.one_way_selected"))
+	b.safeSendEdit(editMsg)
+
+	b.promptDepartureStation(chatID, l)
+}
+
+// promptDepartureStation asks for the departure station with the station keyboard
+func (b *Bot) promptDepartureStation(chatID int64, l *i18n.Localizer) {
+	msg := tgbotapi.NewMessage(chatID, l.T("station.select_departure"))
+	msg.ReplyMarkup = departureKeyboard(l, b.trainService.Stations())
+	b.safeSend(msg)
+}
+
+// handleRoundTripSearch searches both legs collected in the user state and
+// shows them side by side
+func (b *Bot) handleRoundTripSearch(chatID int64, userState *UserState) {
+	l := b.localizer(chatID)
+	from, to := userState.FromStation, userState.ToStation
+
+	msg := tgbotapi.NewMessage(chatID, l.T("roundtrip.confirmation",
+		b.stationDisplayName(from, l),
+		b.stationDisplayName(to, l),
+		userState.SearchDate.Format("2006-01-02"),
+		userState.ReturnDate.Format("2006-01-02")))
+	msg.ParseMode = "Markdown"
+	b.safeSend(msg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	trips, err := b.trainService.FindAvailableRoundTrip(ctx, train.TrainSearchParams{
+		From:       from,
+		To:         to,
+		Date:       userState.SearchDate,
+		ReturnDate: userState.ReturnDate,
+		Language:   i18n.APILanguage(l.Locale()),
+	})
+	if err != nil {
+		log.Printf("Round trip search error: %v", err)
+
+		msg := tgbotapi.NewMessage(chatID, searchErrorMessage(err, l))
+		msg.ReplyMarkup = mainMenuKeyboard(l)
+		b.safeSend(msg)
+		return
+	}
+
+	if trips.Len() == 0 {
+		msg := tgbotapi.NewMessage(chatID, l.T("roundtrip.no_trains",
+			b.stationDisplayName(from, l), b.stationDisplayName(to, l)))
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = mainMenuKeyboard(l)
+		b.safeSend(msg)
+		return
+	}
+
+	// Both legs may not fit into one message; the menu goes with the last part
+	parts := b.splitMessage(b.trainService.FormatRoundTripResults(trips, l.Locale()), 4096)
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		msg.ParseMode = "Markdown"
+		if i == len(parts)-1 {
+			msg.ReplyMarkup = mainMenuKeyboard(l)
+		}
+		b.safeSend(msg)
+	}
+}
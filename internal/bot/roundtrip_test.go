@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+func TestRoundTripConversation(t *testing.T) {
+	c := newConversation(t)
+
+	outbound := train.Today().AddDate(0, 0, 1)
+	back := outbound.AddDate(0, 0, 3)
+	c.railway.SetTrains("2900000", "2900700", outbound.Format("2006-01-02"), trainOn(outbound, "778Ф"))
+	c.railway.SetTrains("2900700", "2900000", back.Format("2006-01-02"), trainOn(back, "767Ф"))
+
+	c.say("/start")
+	c.expect("start.welcome")
+
+	c.tap(buttonRoundTrip)
+	c.expect("roundtrip.prompt")
+	calendar := c.expect("calendar.title", c.l.T(fmt.Sprintf("month.%d", outbound.Month())), outbound.Year())
+	c.pickDate(calendar, outbound)
+	c.expect("calendar.date_selected", outbound.Format("2006-01-02"))
+
+	// The way back cannot be before the way there
+	returnCalendar := c.expect("calendar.return_title", c.l.T(fmt.Sprintf("month.%d", outbound.Month())), outbound.Year())
+	if returnCalendar.HasCallback("one_way") {
+		t.Errorf("round trip search offers a one-way option")
+	}
+	today := train.Today()
+	c.telegram.Press(c.chatID, returnCalendar.MessageID, fmt.Sprintf("date_%d_%d_%d", today.Year(), today.Month(), today.Day()))
+	c.expect("calendar.return_before_outbound", outbound.Format("2006-01-02"))
+
+	c.pickDate(returnCalendar, back)
+	if selected := c.expect("calendar.return_date_selected", back.Format("2006-01-02")); !selected.IsEdit() {
+		t.Errorf("return date selection did not replace the calendar")
+	}
+	c.expect("station.select_departure")
+
+	c.say("Toshkent")
+	c.expect("station.departure_selected", c.station("Toshkent"))
+	c.say("Samarqand")
+	c.expect("roundtrip.confirmation", c.station("Toshkent"), c.station("Samarqand"),
+		outbound.Format("2006-01-02"), back.Format("2006-01-02"))
+
+	results := c.expectText("778Ф")
+	if !strings.Contains(results.Text, "767Ф") {
+		t.Errorf("results %q lack the train back", results.Text)
+	}
+	if !results.HasButton(c.l.T(buttonRoundTrip)) {
+		t.Errorf("results are not followed by the main menu")
+	}
+
+	searches := c.railway.Searches()
+	if len(searches) != 1 || searches[0].Directions.Return == nil {
+		t.Fatalf("got searches %+v, want one search for both directions", searches)
+	}
+	if got := searches[0].Directions.Return.Date; got != back.Format("2006-01-02") {
+		t.Errorf("searched the way back on %s, want %s", got, back.Format("2006-01-02"))
+	}
+}
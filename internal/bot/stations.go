@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// stationCallbackPrefix starts the callback data of a suggested station:
+// station|<code>
+const stationCallbackPrefix = "station|"
+
+// askStationChoice replies to a station name that did not resolve. The
+// closest stations are offered as inline buttons; without candidates the
+// user is asked to pick from the keyboard.
+func (b *Bot) askStationChoice(chatID int64, err error) {
+	l := b.localizer(chatID)
+
+	var stationErr *train.StationError
+	if !errors.As(err, &stationErr) || len(stationErr.Candidates) == 0 {
+		msg := tgbotapi.NewMessage(chatID, l.T("station.invalid"))
+		msg.ReplyMarkup = stationKeyboard(l, b.trainService.Stations())
+		b.safeSend(msg)
+		return
+	}
+
+	key := "station.did_you_mean"
+	if stationErr.Ambiguous {
+		key = "station.which_one"
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, candidate := range stationErr.Candidates {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(candidate.LocalizedName(l.Locale()), stationCallbackPrefix+candidate.Code),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, l.T(key, stationErr.Query))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.safeSend(msg)
+}
+
+// handleStationCallback continues the station step with a station picked
+// from the suggestions
+func (b *Bot) handleStationCallback(update tgbotapi.Update) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	userState := b.getUserState(chatID)
+	if userState.CurrentStep != "select_from_station" && userState.CurrentStep != "select_to_station" {
+		return
+	}
+
+	station := b.trainService.Stations().ByCode(strings.TrimPrefix(callback.Data, stationCallbackPrefix))
+	if station == nil {
+		return
+	}
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID,
+		l.T("station.chosen", station.LocalizedName(l.Locale())))
+	b.safeSendEdit(editMsg)
+
+	b.selectStation(chatID, station.Name, userState)
+}
+
+// nearestCallbackPrefix starts the callback data of a station offered for a
+// shared location: near|<code>
+const nearestCallbackPrefix = "near|"
+
+// nearestStationsLimit is the number of stations offered for a shared location
+const nearestStationsLimit = 3
+
+// departureKeyboard is the station keyboard with a button that shares the
+// user's location to find the nearest station
+func departureKeyboard(l *i18n.Localizer, catalog *train.StationCatalog) tgbotapi.ReplyKeyboardMarkup {
+	keyboard := stationKeyboard(l, catalog)
+	location := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonLocation(l.T(buttonNearestStation)))
+	keyboard.Keyboard = append([][]tgbotapi.KeyboardButton{location}, keyboard.Keyboard...)
+	return keyboard
+}
+
+// handleLocation replies to a shared location with the nearest stations,
+// offered as the departure station
+func (b *Bot) handleLocation(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	l := b.localizer(chatID)
+	location := update.Message.Location
+
+	nearest := b.trainService.Stations().Nearest(train.LatLng{
+		Latitude:  location.Latitude,
+		Longitude: location.Longitude,
+	}, nearestStationsLimit)
+	if len(nearest) == 0 {
+		msg := tgbotapi.NewMessage(chatID, l.T("location.none"))
+		b.safeSend(msg)
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(l.T("location.header"))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, candidate := range nearest {
+		name := candidate.Station.LocalizedName(l.Locale())
+		text.WriteString(l.T("location.station", name, candidate.Distance))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(name, nearestCallbackPrefix+candidate.Station.Code),
+		))
+	}
+	text.WriteString(l.T("location.footer"))
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.safeSend(msg)
+}
+
+// handleNearestStationCallback departs from a station picked near the user's
+// location. Outside the departure step it starts a search for today.
+func (b *Bot) handleNearestStationCallback(update tgbotapi.Update) {
+	callback := update.CallbackQuery
+	chatID := callback.Message.Chat.ID
+	l := b.localizer(chatID)
+
+	station := b.trainService.Stations().ByCode(strings.TrimPrefix(callback.Data, nearestCallbackPrefix))
+	if station == nil {
+		return
+	}
+
+	userState := b.getUserState(chatID)
+	if userState.CurrentStep != "select_from_station" {
+		b.resetUserState(chatID)
+		userState = b.getUserState(chatID)
+		userState.CurrentStep = "select_from_station"
+		userState.SearchDate = train.Today()
+	}
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID,
+		l.T("station.chosen", station.LocalizedName(l.Locale())))
+	b.safeSendEdit(editMsg)
+
+	b.selectStation(chatID, station.Name, userState)
+}
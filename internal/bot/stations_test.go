@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// reachDepartureStep takes the conversation to the departure station prompt
+// of a search for tomorrow
+func reachDepartureStep(c *conversation) {
+	c.t.Helper()
+	c.say("/start")
+	c.expect("start.welcome")
+
+	c.tap(buttonSearchByDate)
+	tomorrow := train.Today().AddDate(0, 0, 1)
+	c.pickDate(c.expectText(""), tomorrow)
+	c.expect("calendar.date_selected", tomorrow.Format("2006-01-02"))
+	c.expect("station.select_departure")
+}
+
+func TestMisspelledStationIsSuggested(t *testing.T) {
+	c := newConversation(t)
+	reachDepartureStep(c)
+
+	c.say("Toshkentttttt")
+	suggestions := c.expect("station.did_you_mean", "Toshkentttttt")
+	if suggestions.InlineKeyboard == nil {
+		t.Fatalf("suggestions have no buttons")
+	}
+
+	c.press(suggestions, stationCallbackPrefix+"2900000")
+	if chosen := c.expect("station.chosen", c.station("Toshkent")); !chosen.IsEdit() {
+		t.Errorf("picking a suggestion did not replace them")
+	}
+	c.expect("station.departure_selected", c.station("Toshkent"))
+
+	if got := c.railway.SearchRequests(); got != 0 {
+		t.Errorf("got %d railway searches before both stations are known, want 0", got)
+	}
+}
+
+func TestSharedLocationOffersNearestStations(t *testing.T) {
+	c := newConversation(t)
+
+	// Registan square, Samarqand
+	c.telegram.SendLocation(c.chatID, 39.6548, 66.9757)
+	nearest := c.expect("location.header")
+	if nearest.InlineKeyboard == nil || len(nearest.InlineKeyboard.InlineKeyboard) != nearestStationsLimit {
+		t.Fatalf("got stations %+v, want %d", nearest.InlineKeyboard, nearestStationsLimit)
+	}
+	if first := nearest.InlineKeyboard.InlineKeyboard[0][0]; first.Text != c.station("Samarqand") {
+		t.Errorf("nearest station %q, want %q", first.Text, c.station("Samarqand"))
+	}
+
+	// Outside the departure step the station starts a search for today
+	c.press(nearest, nearestCallbackPrefix+"2900700")
+	c.expect("station.chosen", c.station("Samarqand"))
+	c.expect("station.departure_selected", c.station("Samarqand"))
+}
+
+func TestDepartureKeyboardShowsCatalogStations(t *testing.T) {
+	catalog := train.NewStationCatalog([]train.StationInfo{
+		{Code: "1", Name: "Beta", NameEn: "Beta", KeyboardRank: 2},
+		{Code: "2", Name: "Alfa", NameEn: "Alpha", KeyboardRank: 1},
+		{Code: "3", Name: "Gamma", NameEn: "Gamma"},
+	})
+	l := i18n.New(i18n.English)
+
+	keyboard := departureKeyboard(l, catalog)
+	var labels []string
+	for _, row := range keyboard.Keyboard {
+		for _, button := range row {
+			labels = append(labels, button.Text)
+		}
+	}
+
+	want := []string{l.T(buttonNearestStation), "Alpha", "Beta", l.T(buttonBack)}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("departure keyboard = %q, want %q", labels, want)
+	}
+}
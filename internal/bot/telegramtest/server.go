@@ -0,0 +1,473 @@
+// Package telegramtest provides a local stand-in for the Telegram Bot API, so
+// bot conversations can be tested without touching Telegram.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Token is the bot token the fake accepts
+const Token = "123456:TEST"
+
+// maxPoll caps how long getUpdates waits for an update, so a stopped bot is
+// not kept waiting by a long poll
+const maxPoll = time.Second
+
+// botUser is the account of the bot under test
+var botUser = tgbotapi.User{ID: 123456, IsBot: true, FirstName: "Chiptatop", UserName: "chiptatop_test_bot"}
+
+// Sent is a message the bot sent or edited
+type Sent struct {
+	Method         string // sendMessage or editMessageText
+	ChatID         int64
+	MessageID      int
+	Text           string
+	ParseMode      string
+	Keyboard       *tgbotapi.ReplyKeyboardMarkup  // Reply keyboard sent with the message, if any
+	InlineKeyboard *tgbotapi.InlineKeyboardMarkup // Inline keyboard sent with the message, if any
+}
+
+// IsEdit reports whether the bot edited an earlier message
+func (s Sent) IsEdit() bool {
+	return s.Method == "editMessageText"
+}
+
+// Buttons returns the labels of the reply keyboard, row by row flattened
+func (s Sent) Buttons() []string {
+	if s.Keyboard == nil {
+		return nil
+	}
+	var labels []string
+	for _, row := range s.Keyboard.Keyboard {
+		for _, button := range row {
+			labels = append(labels, button.Text)
+		}
+	}
+	return labels
+}
+
+// HasButton reports whether the reply keyboard has a button with the label
+func (s Sent) HasButton(label string) bool {
+	for _, text := range s.Buttons() {
+		if text == label {
+			return true
+		}
+	}
+	return false
+}
+
+// InlineButton returns the callback data of the first inline button with
+// the label
+func (s Sent) InlineButton(label string) (string, bool) {
+	if s.InlineKeyboard == nil {
+		return "", false
+	}
+	for _, row := range s.InlineKeyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.Text == label && button.CallbackData != nil {
+				return *button.CallbackData, true
+			}
+		}
+	}
+	return "", false
+}
+
+// HasCallback reports whether an inline button carries the callback data
+func (s Sent) HasCallback(data string) bool {
+	if s.InlineKeyboard == nil {
+		return false
+	}
+	for _, row := range s.InlineKeyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == data {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Server is a fake Telegram Bot API. Tests play the user: they queue updates
+// with SendText and Press, and read what the bot answered with Next. Every
+// API call is recorded for Calls. It is safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	languageCode string // Language of the Telegram client of the test user
+	updates      []tgbotapi.Update
+	nextUpdateID int
+	nextMessage  int
+	newUpdate    chan struct{} // Closed and replaced whenever an update is queued
+	sent         []Sent
+	read         int           // Sent messages returned by Next so far
+	newSent      chan struct{} // Closed and replaced whenever the bot sends
+	answered     []string      // IDs of the answered callback queries
+	calls        map[string][]url.Values
+	webhook      string // URL of the webhook, getUpdates is refused while set
+}
+
+// NewServer starts a fake Bot API. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		languageCode: "en",
+		nextUpdateID: 1,
+		nextMessage:  1,
+		newUpdate:    make(chan struct{}),
+		newSent:      make(chan struct{}),
+		calls:        make(map[string][]url.Values),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Close shuts the fake down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Endpoint is the API endpoint format for tgbotapi.NewBotAPIWithAPIEndpoint
+func (s *Server) Endpoint() string {
+	return s.server.URL + "/bot%s/%s"
+}
+
+// SetLanguageCode sets the language of the test user's Telegram client
+func (s *Server) SetLanguageCode(code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.languageCode = code
+}
+
+// SendText queues a text message from the user in a private chat. Text
+// starting with a slash is sent as a command. It returns the message ID.
+func (s *Server) SendText(chatID int64, text string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message := s.textMessageLocked(chatID, text)
+	s.queueLocked(tgbotapi.Update{Message: message})
+	return message.MessageID
+}
+
+// TextUpdate returns an update with a text message from the user without
+// queueing it, e.g. to deliver it to a webhook
+func (s *Server) TextUpdate(chatID int64, text string) tgbotapi.Update {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := tgbotapi.Update{UpdateID: s.nextUpdateID, Message: s.textMessageLocked(chatID, text)}
+	s.nextUpdateID++
+	return update
+}
+
+// SendLocation queues a location shared by the user
+func (s *Server) SendLocation(chatID int64, latitude, longitude float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message := s.userMessageLocked(chatID)
+	message.Location = &tgbotapi.Location{Latitude: latitude, Longitude: longitude}
+	s.queueLocked(tgbotapi.Update{Message: message})
+}
+
+// Press queues a tap on an inline button with the callback data, on a
+// message the bot sent
+func (s *Server) Press(chatID int64, messageID int, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userLocked(chatID)
+	s.queueLocked(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:   fmt.Sprintf("callback-%d", s.nextUpdateID),
+		From: &user,
+		Message: &tgbotapi.Message{
+			MessageID: messageID,
+			From:      &botUser,
+			Chat:      &tgbotapi.Chat{ID: chatID, Type: "private"},
+			Date:      int(time.Now().Unix()),
+		},
+		ChatInstance: strconv.FormatInt(chatID, 10),
+		Data:         data,
+	}})
+}
+
+// Next returns the next message the bot sent or edited, waiting up to
+// timeout for it
+func (s *Server) Next(timeout time.Duration) (Sent, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		if s.read < len(s.sent) {
+			sent := s.sent[s.read]
+			s.read++
+			s.mu.Unlock()
+			return sent, true
+		}
+		wait := s.newSent
+		s.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-deadline.C:
+			return Sent{}, false
+		}
+	}
+}
+
+// Sent returns every message the bot sent or edited so far
+func (s *Server) Sent() []Sent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Sent(nil), s.sent...)
+}
+
+// Calls returns the parameters of every call of an API method other than
+// getUpdates, in order
+func (s *Server) Calls(method string) []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]url.Values(nil), s.calls[method]...)
+}
+
+// SetWebhook sets a webhook as if another process had registered it
+func (s *Server) SetWebhook(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhook = url
+}
+
+// Webhook returns the URL of the webhook, empty if none is set
+func (s *Server) Webhook() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.webhook
+}
+
+// AnsweredCallbacks returns the IDs of the callback queries the bot answered
+func (s *Server) AnsweredCallbacks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.answered...)
+}
+
+// textMessageLocked returns a new text message from the test user, a command
+// if it starts with a slash. Callers hold s.mu.
+func (s *Server) textMessageLocked(chatID int64, text string) *tgbotapi.Message {
+	message := s.userMessageLocked(chatID)
+	message.Text = text
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+	return message
+}
+
+// userLocked returns the test user chatting in a private chat. Callers hold s.mu.
+func (s *Server) userLocked(chatID int64) tgbotapi.User {
+	return tgbotapi.User{ID: chatID, FirstName: "Test", UserName: "test_user", LanguageCode: s.languageCode}
+}
+
+// userMessageLocked returns a new message from the test user. Callers hold s.mu.
+func (s *Server) userMessageLocked(chatID int64) *tgbotapi.Message {
+	user := s.userLocked(chatID)
+	message := &tgbotapi.Message{
+		MessageID: s.nextMessage,
+		From:      &user,
+		Chat:      &tgbotapi.Chat{ID: chatID, Type: "private", FirstName: user.FirstName},
+		Date:      int(time.Now().Unix()),
+	}
+	s.nextMessage++
+	return message
+}
+
+// queueLocked queues an update for getUpdates. Callers hold s.mu.
+func (s *Server) queueLocked(update tgbotapi.Update) {
+	update.UpdateID = s.nextUpdateID
+	s.nextUpdateID++
+	s.updates = append(s.updates, update)
+
+	close(s.newUpdate)
+	s.newUpdate = make(chan struct{})
+}
+
+// handle serves /bot<token>/<method>
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || token != Token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	if method != "getUpdates" {
+		s.mu.Lock()
+		s.calls[method] = append(s.calls[method], r.Form)
+		s.mu.Unlock()
+	}
+
+	switch method {
+	case "getMe":
+		writeResult(w, botUser)
+	case "getUpdates":
+		if s.Webhook() != "" {
+			writeError(w, http.StatusConflict, "Conflict: can't use getUpdates method while webhook is active; use deleteWebhook to delete the webhook first")
+			return
+		}
+		s.handleGetUpdates(w, r)
+	case "sendMessage", "editMessageText":
+		s.handleSend(w, r, method)
+	case "setWebhook":
+		s.SetWebhook(r.Form.Get("url"))
+		writeResult(w, true)
+	case "deleteWebhook":
+		s.SetWebhook("")
+		writeResult(w, true)
+	case "getWebhookInfo":
+		writeResult(w, tgbotapi.WebhookInfo{URL: s.Webhook()})
+	case "answerCallbackQuery":
+		s.mu.Lock()
+		s.answered = append(s.answered, r.PostForm.Get("callback_query_id"))
+		s.mu.Unlock()
+		writeResult(w, true)
+	default:
+		// Commands, webhooks and the like need no state in tests
+		writeResult(w, true)
+	}
+}
+
+// handleGetUpdates long-polls for updates after the offset
+func (s *Server) handleGetUpdates(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.PostForm.Get("offset"))
+	timeout, _ := strconv.Atoi(r.PostForm.Get("timeout"))
+	poll := min(time.Duration(timeout)*time.Second, maxPoll)
+
+	deadline := time.NewTimer(poll)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		var pending []tgbotapi.Update
+		for _, update := range s.updates {
+			if update.UpdateID >= offset {
+				pending = append(pending, update)
+			}
+		}
+		wait := s.newUpdate
+		s.mu.Unlock()
+
+		if len(pending) > 0 || poll <= 0 {
+			writeResult(w, pending)
+			return
+		}
+
+		select {
+		case <-wait:
+		case <-deadline.C:
+			writeResult(w, []tgbotapi.Update{})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleSend records a sent or edited message
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request, method string) {
+	form := r.PostForm
+	chatID, err := strconv.ParseInt(form.Get("chat_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: chat not found")
+		return
+	}
+	if form.Get("text") == "" {
+		writeError(w, http.StatusBadRequest, "Bad Request: message text is empty")
+		return
+	}
+
+	sent := Sent{
+		Method:    method,
+		ChatID:    chatID,
+		Text:      form.Get("text"),
+		ParseMode: form.Get("parse_mode"),
+	}
+	if markup := form.Get("reply_markup"); markup != "" {
+		if err := decodeMarkup(markup, &sent); err != nil {
+			writeError(w, http.StatusBadRequest, "Bad Request: can't parse reply keyboard markup JSON object")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	if method == "editMessageText" {
+		sent.MessageID, _ = strconv.Atoi(form.Get("message_id"))
+	} else {
+		sent.MessageID = s.nextMessage
+		s.nextMessage++
+	}
+	s.sent = append(s.sent, sent)
+	close(s.newSent)
+	s.newSent = make(chan struct{})
+	s.mu.Unlock()
+
+	writeResult(w, tgbotapi.Message{
+		MessageID: sent.MessageID,
+		From:      &botUser,
+		Chat:      &tgbotapi.Chat{ID: chatID, Type: "private"},
+		Date:      int(time.Now().Unix()),
+		Text:      sent.Text,
+	})
+}
+
+// decodeMarkup reads a reply or inline keyboard
+func decodeMarkup(markup string, sent *Sent) error {
+	var kinds struct {
+		Keyboard       json.RawMessage `json:"keyboard"`
+		InlineKeyboard json.RawMessage `json:"inline_keyboard"`
+	}
+	if err := json.Unmarshal([]byte(markup), &kinds); err != nil {
+		return err
+	}
+
+	switch {
+	case kinds.Keyboard != nil:
+		sent.Keyboard = &tgbotapi.ReplyKeyboardMarkup{}
+		return json.Unmarshal([]byte(markup), sent.Keyboard)
+	case kinds.InlineKeyboard != nil:
+		sent.InlineKeyboard = &tgbotapi.InlineKeyboardMarkup{}
+		return json.Unmarshal([]byte(markup), sent.InlineKeyboard)
+	}
+	return nil
+}
+
+// writeResult writes a successful Bot API response
+func writeResult(w http.ResponseWriter, result interface{}) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+// writeError writes a failed Bot API response
+func writeError(w http.ResponseWriter, status int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: false, ErrorCode: status, Description: description})
+}
@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretTokenHeader carries the webhook secret in every update Telegram posts
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookShutdownTimeout bounds how long updates being handed to workers may
+// hold up shutdown. It is a variable so that tests can shorten it.
+var webhookShutdownTimeout = 10 * time.Second
+
+// serveWebhook receives updates posted by Telegram to the built-in HTTP
+// server. The webhook is registered once the server listens and, unless
+// replicas share it, removed again on shutdown. Replicas sharing the webhook
+// each handle some of the chats, so they need a store they share too, with
+// alerts checked by only one of them (Config.AlertChecks).
+func (b *Bot) serveWebhook(ctx context.Context, workers *dispatcher) error {
+	listener, err := net.Listen("tcp", b.cfg.WebhookListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen for webhook: %w", err)
+	}
+
+	// Updates must not reach the workers once they are stopped
+	handlers := &handlerGate{}
+	defer handlers.close()
+
+	mux := http.NewServeMux()
+	mux.Handle(b.webhookPath(), b.webhookHandler(workers, handlers))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		if b.cfg.WebhookCertFile != "" {
+			served <- server.ServeTLS(listener, b.cfg.WebhookCertFile, b.cfg.WebhookKeyFile)
+		} else {
+			served <- server.Serve(listener)
+		}
+	}()
+
+	if err := b.setWebhook(); err != nil {
+		server.Close()
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	log.Printf("Receiving updates at %s on %s", b.webhookURL(), listener.Addr())
+
+	if b.cfg.WebhookDeleteOnStop {
+		defer func() {
+			if err := b.deleteWebhook(); err != nil {
+				log.Printf("Failed to delete webhook: %v", err)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down bot...")
+	case err := <-served:
+		server.Close()
+		return fmt.Errorf("webhook server stopped: %w", err)
+	}
+
+	// Updates being posted are still handed to the workers
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Closing the connections cancels the updates still waiting for room
+		// in the queues; they are delivered again
+		server.Close()
+		return fmt.Errorf("webhook server shutdown: %w", err)
+	}
+	return nil
+}
+
+// handlerGate admits webhook requests until it is closed and lets closing
+// wait for the requests already admitted
+type handlerGate struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// enter admits a request, which must call leave when done. It reports false
+// once the gate is closed.
+func (g *handlerGate) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.wg.Add(1)
+	return true
+}
+
+// leave marks an admitted request as done
+func (g *handlerGate) leave() {
+	g.wg.Done()
+}
+
+// close stops admitting requests and waits for those admitted to finish
+func (g *handlerGate) close() {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+	g.wg.Wait()
+}
+
+// webhookHandler accepts updates that carry the webhook secret and hands them
+// to the workers. An update the workers cannot take, or that arrives once the
+// gate is closed, is refused, so that Telegram delivers it again.
+func (b *Bot) webhookHandler(workers *dispatcher, handlers *handlerGate) http.Handler {
+	secret := []byte(b.webhookSecret())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !handlers.enter() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer handlers.leave()
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), secret) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		update, err := b.api.HandleUpdate(r)
+		if err != nil {
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+
+		if err := workers.Dispatch(r.Context(), *update); err != nil {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// setWebhook points Telegram at the webhook URL. The secret token is not in
+// tgbotapi.WebhookConfig, so the request is made by hand.
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          b.webhookURL(),
+		"secret_token": b.webhookSecret(),
+	}
+	_, err := b.api.MakeRequest("setWebhook", params)
+	return err
+}
+
+// deleteWebhook makes Telegram stop posting updates
+func (b *Bot) deleteWebhook() error {
+	_, err := b.api.Request(tgbotapi.DeleteWebhookConfig{})
+	return err
+}
+
+// webhookPath returns the path Telegram posts updates to
+func (b *Bot) webhookPath() string {
+	path := b.cfg.WebhookPath
+	if path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// webhookURL returns the public URL Telegram posts updates to
+func (b *Bot) webhookURL() string {
+	return strings.TrimSuffix(b.cfg.WebhookURL, "/") + b.webhookPath()
+}
+
+// webhookSecret returns the secret token of the webhook. Without a configured
+// one it is derived from the bot token, so every replica expects the same.
+func (b *Bot) webhookSecret() string {
+	if b.cfg.WebhookSecret != "" {
+		return b.cfg.WebhookSecret
+	}
+	sum := sha256.Sum256([]byte("webhook:" + b.cfg.TelegramBotToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,239 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/bot/telegramtest"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/config"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// freeAddress returns a local address nothing listens on
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("no free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// waitFor polls until condition holds or fails the test after replyTimeout
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(replyTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// postUpdate posts an update to the webhook at url as Telegram does and
+// returns the response status
+func postUpdate(url, secret string, update tgbotapi.Update) (int, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// A kept-alive connection would hold up the server shutdown
+	req.Close = true
+	if secret != "" {
+		req.Header.Set(secretTokenHeader, secret)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func TestWebhookReceivesUpdates(t *testing.T) {
+	address := freeAddress(t)
+	c := newConversation(t, func(cfg *config.Config) {
+		cfg.WebhookURL = "https://bot.example.com/"
+		cfg.WebhookPath = "/hook"
+		cfg.WebhookListen = address
+		cfg.WebhookSecret = "s3cret"
+		cfg.WebhookDeleteOnStop = true
+	})
+
+	waitFor(t, "setWebhook", func() bool { return len(c.telegram.Calls("setWebhook")) > 0 })
+	params := c.telegram.Calls("setWebhook")[0]
+	if got := params.Get("url"); got != "https://bot.example.com/hook" {
+		t.Errorf("webhook URL %q, want https://bot.example.com/hook", got)
+	}
+	if got := params.Get("secret_token"); got != "s3cret" {
+		t.Errorf("webhook secret %q, want s3cret", got)
+	}
+
+	post := func(secret string) int {
+		t.Helper()
+		status, err := postUpdate("http://"+address+"/hook", secret, c.telegram.TextUpdate(c.chatID, "/start"))
+		if err != nil {
+			t.Fatalf("post update: %v", err)
+		}
+		return status
+	}
+
+	for _, secret := range []string{"", "wrong"} {
+		if status := post(secret); status != http.StatusUnauthorized {
+			t.Errorf("update with secret %q got status %d, want %d", secret, status, http.StatusUnauthorized)
+		}
+	}
+	if status := post("s3cret"); status != http.StatusOK {
+		t.Fatalf("update got status %d, want %d", status, http.StatusOK)
+	}
+	c.expect("start.welcome")
+
+	// Only the genuine update was handled
+	if sent, ok := c.telegram.Next(100 * time.Millisecond); ok {
+		t.Errorf("unexpected reply %q", sent.Text)
+	}
+}
+
+func TestWebhookShutdownTimesOut(t *testing.T) {
+	timeout := webhookShutdownTimeout
+	webhookShutdownTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { webhookShutdownTimeout = timeout })
+
+	address := freeAddress(t)
+	c := newConversation(t, func(cfg *config.Config) {
+		cfg.WebhookURL = "https://bot.example.com"
+		cfg.WebhookListen = address
+		cfg.WebhookSecret = "s3cret"
+		cfg.UpdateWorkers = 1
+		cfg.UpdateQueueSize = 1
+	})
+	waitFor(t, "setWebhook", func() bool { return len(c.telegram.Calls("setWebhook")) > 0 })
+	c.railway.QueueSearchReplies(railwaytest.Reply{Delay: time.Second})
+
+	post := func(text string) (int, error) {
+		return postUpdate("http://"+address+"/", "s3cret", c.telegram.TextUpdate(c.chatID, text))
+	}
+
+	// The only worker is busy searching and its queue is full
+	date := train.Today().AddDate(0, 0, 1).Format("2006-01-02")
+	if status, err := post("/search_date Toshkent Samarqand " + date); err != nil || status != http.StatusOK {
+		t.Fatalf("search update got %d, %v", status, err)
+	}
+	c.expect("search.searching", c.station("Toshkent"), c.station("Samarqand"), date)
+	if status, err := post("/start"); err != nil || status != http.StatusOK {
+		t.Fatalf("queued update got %d, %v", status, err)
+	}
+
+	// This update waits for room until the server is closed
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		if status, err := post("/help"); err == nil && status == http.StatusOK {
+			t.Errorf("update posted during shutdown was accepted")
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	err := c.shutdown()
+	if err == nil || !strings.Contains(err.Error(), "webhook server shutdown") {
+		t.Errorf("Run() = %v, want a webhook shutdown error", err)
+	}
+	<-blocked
+}
+
+func TestWebhookIsDeletedOnStop(t *testing.T) {
+	tests := []struct {
+		name         string
+		deleteOnStop bool
+		want         int
+	}{
+		{"single instance", true, 1},
+		{"shared by replicas", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c *conversation
+			t.Run("run", func(t *testing.T) {
+				c = newConversation(t, func(cfg *config.Config) {
+					cfg.WebhookURL = "https://bot.example.com"
+					cfg.WebhookListen = freeAddress(t)
+					cfg.WebhookDeleteOnStop = tt.deleteOnStop
+				})
+				waitFor(t, "setWebhook", func() bool { return len(c.telegram.Calls("setWebhook")) > 0 })
+			})
+
+			// The bot stopped when the subtest ended
+			if got := len(c.telegram.Calls("deleteWebhook")); got != tt.want {
+				t.Errorf("got %d deleteWebhook calls, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollingClearsWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		webhook      string
+		deleteOnStop bool
+		wantErr      bool
+		wantDeletes  int
+	}{
+		{"no webhook", "", true, false, 0},
+		{"left behind", "https://bot.example.com/telegram/webhook", true, false, 1},
+		{"shared by replicas", "https://bot.example.com/telegram/webhook", false, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telegram := telegramtest.NewServer()
+			defer telegram.Close()
+			railway := railwaytest.NewServer()
+			defer railway.Close()
+			telegram.SetWebhook(tt.webhook)
+
+			cfg := testConfig(telegram, railway, t.TempDir())
+			cfg.WebhookDeleteOnStop = tt.deleteOnStop
+			b, err := New(cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer b.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stopped := make(chan error, 1)
+			go func() { stopped <- b.Run(ctx) }()
+
+			if !tt.wantErr {
+				telegram.SendText(1001, "/start")
+				if _, ok := telegram.Next(replyTimeout); !ok {
+					t.Errorf("no reply while polling")
+				}
+			}
+			cancel()
+
+			if err := <-stopped; (err != nil) != tt.wantErr {
+				t.Errorf("Run() = %v, want error %v", err, tt.wantErr)
+			}
+			if got := len(telegram.Calls("deleteWebhook")); got != tt.wantDeletes {
+				t.Errorf("got %d deleteWebhook calls, want %d", got, tt.wantDeletes)
+			}
+		})
+	}
+}
@@ -3,29 +3,144 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/joho/godotenv"
 )
 
+// Cassette modes for railway responses
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+)
+
 type Config struct {
-	TelegramBotToken string
-	Environment      string
+	TelegramBotToken    string
+	TelegramAPIEndpoint string // Bot API URL format with the token and method, e.g. for a local Bot API server
+	Environment         string
+
+	// Webhook mode, used instead of long polling when WebhookURL is set
+	WebhookURL          string // Public HTTPS URL Telegram posts updates to, without the path
+	WebhookPath         string
+	WebhookListen       string // Address of the built-in HTTP server, e.g. ":8443"
+	WebhookSecret       string // Expected X-Telegram-Bot-Api-Secret-Token, derived from the bot token if empty
+	WebhookCertFile     string // TLS certificate; without one the server speaks plain HTTP behind a reverse proxy
+	WebhookKeyFile      string
+	WebhookDeleteOnStop bool // Remove the webhook on shutdown and before polling; keep it when replicas share it
+
+	// Railway API location, the public eticket.railway.uz API if empty
+	RailwayBaseURL   string
+	RailwayBaseURLv1 string
+
+	// Recorded railway responses: "record" appends responses to the cassette,
+	// "replay" answers from it without the network
+	CassetteMode string
+	CassettePath string
 
 	// Railway API Configuration - now optional since we'll get them dynamically
 	RailwayXSRFToken string
 	RailwayCookies   string
+	SessionPath      string // Where the railway.uz session cookies are kept between restarts
+
+	// Retry policy for railway API requests
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+
+	// Load limits for railway API requests
+	RequestRate         float64 // Sustained requests per second
+	RequestBurst        int     // Requests that may start at once after a quiet period
+	MaxInFlightRequests int     // Requests waiting for a response at the same time
+
+	// Circuit breaker for a failing railway API
+	BreakerThreshold int           // Consecutive failures before requests stop
+	BreakerCooldown  time.Duration // Pause before the API is tried again
+
+	// Search responses reused for identical searches
+	SearchCacheTTL     time.Duration
+	StaleResultsMaxAge time.Duration // How old results shown while the API is down may be
+
+	// Alert monitoring
+	AlertCheckInterval time.Duration
+	AlertChecks        bool // Check alerts on this replica; only one replica of a shared store may
+
+	// Journey planning
+	MinTransferTime time.Duration // Shortest change between trains at a hub station
+
+	// Storage for alerts, user state and preferences
+	StorageBackend string // "file" or "memory"
+	StoragePath    string
+
+	// Station catalog synced from the railway handbook
+	StationsCachePath string
+	StationsTTL       time.Duration // How long a fetched handbook stays fresh
+
+	// Update processing
+	UpdateWorkers   int // Number of chats processed in parallel
+	UpdateQueueSize int // Pending updates per worker before polling blocks
+
+	// Shutdown
+	ShutdownTimeout time.Duration // How long in-flight work may take to finish on shutdown
 }
 
 func Load() Config {
 	_ = godotenv.Load()
 
 	cfg := Config{
-		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
-		Environment:      valueOrDefault(os.Getenv("ENVIRONMENT"), "development"),
+		TelegramBotToken:    os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramAPIEndpoint: valueOrDefault(os.Getenv("TELEGRAM_API_ENDPOINT"), tgbotapi.APIEndpoint),
+		Environment:         valueOrDefault(os.Getenv("ENVIRONMENT"), "development"),
+
+		WebhookURL:          os.Getenv("WEBHOOK_URL"),
+		WebhookPath:         valueOrDefault(os.Getenv("WEBHOOK_PATH"), "/telegram/webhook"),
+		WebhookListen:       valueOrDefault(os.Getenv("WEBHOOK_LISTEN"), ":8443"),
+		WebhookSecret:       os.Getenv("WEBHOOK_SECRET"),
+		WebhookCertFile:     os.Getenv("WEBHOOK_TLS_CERT"),
+		WebhookKeyFile:      os.Getenv("WEBHOOK_TLS_KEY"),
+		WebhookDeleteOnStop: boolOrDefault(os.Getenv("WEBHOOK_DELETE_ON_STOP"), true),
+
+		RailwayBaseURL:   os.Getenv("RAILWAY_BASE_URL"),
+		RailwayBaseURLv1: os.Getenv("RAILWAY_BASE_URL_V1"),
+
+		CassetteMode: os.Getenv("CASSETTE_MODE"),
+		CassettePath: valueOrDefault(os.Getenv("CASSETTE_PATH"), "data/railway_cassette.json"),
 
 		// Railway API credentials - now optional, will be obtained dynamically
 		RailwayXSRFToken: os.Getenv("RAILWAY_XSRF_TOKEN"),
 		RailwayCookies:   os.Getenv("RAILWAY_COOKIES"),
+		SessionPath:      valueOrDefault(os.Getenv("SESSION_PATH"), "data/session.json"),
+
+		RetryMaxAttempts: intOrDefault(os.Getenv("RETRY_MAX_ATTEMPTS"), 3),
+		RetryBaseDelay:   durationOrDefault(os.Getenv("RETRY_BASE_DELAY"), time.Second),
+		RetryMaxDelay:    durationOrDefault(os.Getenv("RETRY_MAX_DELAY"), 10*time.Second),
+
+		RequestRate:         floatOrDefault(os.Getenv("REQUEST_RATE"), 2),
+		RequestBurst:        intOrDefault(os.Getenv("REQUEST_BURST"), 5),
+		MaxInFlightRequests: intOrDefault(os.Getenv("MAX_IN_FLIGHT_REQUESTS"), 4),
+
+		BreakerThreshold: intOrDefault(os.Getenv("BREAKER_THRESHOLD"), 5),
+		BreakerCooldown:  durationOrDefault(os.Getenv("BREAKER_COOLDOWN"), 30*time.Second),
+
+		SearchCacheTTL:     durationOrDefault(os.Getenv("SEARCH_CACHE_TTL"), time.Minute),
+		StaleResultsMaxAge: durationOrDefault(os.Getenv("STALE_RESULTS_MAX_AGE"), time.Hour),
+
+		AlertCheckInterval: durationOrDefault(os.Getenv("ALERT_CHECK_INTERVAL"), 5*time.Minute),
+		AlertChecks:        boolOrDefault(os.Getenv("ALERT_CHECKS"), true),
+
+		MinTransferTime: durationOrDefault(os.Getenv("MIN_TRANSFER_TIME"), 45*time.Minute),
+
+		StorageBackend: valueOrDefault(os.Getenv("STORAGE_BACKEND"), "file"),
+		StoragePath:    valueOrDefault(os.Getenv("STORAGE_PATH"), "data/chiptatop.json"),
+
+		StationsCachePath: valueOrDefault(os.Getenv("STATIONS_CACHE_PATH"), "data/stations.json"),
+		StationsTTL:       durationOrDefault(os.Getenv("STATIONS_TTL"), 24*time.Hour),
+
+		UpdateWorkers:   intOrDefault(os.Getenv("UPDATE_WORKERS"), 8),
+		UpdateQueueSize: intOrDefault(os.Getenv("UPDATE_QUEUE_SIZE"), 64),
+
+		ShutdownTimeout: durationOrDefault(os.Getenv("SHUTDOWN_TIMEOUT"), 30*time.Second),
 	}
 
 	if cfg.TelegramBotToken == "" {
@@ -48,3 +163,51 @@ func valueOrDefault(value string, def string) string {
 	}
 	return value
 }
+
+func intOrDefault(value string, def int) int {
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid number %q, using default %d", value, def)
+		return def
+	}
+	return n
+}
+
+func floatOrDefault(value string, def float64) float64 {
+	if value == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f <= 0 {
+		log.Printf("Invalid number %q, using default %v", value, def)
+		return def
+	}
+	return f
+}
+
+func boolOrDefault(value string, def bool) bool {
+	if value == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean %q, using default %v", value, def)
+		return def
+	}
+	return b
+}
+
+func durationOrDefault(value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration %q, using default %v", value, def)
+		return def
+	}
+	return d
+}
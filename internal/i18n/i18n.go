@@ -0,0 +1,182 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Supported locales
+const (
+	Uzbek         = "uz"      // Uzbek, Latin script
+	UzbekCyrillic = "uz-Cyrl" // Uzbek, Cyrillic script
+	Russian       = "ru"
+	English       = "en"
+)
+
+// Default is the locale used when the user has not chosen one
+const Default = Uzbek
+
+// Plural categories, following the CLDR names
+const (
+	pluralOne   = "one"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+// catalog maps message keys to translations. Plural messages are stored
+// under "<key>.<category>", e.g. "trains.found.one".
+type catalog map[string]string
+
+var catalogs = map[string]catalog{
+	Uzbek:         uz,
+	UzbekCyrillic: uzCyrl,
+	Russian:       ru,
+	English:       en,
+}
+
+// Locales returns all supported locales
+func Locales() []string {
+	return []string{Uzbek, UzbekCyrillic, Russian, English}
+}
+
+// IsSupported reports whether the locale has a message catalog
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// FromLanguageCode maps a Telegram IETF language code (e.g. "ru", "en-US")
+// to a supported locale. Unknown languages fall back to English.
+func FromLanguageCode(code string) string {
+	code = strings.ToLower(code)
+	switch {
+	case code == "":
+		return Default
+	case strings.HasPrefix(code, "uz-cyrl"):
+		return UzbekCyrillic
+	case strings.HasPrefix(code, "uz"):
+		return Uzbek
+	case strings.HasPrefix(code, "ru"):
+		return Russian
+	default:
+		return English
+	}
+}
+
+// APILanguage returns the railway API language for a locale. The API has
+// no Cyrillic Uzbek, so both Uzbek scripts use "uz".
+func APILanguage(locale string) string {
+	switch locale {
+	case Uzbek, UzbekCyrillic:
+		return "uz"
+	case Russian:
+		return "ru"
+	case English:
+		return "en"
+	default:
+		return "uz"
+	}
+}
+
+// Localizer looks up messages for a single locale
+type Localizer struct {
+	locale string
+}
+
+// New returns a localizer for the locale, falling back to Default for
+// unsupported locales
+func New(locale string) *Localizer {
+	if !IsSupported(locale) {
+		locale = Default
+	}
+	return &Localizer{locale: locale}
+}
+
+// Locale returns the locale of the localizer
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T returns the message for key formatted with args
+func (l *Localizer) T(key string, args ...interface{}) string {
+	return format(l.lookup(key), args)
+}
+
+// N returns the plural form of the message for key that matches n,
+// formatted with args. The count is not added to args automatically.
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	category := pluralCategory(l.locale, n)
+
+	message, ok := l.find(key + "." + category)
+	if !ok {
+		message = l.lookup(key + "." + pluralOther)
+	}
+	return format(message, args)
+}
+
+// lookup returns the message for key in the localizer's locale, then in
+// English, and finally the key itself so missing translations are visible
+func (l *Localizer) lookup(key string) string {
+	if message, ok := l.find(key); ok {
+		return message
+	}
+	if message, ok := catalogs[English][key]; ok {
+		return message
+	}
+
+	log.Printf("i18n: missing message %q", key)
+	return key
+}
+
+func (l *Localizer) find(key string) (string, bool) {
+	message, ok := catalogs[l.locale][key]
+	return message, ok
+}
+
+// Match returns the first key whose message equals text in any locale, or
+// an empty string. It is used to recognize reply keyboard buttons.
+func Match(text string, keys ...string) string {
+	for _, key := range keys {
+		for _, c := range catalogs {
+			if message, ok := c[key]; ok && message == text {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+func format(message string, args []interface{}) string {
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// pluralCategory selects the CLDR plural category of n for the locale
+func pluralCategory(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch locale {
+	case Russian:
+		mod10, mod100 := n%10, n%100
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return pluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	default:
+		// English and Uzbek distinguish only one and other
+		if n == 1 {
+			return pluralOne
+		}
+		return pluralOther
+	}
+}
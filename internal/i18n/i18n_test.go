@@ -0,0 +1,176 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{English, 0, pluralOther},
+		{English, 1, pluralOne},
+		{English, 2, pluralOther},
+		{English, 11, pluralOther},
+		{English, -1, pluralOne},
+		{Uzbek, 1, pluralOne},
+		{Uzbek, 5, pluralOther},
+		{UzbekCyrillic, 1, pluralOne},
+		{UzbekCyrillic, 21, pluralOther},
+		{Russian, 0, pluralMany},
+		{Russian, 1, pluralOne},
+		{Russian, 2, pluralFew},
+		{Russian, 4, pluralFew},
+		{Russian, 5, pluralMany},
+		{Russian, 11, pluralMany},
+		{Russian, 12, pluralMany},
+		{Russian, 14, pluralMany},
+		{Russian, 21, pluralOne},
+		{Russian, 22, pluralFew},
+		{Russian, 25, pluralMany},
+		{Russian, 101, pluralOne},
+		{Russian, 111, pluralMany},
+		{Russian, 112, pluralMany},
+		{Russian, 122, pluralFew},
+		{Russian, -3, pluralFew},
+	}
+
+	for _, tt := range tests {
+		if got := pluralCategory(tt.locale, tt.n); got != tt.want {
+			t.Errorf("pluralCategory(%s, %d) = %s, want %s", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFromLanguageCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"", Default},
+		{"uz", Uzbek},
+		{"uz-Latn", Uzbek},
+		{"uz-Cyrl", UzbekCyrillic},
+		{"UZ-CYRL-UZ", UzbekCyrillic},
+		{"ru", Russian},
+		{"ru-RU", Russian},
+		{"en", English},
+		{"en-US", English},
+		{"de", English},
+	}
+
+	for _, tt := range tests {
+		if got := FromLanguageCode(tt.code); got != tt.want {
+			t.Errorf("FromLanguageCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestAPILanguage(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{Uzbek, "uz"},
+		{UzbekCyrillic, "uz"},
+		{Russian, "ru"},
+		{English, "en"},
+		{"fr", "uz"},
+	}
+
+	for _, tt := range tests {
+		if got := APILanguage(tt.locale); got != tt.want {
+			t.Errorf("APILanguage(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestPluralMessages(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{English, 1, "You already have 1 active alert."},
+		{English, 3, "You already have 3 active alerts."},
+		{Russian, 1, "У вас уже 1 активное уведомление."},
+		{Russian, 3, "У вас уже 3 активных уведомления."},
+		{Russian, 10, "У вас уже 10 активных уведомлений."},
+	}
+
+	for _, tt := range tests {
+		got := New(tt.locale).N("alert.limit_reached", tt.n, tt.n)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("N(alert.limit_reached, %d) in %s = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestLookupFallsBack(t *testing.T) {
+	if got := New("fr").Locale(); got != Default {
+		t.Errorf("New(fr).Locale() = %q, want %q", got, Default)
+	}
+	if got := New(Russian).T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T of a missing key = %q, want the key", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{en["button.back"], "button.back"},
+		{ru["button.back"], "button.back"},
+		{uzCyrl["button.back"], "button.back"},
+		{en["button.help"], "button.help"},
+		{"back", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.text, "button.help", "button.back"); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestCatalogsAreComplete checks that every locale has every message, with
+// the plural forms its language needs
+func TestCatalogsAreComplete(t *testing.T) {
+	forms := map[string][]string{
+		Uzbek:         {pluralOne, pluralOther},
+		UzbekCyrillic: {pluralOne, pluralOther},
+		Russian:       {pluralOne, pluralFew, pluralMany},
+		English:       {pluralOne, pluralOther},
+	}
+
+	// Messages by key, with plural messages by their base key
+	keys := make(map[string]bool)
+	for key := range en {
+		if base, ok := strings.CutSuffix(key, "."+pluralOther); ok {
+			keys[base+".*"] = true
+		} else if !strings.HasSuffix(key, "."+pluralOne) {
+			keys[key] = true
+		}
+	}
+
+	for _, locale := range Locales() {
+		c := catalogs[locale]
+		for key := range keys {
+			if base, ok := strings.CutSuffix(key, ".*"); ok {
+				for _, form := range forms[locale] {
+					if _, ok := c[base+"."+form]; !ok {
+						t.Errorf("%s: missing %s.%s", locale, base, form)
+					}
+				}
+				continue
+			}
+			if _, ok := c[key]; !ok {
+				t.Errorf("%s: missing %s", locale, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package i18n
+
+var en = catalog{
+	// Menu buttons
+	"button.search_trains":     "🔍 Search Trains",
+	"button.search_by_date":    "📅 Search by Date",
+	"button.view_stations":     "🚉 View Stations",
+	"button.change_language":   "🌍 Change Language",
+	"button.help":              "❓ Help",
+	"button.back":              "🔙 Back to Main Menu",
+	"button.nearest_station":   "📍 Nearest station",
+	"button.any_seat_type":     "🎫 Any seat type",
+	"button.seats_done":        "✅ Done",
+	"button.skip":              "⏭ Skip",
+	"button.round_trip":        "🔁 Round Trip",
+	"button.one_way":           "➡️ One way only",
+	"button.direction_forward": "➡️ Outbound only",
+	"button.direction_return":  "⬅️ Return only",
+	"button.direction_both":    "🔁 Both directions",
+	"button.flexible_dates":    "📆 Flexible Dates",
+
+	// Main menu and help
+	"start.welcome": "🚂 *Welcome to ChiptaTop!*\n\nI will help you find train tickets instantly. Use the menu buttons below:",
+	"help.text": `🚂 *ChiptaTop Train Bot Help*
+
+🔍 *How to Use:*
+• Use the menu buttons to navigate
+• Search for trains between any stations
+• View available dates and times
+• Change language as needed
+
+📋 *Available Options:*
+• Search Trains - Find trains for today
+• Search by Date - Find trains for specific date
+• Round Trip - Outbound and return trains side by side
+• Flexible Dates - Seats and the cheapest price for each day around a date (also /flex)
+• Connections - Routes with a change of trains when there is no direct train (/connect)
+• View Stations - See all available stations
+• Change Language - Switch between Uzbek/Russian/English
+
+🔔 *Ticket Alerts:*
+• /alert - Get notified when tickets become available
+• /alerts - List your alerts
+• /pause, /resume, /delete - Manage an alert by its ID
+
+💡 *Tips:*
+• All major cities are supported
+• Results show available seats and prices
+• Automatic language detection
+• Share your location to find the nearest station`,
+	"language.prompt":  "🌍 *Change Language*\n\nChoose your preferred language for the bot interface:",
+	"language.changed": "🇺🇸 *Language changed!*\n\nSwitched to English. The menus and search results will now be in English.",
+
+	// Errors
+	"error.unknown_command":         "Unknown command. Try /help to see available commands.",
+	"error.not_understood":          "❓ I didn't understand that. Please use the menu buttons or send a search request in the format:\n\n`from_station to_station`\nor\n`from_station to_station YYYY-MM-DD`",
+	"error.invalid_date":            "❌ Invalid date format. Please use YYYY-MM-DD format.\n\nExample: `2025-01-15`",
+	"error.auth":                    "❌ Authentication Error\n\nUnable to authenticate with railway service. Please try again later.\n\nIf this problem persists, the railway service may be temporarily unavailable.",
+	"error.search_failed":           "❌ Search Failed\n\nCould not connect to railway service after multiple attempts. This might be because:\n• Network connection issues\n• Railway service is temporarily unavailable\n• High server load\n\nPlease try again in a few moments.",
+	"error.search_unexpected":       "❌ Search Error\n\nAn unexpected error occurred while searching for trains. Please try again later.",
+	"error.rate_limited":            "⏳ Too Many Requests\n\nThe railway service is receiving too many requests right now. Please try again in a minute.",
+	"error.timeout":                 "⌛ Search Timed Out\n\nThe railway service did not answer in time. Please try again in a few moments.",
+	"error.service_down":            "🚧 Railway Service Unavailable\n\nThe railway service is not responding right now. Please try again in a few minutes.",
+	"error.unknown_station":         "❌ Unknown station: %s. Use /stations to see available stations.",
+	"error.unknown_station_suggest": "❌ Unknown station: %s. Did you mean: %s?",
+	"error.ambiguous_station":       "❓ %s matches several stations: %s. Please be more specific.",
+
+	// Nearest station
+	"location.header":  "📍 *Stations near you:*\n\n",
+	"location.station": "• %s - %.0f km\n",
+	"location.footer":  "\nTap a station to depart from it.",
+	"location.none":    "❌ No stations with known locations were found.",
+
+	// Calendar
+	"calendar.title":                  "📅 Select Travel Date\n\n%s %d\n\n",
+	"calendar.past_date":              "❌ Cannot select a date in the past. Please choose a future date.",
+	"calendar.date_selected":          "✅ Selected date: %s",
+	"calendar.return_title":           "📅 Select Return Date\n\n%s %d\n\n",
+	"calendar.return_title_optional":  "📅 Select Return Date\n\nPick a date to watch the way back too, or tap One way only.\n\n%s %d\n\n",
+	"calendar.return_date_selected":   "✅ Return date: %s",
+	"calendar.return_before_outbound": "❌ The return date cannot be earlier than the departure date (%s). Please choose another date.",
+	"calendar.one_way_selected":       "➡️ One way trip",
+	"month.1":                         "January",
+	"month.2":                         "February",
+	"month.3":                         "March",
+	"month.4":                         "April",
+	"month.5":                         "May",
+	"month.6":                         "June",
+	"month.7":                         "July",
+	"month.8":                         "August",
+	"month.9":                         "September",
+	"month.10":                        "October",
+	"month.11":                        "November",
+	"month.12":                        "December",
+	"weekday.mon":                     "Mon",
+	"weekday.tue":                     "Tue",
+	"weekday.wed":                     "Wed",
+	"weekday.thu":                     "Thu",
+	"weekday.fri":                     "Fri",
+	"weekday.sat":                     "Sat",
+	"weekday.sun":                     "Sun",
+
+	// Stations
+	"station.select_departure":   "Please select your departure station:",
+	"station.departure_selected": "✅ Departure station: *%s*\n\nNow select your destination station:",
+	"station.invalid":            "❌ Invalid station selection. Please try again.",
+	"station.did_you_mean":       "🤔 I couldn't find the station \"%s\". Did you mean one of these?",
+	"station.which_one":          "🤔 \"%s\" matches several stations. Which one did you mean?",
+	"station.chosen":             "✅ %s",
+	"station.same":               "❌ Departure and destination stations cannot be the same. Please select a different destination station.",
+	"stations.list_header":       "🚉 *Available Railway Stations:*\n\n",
+	"stations.list_footer":       "\n\n💡 Use these names in your search requests!",
+	"stations.overview": `🚉 *Available Railway Stations (16 total):*
+
+*Major Cities:*
+🇺🇿 *Tashkent* - Capital city
+🇺🇿 *Samarkand* - Historic center
+🇺🇿 *Bukhara* - Ancient city
+🇺🇿 *Andijan* - Eastern hub
+🇺🇿 *Karshi* - Southern center
+🇺🇿 *Termez* - Southern border
+🇺🇿 *Nukus* - Karakalpakstan
+🇺🇿 *Khiva* - Historic oasis
+
+*Regional Centers:*
+🇺🇿 *Jizzakh* - Central region
+🇺🇿 *Navoi* - Central mining
+🇺🇿 *Namangan* - Fergana Valley
+🇺🇿 *Margilan* - Silk city
+🇺🇿 *Kokand* - Fergana hub
+🇺🇿 *Gulistan* - Sirdaryo region
+🇺🇿 *Urgench* - Khorezm center
+🇺🇿 *Pop* - Namangan region
+
+💡 *All stations support train connections!*`,
+
+	// Search
+	"search.today_prompt":        "🔍 *Search Trains (Today)*\n\nPlease select your departure station:",
+	"search.confirmation":        "✅ *Search Confirmation*\n\n🚉 From: *%s*\n🎯 To: *%s*\n📅 Date: *%s*\n\n🔍 Searching for trains...",
+	"search.searching":           "🔍 Searching trains from %s to %s on %s...",
+	"search.no_trains_menu":      "❌ No available trains found from *%s* to *%s* on *%s*.\n\nTry:\n• Different dates\n• Alternative station names\n• Use the View Stations button to see available stations",
+	"search.no_trains_command":   "❌ No available trains found from *%s* to *%s* on *%s*.\n\nTry:\n• Different dates\n• Alternative station names\n• Use /stations to see available stations",
+	"search.stale_results.one":   "🚧 The railway service is not responding right now. These trains were found %d minute ago, seats may have changed.\n\n",
+	"search.stale_results.other": "🚧 The railway service is not responding right now. These trains were found %d minutes ago, seats may have changed.\n\n",
+	"search.usage":               "❌ Please provide departure and arrival stations.\n\nExample: `/search Toshkent Samarqand`",
+	"search_date.usage":          "❌ Please provide departure, arrival stations and date.\n\nExample: `/search_date Toshkent Samarqand 2025-01-15`",
+
+	// Round trips
+	"roundtrip.prompt":       "🔁 *Round Trip Search*\n\nSelect your departure date first, then the return date:",
+	"roundtrip.confirmation": "✅ *Round Trip Confirmation*\n\n🚉 From: *%s*\n🎯 To: *%s*\n📅 Departure: *%s*\n🔙 Return: *%s*\n\n🔍 Searching for trains in both directions...",
+	"roundtrip.no_trains":    "❌ No available trains found between *%s* and *%s* in either direction on these dates.\n\nTry different dates.",
+
+	// Flexible dates
+	"flex.prompt":       "📆 *Flexible Dates*\n\nPick a date and I will check %d days before and after it:",
+	"flex.usage":        "❌ Please provide departure and arrival stations.\n\nExamples:\n`/flex Toshkent Buxoro` - the coming week\n`/flex Toshkent Buxoro 2025-01-15` - 3 days around the date\n`/flex Toshkent Buxoro 2025-01-15 5` - 5 days around the date",
+	"flex.invalid_days": "❌ The number of days around the date must be between 0 and %d.",
+	"flex.searching":    "🔍 Checking trains from %s to %s between %s and %s...",
+	"flex.header":       "📆 *%s → %s*\n\n",
+	"flex.footer":       "\n⭐ cheapest day · ✅ seats available · ❌ no seats\n\nTap a day to see its trains.",
+	"flex.no_trains":    "❌ No available trains found from *%s* to *%s* between *%s* and *%s*.\n\nTry other dates.",
+
+	// Connections
+	"connect.usage":     "❌ Please provide departure and arrival stations.\n\nExample: `/connect Nukus Andijon 2025-01-15`",
+	"connect.searching": "🔄 Looking for connections from %s to %s...",
+	"connect.header":    "🔄 *Connections %s → %s on %s*\n\n",
+	"connect.none":      "❌ No connections found from *%s* to *%s* on *%s*.",
+
+	// Train details
+	"train.route":               "🚄 Route: %s → %s\n",
+	"train.seats_header":        "\n💺 *Seat types and prices:*\n",
+	"train.car_seats.one":       "*%s* (%d seat): %s UZS\n",
+	"train.car_seats.other":     "*%s* (%d total seats): %s UZS\n",
+	"train.found.one":           "🚂 *Found %d train:*\n\n",
+	"train.found.other":         "🚂 *Found %d trains:*\n\n",
+	"train.none_found":          "❌ No trains found for your search criteria.",
+	"train.outbound_column":     "Outbound",
+	"train.return_column":       "Return",
+	"train.outbound_header":     "➡️ *Outbound*\n",
+	"train.return_header":       "⬅️ *Return*\n",
+	"train.day_available.one":   "%s %s %s - from %s UZS, %d train\n",
+	"train.day_available.other": "%s %s %s - from %s UZS, %d trains\n",
+	"train.day_sold_out":        "❌ %s %s - no seats\n",
+	"train.day_failed":          "⚠️ %s %s - search failed\n",
+	"train.itinerary":           "*%d.* %s → %s · %s · from %s UZS\n",
+	"train.itinerary_leg":       "   🚆 %s %s: %s %s → %s %s\n",
+	"train.itinerary_transfer":  "   🔄 Change at %s, wait %s\n",
+	"train.duration":            "%dh %02dm",
+
+	// Prices
+	"price.range": "%s - %s UZS",
+	"price.from":  "from %s UZS",
+	"price.up_to": "up to %s UZS",
+	"price.any":   "Any price",
+
+	// Alerts
+	"alert.new_intro":           "🔔 *New Ticket Alert*\n\nI will notify you as soon as matching tickets become available.",
+	"alert.limit_reached.one":   "❌ You already have %d active alert. Pause it with /pause or delete it with /delete before creating a new alert.",
+	"alert.limit_reached.other": "❌ You already have %d active alerts. Pause one with /pause or delete it with /delete before creating a new alert.",
+	"alert.none":                "🔕 You have no alerts yet.\n\nUse /alert to create one.",
+	"alert.list_header.one":     "🔔 *Your alert (%d):*\n\n",
+	"alert.list_header.other":   "🔔 *Your alerts (%d):*\n\n",
+	"alert.list_footer":         "\n💡 Manage alerts with /pause, /resume and /delete followed by the alert ID.",
+	"alert.deleted":             "🗑 Alert `%s` deleted.",
+	"alert.paused":              "⏸ Alert `%s` paused.",
+	"alert.resumed":             "▶️ Alert `%s` resumed.",
+	"alert.id_required":         "❌ Please provide the alert ID.\n\nExample: `/%s a1b2c3d4`\n\nUse /alerts to see your alerts.",
+	"alert.not_found":           "❌ Alert not found. Use /alerts to see your alerts.",
+	"alert.storage_error":       "❌ Could not access your alerts right now. Please try again later.",
+	"alert.status_active":       "🟢 Active",
+	"alert.status_paused":       "⏸ Paused",
+	"alert.any_seat_type":       "Any",
+	"alert.notify_count":        "📨 Notifications sent: %d\n",
+	"alert.last_checked":        "🕐 Last checked: %s\n",
+	"alert.seat_types_prompt":   "✅ Route: *%s → %s*\n\nSelect the seat types you are interested in. Tap several types and then *%s*, or choose *%s*.",
+	"alert.seat_types_selected": "💺 Selected: %s\n\nAdd more or tap %s.",
+	"alert.seat_type_unknown":   "❓ %s is not a seat type. Pick one on the keyboard below.",
+	"alert.direction_prompt":    "🧭 *Which direction should I watch?*\n\n➡️ %s → %s on %s\n⬅️ %s → %s on %s",
+	"alert.min_price_prompt":    "💰 Enter the *minimum* ticket price in UZS, or tap Skip:",
+	"alert.max_price_prompt":    "💰 Enter the *maximum* ticket price in UZS, or tap Skip:",
+	"alert.max_below_min":       "❌ Maximum price cannot be lower than the minimum price. Please try again.",
+	"alert.invalid_price":       "❌ Invalid price. Please enter a number, e.g. `300000`, or tap Skip.",
+	"alert.created_header":      "✅ *Alert created!*\n\n",
+	"alert.created_footer":      "\nI will message you as soon as matching tickets appear.",
+
+	// Alert notifications
+	"notification.header":       "🔔 *Tickets available!*\n\n",
+	"notification.leg_forward":  "➡️ *Outbound trip*\n",
+	"notification.leg_return":   "⬅️ *Return trip*\n",
+	"notification.seats_header": "\n💺 *Matching seats:*\n",
+	"notification.seat.one":     "*%s* %s - %d seat: %s UZS\n",
+	"notification.seat.other":   "*%s* %s - %d seats: %s UZS\n",
+	"notification.alert_id":     "\n🆔 Alert: `%s`",
+}
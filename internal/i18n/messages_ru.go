@@ -0,0 +1,240 @@
+package i18n
+
+var ru = catalog{
+	// Menu buttons
+	"button.search_trains":     "🔍 Поиск поездов",
+	"button.search_by_date":    "📅 Поиск по дате",
+	"button.view_stations":     "🚉 Станции",
+	"button.change_language":   "🌍 Сменить язык",
+	"button.help":              "❓ Помощь",
+	"button.back":              "🔙 Главное меню",
+	"button.nearest_station":   "📍 Ближайшая станция",
+	"button.any_seat_type":     "🎫 Любой тип мест",
+	"button.seats_done":        "✅ Готово",
+	"button.skip":              "⏭ Пропустить",
+	"button.round_trip":        "🔁 Туда и обратно",
+	"button.one_way":           "➡️ Только туда",
+	"button.direction_forward": "➡️ Только туда",
+	"button.direction_return":  "⬅️ Только обратно",
+	"button.direction_both":    "🔁 Оба направления",
+	"button.flexible_dates":    "📆 Гибкие даты",
+
+	// Main menu and help
+	"start.welcome": "🚂 *Добро пожаловать в ChiptaTop!*\n\nЯ помогу мгновенно найти билеты на поезд. Используйте кнопки меню ниже:",
+	"help.text": `🚂 *Помощь по боту ChiptaTop*
+
+🔍 *Как пользоваться:*
+• Используйте кнопки меню для навигации
+• Ищите поезда между любыми станциями
+• Смотрите доступные даты и время
+• Меняйте язык при необходимости
+
+📋 *Доступные функции:*
+• Поиск поездов - поезда на сегодня
+• Поиск по дате - поезда на выбранную дату
+• Туда и обратно - поезда в обе стороны рядом
+• Гибкие даты - места и самая низкая цена по дням вокруг даты (также /flex)
+• Пересадки - маршруты с пересадкой, если нет прямого поезда (/connect)
+• Станции - список всех станций
+• Сменить язык - узбекский/русский/английский
+
+🔔 *Уведомления о билетах:*
+• /alert - уведомить, когда появятся билеты
+• /alerts - список ваших уведомлений
+• /pause, /resume, /delete - управление уведомлением по ID
+
+💡 *Советы:*
+• Поддерживаются все крупные города
+• В результатах видны свободные места и цены
+• Язык определяется автоматически
+• Отправьте геолокацию, чтобы найти ближайшую станцию`,
+	"language.prompt":  "🌍 *Сменить язык*\n\nВыберите язык интерфейса бота:",
+	"language.changed": "🇷🇺 *Язык изменен!*\n\nПереключено на русский язык. Меню и результаты поиска теперь будут на русском.",
+
+	// Errors
+	"error.unknown_command":         "Неизвестная команда. Отправьте /help, чтобы увидеть доступные команды.",
+	"error.not_understood":          "❓ Я вас не понял. Используйте кнопки меню или отправьте запрос в формате:\n\n`откуда куда`\nили\n`откуда куда ГГГГ-ММ-ДД`",
+	"error.invalid_date":            "❌ Неверный формат даты. Используйте формат ГГГГ-ММ-ДД.\n\nПример: `2025-01-15`",
+	"error.auth":                    "❌ Ошибка авторизации\n\nНе удалось авторизоваться в сервисе железной дороги. Попробуйте позже.\n\nЕсли проблема повторяется, сервис может быть временно недоступен.",
+	"error.search_failed":           "❌ Поиск не удался\n\nНе удалось подключиться к сервису железной дороги после нескольких попыток. Возможные причины:\n• Проблемы с сетью\n• Сервис временно недоступен\n• Высокая нагрузка на сервер\n\nПопробуйте снова через несколько минут.",
+	"error.search_unexpected":       "❌ Ошибка поиска\n\nПри поиске поездов произошла непредвиденная ошибка. Попробуйте позже.",
+	"error.rate_limited":            "⏳ Слишком много запросов\n\nСервис железной дороги сейчас перегружен запросами. Попробуйте снова через минуту.",
+	"error.timeout":                 "⌛ Время поиска истекло\n\nСервис железной дороги не ответил вовремя. Попробуйте снова через несколько минут.",
+	"error.service_down":            "🚧 Сервис недоступен\n\nСервис железной дороги сейчас не отвечает. Попробуйте снова через несколько минут.",
+	"error.unknown_station":         "❌ Неизвестная станция: %s. Используйте /stations, чтобы увидеть доступные станции.",
+	"error.unknown_station_suggest": "❌ Неизвестная станция: %s. Возможно, вы имели в виду: %s?",
+	"error.ambiguous_station":       "❓ %s подходит к нескольким станциям: %s. Уточните, пожалуйста.",
+
+	// Nearest station
+	"location.header":  "📍 *Станции рядом с вами:*\n\n",
+	"location.station": "• %s - %.0f км\n",
+	"location.footer":  "\nНажмите на станцию, чтобы выбрать её станцией отправления.",
+	"location.none":    "❌ Станции с известным местоположением не найдены.",
+
+	// Calendar
+	"calendar.title":                  "📅 Выберите дату поездки\n\n%s %d\n\n",
+	"calendar.past_date":              "❌ Нельзя выбрать прошедшую дату. Выберите дату в будущем.",
+	"calendar.date_selected":          "✅ Выбрана дата: %s",
+	"calendar.return_title":           "📅 Выберите дату возвращения\n\n%s %d\n\n",
+	"calendar.return_title_optional":  "📅 Выберите дату возвращения\n\nВыберите дату, чтобы следить и за обратным путем, или нажмите «Только туда».\n\n%s %d\n\n",
+	"calendar.return_date_selected":   "✅ Дата возвращения: %s",
+	"calendar.return_before_outbound": "❌ Дата возвращения не может быть раньше даты отправления (%s). Выберите другую дату.",
+	"calendar.one_way_selected":       "➡️ Поездка в одну сторону",
+	"month.1":                         "Январь",
+	"month.2":                         "Февраль",
+	"month.3":                         "Март",
+	"month.4":                         "Апрель",
+	"month.5":                         "Май",
+	"month.6":                         "Июнь",
+	"month.7":                         "Июль",
+	"month.8":                         "Август",
+	"month.9":                         "Сентябрь",
+	"month.10":                        "Октябрь",
+	"month.11":                        "Ноябрь",
+	"month.12":                        "Декабрь",
+	"weekday.mon":                     "Пн",
+	"weekday.tue":                     "Вт",
+	"weekday.wed":                     "Ср",
+	"weekday.thu":                     "Чт",
+	"weekday.fri":                     "Пт",
+	"weekday.sat":                     "Сб",
+	"weekday.sun":                     "Вс",
+
+	// Stations
+	"station.select_departure":   "Выберите станцию отправления:",
+	"station.departure_selected": "✅ Станция отправления: *%s*\n\nТеперь выберите станцию назначения:",
+	"station.invalid":            "❌ Неверный выбор станции. Попробуйте снова.",
+	"station.did_you_mean":       "🤔 Не удалось найти станцию «%s». Возможно, вы имели в виду одну из этих?",
+	"station.which_one":          "🤔 «%s» подходит к нескольким станциям. Какую вы имели в виду?",
+	"station.chosen":             "✅ %s",
+	"station.same":               "❌ Станции отправления и назначения не могут совпадать. Выберите другую станцию назначения.",
+	"stations.list_header":       "🚉 *Доступные станции:*\n\n",
+	"stations.list_footer":       "\n\n💡 Используйте эти названия в поисковых запросах!",
+	"stations.overview": `🚉 *Доступные станции (всего 16):*
+
+*Крупные города:*
+🇺🇿 *Ташкент* - столица
+🇺🇿 *Самарканд* - исторический центр
+🇺🇿 *Бухара* - древний город
+🇺🇿 *Андижан* - восточный узел
+🇺🇿 *Карши* - южный центр
+🇺🇿 *Термез* - южная граница
+🇺🇿 *Нукус* - Каракалпакстан
+🇺🇿 *Хива* - исторический оазис
+
+*Региональные центры:*
+🇺🇿 *Джизак* - центральный регион
+🇺🇿 *Навои* - горнодобывающий центр
+🇺🇿 *Наманган* - Ферганская долина
+🇺🇿 *Маргилан* - город шелка
+🇺🇿 *Коканд* - узел Ферганской долины
+🇺🇿 *Гулистан* - Сырдарьинская область
+🇺🇿 *Ургенч* - центр Хорезма
+🇺🇿 *Пап* - Наманганская область
+
+💡 *Все станции поддерживают железнодорожное сообщение!*`,
+
+	// Search
+	"search.today_prompt":       "🔍 *Поиск поездов (сегодня)*\n\nВыберите станцию отправления:",
+	"search.confirmation":       "✅ *Подтверждение поиска*\n\n🚉 Откуда: *%s*\n🎯 Куда: *%s*\n📅 Дата: *%s*\n\n🔍 Ищем поезда...",
+	"search.searching":          "🔍 Ищем поезда %s → %s на %s...",
+	"search.no_trains_menu":     "❌ Нет доступных поездов *%s* → *%s* на *%s*.\n\nПопробуйте:\n• Другие даты\n• Другие названия станций\n• Кнопку «Станции», чтобы увидеть доступные станции",
+	"search.no_trains_command":  "❌ Нет доступных поездов *%s* → *%s* на *%s*.\n\nПопробуйте:\n• Другие даты\n• Другие названия станций\n• Команду /stations, чтобы увидеть доступные станции",
+	"search.stale_results.one":  "🚧 Сервис железной дороги сейчас не отвечает. Эти поезда были найдены %d минуту назад, места могли измениться.\n\n",
+	"search.stale_results.few":  "🚧 Сервис железной дороги сейчас не отвечает. Эти поезда были найдены %d минуты назад, места могли измениться.\n\n",
+	"search.stale_results.many": "🚧 Сервис железной дороги сейчас не отвечает. Эти поезда были найдены %d минут назад, места могли измениться.\n\n",
+	"search.usage":              "❌ Укажите станции отправления и прибытия.\n\nПример: `/search Toshkent Samarqand`",
+	"search_date.usage":         "❌ Укажите станции отправления, прибытия и дату.\n\nПример: `/search_date Toshkent Samarqand 2025-01-15`",
+
+	// Round trips
+	"roundtrip.prompt":       "🔁 *Поиск туда и обратно*\n\nСначала выберите дату отправления, затем дату возвращения:",
+	"roundtrip.confirmation": "✅ *Подтверждение поездки туда и обратно*\n\n🚉 Откуда: *%s*\n🎯 Куда: *%s*\n📅 Туда: *%s*\n🔙 Обратно: *%s*\n\n🔍 Ищем поезда в обоих направлениях...",
+	"roundtrip.no_trains":    "❌ Нет доступных поездов между *%s* и *%s* ни в одном направлении на эти даты.\n\nПопробуйте другие даты.",
+
+	// Flexible dates
+	"flex.prompt":       "📆 *Гибкие даты*\n\nВыберите дату, и я проверю %d дня до и после нее:",
+	"flex.usage":        "❌ Укажите станции отправления и прибытия.\n\nПримеры:\n`/flex Toshkent Buxoro` - ближайшая неделя\n`/flex Toshkent Buxoro 2025-01-15` - 3 дня вокруг даты\n`/flex Toshkent Buxoro 2025-01-15 5` - 5 дней вокруг даты",
+	"flex.invalid_days": "❌ Количество дней вокруг даты должно быть от 0 до %d.",
+	"flex.searching":    "🔍 Проверяем поезда %s → %s с %s по %s...",
+	"flex.header":       "📆 *%s → %s*\n\n",
+	"flex.footer":       "\n⭐ самый дешевый день · ✅ есть места · ❌ мест нет\n\nНажмите на день, чтобы увидеть поезда.",
+	"flex.no_trains":    "❌ Нет доступных поездов *%s* → *%s* с *%s* по *%s*.\n\nПопробуйте другие даты.",
+
+	// Connections
+	"connect.usage":     "❌ Укажите станции отправления и прибытия.\n\nПример: `/connect Nukus Andijon 2025-01-15`",
+	"connect.searching": "🔄 Ищу маршруты с пересадкой из %s в %s...",
+	"connect.header":    "🔄 *Маршруты с пересадкой %s → %s на %s*\n\n",
+	"connect.none":      "❌ Маршруты с пересадкой из *%s* в *%s* на *%s* не найдены.",
+
+	// Train details
+	"train.route":              "🚄 Маршрут: %s → %s\n",
+	"train.seats_header":       "\n💺 *Типы мест и цены:*\n",
+	"train.car_seats.one":      "*%s* (%d место): %s сум\n",
+	"train.car_seats.few":      "*%s* (%d места): %s сум\n",
+	"train.car_seats.many":     "*%s* (%d мест): %s сум\n",
+	"train.found.one":          "🚂 *Найден %d поезд:*\n\n",
+	"train.found.few":          "🚂 *Найдено %d поезда:*\n\n",
+	"train.found.many":         "🚂 *Найдено %d поездов:*\n\n",
+	"train.none_found":         "❌ По вашему запросу поездов не найдено.",
+	"train.outbound_column":    "Туда",
+	"train.return_column":      "Обратно",
+	"train.outbound_header":    "➡️ *Туда*\n",
+	"train.return_header":      "⬅️ *Обратно*\n",
+	"train.day_available.one":  "%s %s %s - от %s сум, %d поезд\n",
+	"train.day_available.few":  "%s %s %s - от %s сум, %d поезда\n",
+	"train.day_available.many": "%s %s %s - от %s сум, %d поездов\n",
+	"train.day_sold_out":       "❌ %s %s - мест нет\n",
+	"train.day_failed":         "⚠️ %s %s - ошибка поиска\n",
+	"train.itinerary":          "*%d.* %s → %s · %s · от %s сум\n",
+	"train.itinerary_leg":      "   🚆 %s %s: %s %s → %s %s\n",
+	"train.itinerary_transfer": "   🔄 Пересадка в %s, ожидание %s\n",
+	"train.duration":           "%d ч %02d мин",
+
+	// Prices
+	"price.range": "%s - %s сум",
+	"price.from":  "от %s сум",
+	"price.up_to": "до %s сум",
+	"price.any":   "Любая цена",
+
+	// Alerts
+	"alert.new_intro":           "🔔 *Новое уведомление*\n\nЯ сообщу вам, как только появятся подходящие билеты.",
+	"alert.limit_reached.one":   "❌ У вас уже %d активное уведомление. Приостановите его командой /pause или удалите командой /delete, прежде чем создавать новое.",
+	"alert.limit_reached.few":   "❌ У вас уже %d активных уведомления. Приостановите одно командой /pause или удалите командой /delete, прежде чем создавать новое.",
+	"alert.limit_reached.many":  "❌ У вас уже %d активных уведомлений. Приостановите одно командой /pause или удалите командой /delete, прежде чем создавать новое.",
+	"alert.none":                "🔕 У вас пока нет уведомлений.\n\nСоздайте его командой /alert.",
+	"alert.list_header.one":     "🔔 *Ваши уведомления (%d):*\n\n",
+	"alert.list_header.few":     "🔔 *Ваши уведомления (%d):*\n\n",
+	"alert.list_header.many":    "🔔 *Ваши уведомления (%d):*\n\n",
+	"alert.list_footer":         "\n💡 Управляйте уведомлениями командами /pause, /resume и /delete с ID уведомления.",
+	"alert.deleted":             "🗑 Уведомление `%s` удалено.",
+	"alert.paused":              "⏸ Уведомление `%s` приостановлено.",
+	"alert.resumed":             "▶️ Уведомление `%s` возобновлено.",
+	"alert.id_required":         "❌ Укажите ID уведомления.\n\nПример: `/%s a1b2c3d4`\n\nСписок уведомлений: /alerts",
+	"alert.not_found":           "❌ Уведомление не найдено. Список уведомлений: /alerts",
+	"alert.storage_error":       "❌ Не удалось получить доступ к вашим уведомлениям. Попробуйте позже.",
+	"alert.status_active":       "🟢 Активно",
+	"alert.status_paused":       "⏸ Приостановлено",
+	"alert.any_seat_type":       "Любые",
+	"alert.notify_count":        "📨 Отправлено уведомлений: %d\n",
+	"alert.last_checked":        "🕐 Последняя проверка: %s\n",
+	"alert.seat_types_prompt":   "✅ Маршрут: *%s → %s*\n\nВыберите интересующие типы мест. Нажмите несколько типов, затем *%s*, или выберите *%s*.",
+	"alert.seat_types_selected": "💺 Выбрано: %s\n\nДобавьте еще или нажмите %s.",
+	"alert.seat_type_unknown":   "❓ %s — не тип места. Выберите тип на клавиатуре ниже.",
+	"alert.direction_prompt":    "🧭 *За каким направлением следить?*\n\n➡️ %s → %s, %s\n⬅️ %s → %s, %s",
+	"alert.min_price_prompt":    "💰 Введите *минимальную* цену билета в сумах или нажмите «Пропустить»:",
+	"alert.max_price_prompt":    "💰 Введите *максимальную* цену билета в сумах или нажмите «Пропустить»:",
+	"alert.max_below_min":       "❌ Максимальная цена не может быть ниже минимальной. Попробуйте снова.",
+	"alert.invalid_price":       "❌ Неверная цена. Введите число, например `300000`, или нажмите «Пропустить».",
+	"alert.created_header":      "✅ *Уведомление создано!*\n\n",
+	"alert.created_footer":      "\nЯ напишу вам, как только появятся подходящие билеты.",
+
+	// Alert notifications
+	"notification.header":       "🔔 *Появились билеты!*\n\n",
+	"notification.leg_forward":  "➡️ *Поездка туда*\n",
+	"notification.leg_return":   "⬅️ *Обратная поездка*\n",
+	"notification.seats_header": "\n💺 *Подходящие места:*\n",
+	"notification.seat.one":     "*%s* %s - %d место: %s сум\n",
+	"notification.seat.few":     "*%s* %s - %d места: %s сум\n",
+	"notification.seat.many":    "*%s* %s - %d мест: %s сум\n",
+	"notification.alert_id":     "\n🆔 Уведомление: `%s`",
+}
@@ -0,0 +1,233 @@
+package i18n
+
+var uz = catalog{
+	// Menu buttons
+	"button.search_trains":     "🔍 Poyezd qidirish",
+	"button.search_by_date":    "📅 Sana bo'yicha qidirish",
+	"button.view_stations":     "🚉 Stansiyalar",
+	"button.change_language":   "🌍 Tilni o'zgartirish",
+	"button.help":              "❓ Yordam",
+	"button.back":              "🔙 Bosh menyu",
+	"button.nearest_station":   "📍 Eng yaqin stansiya",
+	"button.any_seat_type":     "🎫 Istalgan joy turi",
+	"button.seats_done":        "✅ Tayyor",
+	"button.skip":              "⏭ O'tkazib yuborish",
+	"button.round_trip":        "🔁 Borish-qaytish",
+	"button.one_way":           "➡️ Faqat borish",
+	"button.direction_forward": "➡️ Faqat borish",
+	"button.direction_return":  "⬅️ Faqat qaytish",
+	"button.direction_both":    "🔁 Ikkala yo'nalish",
+	"button.flexible_dates":    "📆 Moslashuvchan sanalar",
+
+	// Main menu and help
+	"start.welcome": "🚂 *ChiptaTop'ga xush kelibsiz!*\n\nMen sizga poyezd chiptalarini tezda topishda yordam beraman. Quyidagi menyu tugmalaridan foydalaning:",
+	"help.text": `🚂 *ChiptaTop bot bo'yicha yordam*
+
+🔍 *Qanday foydalanish:*
+• Harakatlanish uchun menyu tugmalaridan foydalaning
+• Istalgan stansiyalar orasida poyezd qidiring
+• Mavjud sanalar va vaqtlarni ko'ring
+• Kerak bo'lsa tilni o'zgartiring
+
+📋 *Mavjud imkoniyatlar:*
+• Poyezd qidirish - bugungi poyezdlar
+• Sana bo'yicha qidirish - tanlangan sanadagi poyezdlar
+• Borish-qaytish - ikkala yo'nalishdagi poyezdlar yonma-yon
+• Moslashuvchan sanalar - sana atrofidagi har bir kun uchun joylar va eng arzon narx (/flex ham)
+• Ulanishlar - to'g'ridan-to'g'ri poyezd bo'lmasa, almashib o'tiladigan yo'nalishlar (/connect)
+• Stansiyalar - barcha stansiyalar ro'yxati
+• Tilni o'zgartirish - o'zbek/rus/ingliz tillari
+
+🔔 *Chipta xabarnomalari:*
+• /alert - chiptalar paydo bo'lganda xabar olish
+• /alerts - xabarnomalaringiz ro'yxati
+• /pause, /resume, /delete - xabarnomani ID bo'yicha boshqarish
+
+💡 *Maslahatlar:*
+• Barcha yirik shaharlar qo'llab-quvvatlanadi
+• Natijalarda bo'sh joylar va narxlar ko'rsatiladi
+• Til avtomatik aniqlanadi
+• Eng yaqin stansiyani topish uchun joylashuvingizni yuboring`,
+	"language.prompt":  "🌍 *Tilni o'zgartirish*\n\nBot interfeysi uchun tilni tanlang:",
+	"language.changed": "🇺🇿 *Til o'zgartirildi!*\n\nO'zbek tiliga o'tildi. Menyular va qidiruv natijalari endi o'zbek tilida bo'ladi.",
+
+	// Errors
+	"error.unknown_command":         "Noma'lum buyruq. Mavjud buyruqlarni ko'rish uchun /help yuboring.",
+	"error.not_understood":          "❓ Sizni tushunmadim. Menyu tugmalaridan foydalaning yoki so'rovni quyidagi formatda yuboring:\n\n`qayerdan qayerga`\nyoki\n`qayerdan qayerga YYYY-MM-DD`",
+	"error.invalid_date":            "❌ Sana formati noto'g'ri. YYYY-MM-DD formatidan foydalaning.\n\nMisol: `2025-01-15`",
+	"error.auth":                    "❌ Avtorizatsiya xatosi\n\nTemir yo'l xizmatida avtorizatsiyadan o'tib bo'lmadi. Keyinroq qayta urinib ko'ring.\n\nMuammo takrorlansa, xizmat vaqtincha ishlamayotgan bo'lishi mumkin.",
+	"error.search_failed":           "❌ Qidiruv amalga oshmadi\n\nBir necha urinishdan so'ng temir yo'l xizmatiga ulanib bo'lmadi. Sabablari:\n• Tarmoq bilan bog'liq muammolar\n• Xizmat vaqtincha ishlamayapti\n• Serverga yuklama yuqori\n\nBir necha daqiqadan so'ng qayta urinib ko'ring.",
+	"error.search_unexpected":       "❌ Qidiruv xatosi\n\nPoyezdlarni qidirishda kutilmagan xato yuz berdi. Keyinroq qayta urinib ko'ring.",
+	"error.rate_limited":            "⏳ So'rovlar juda ko'p\n\nTemir yo'l xizmatiga hozir juda ko'p so'rov kelmoqda. Bir daqiqadan so'ng qayta urinib ko'ring.",
+	"error.timeout":                 "⌛ Qidiruv vaqti tugadi\n\nTemir yo'l xizmati o'z vaqtida javob bermadi. Birozdan so'ng qayta urinib ko'ring.",
+	"error.service_down":            "🚧 Xizmat mavjud emas\n\nTemir yo'l xizmati hozir javob bermayapti. Bir necha daqiqadan so'ng qayta urinib ko'ring.",
+	"error.unknown_station":         "❌ Noma'lum stansiya: %s. Mavjud stansiyalarni ko'rish uchun /stations dan foydalaning.",
+	"error.unknown_station_suggest": "❌ Noma'lum stansiya: %s. Balki siz buni nazarda tutgandirsiz: %s?",
+	"error.ambiguous_station":       "❓ %s bir nechta stansiyaga mos keladi: %s. Iltimos, aniqroq yozing.",
+
+	// Nearest station
+	"location.header":  "📍 *Sizga yaqin stansiyalar:*\n\n",
+	"location.station": "• %s - %.0f km\n",
+	"location.footer":  "\nJo'nash stansiyasi sifatida tanlash uchun stansiyani bosing.",
+	"location.none":    "❌ Joylashuvi ma'lum stansiyalar topilmadi.",
+
+	// Calendar
+	"calendar.title":                  "📅 Sayohat sanasini tanlang\n\n%s %d\n\n",
+	"calendar.past_date":              "❌ O'tgan sanani tanlab bo'lmaydi. Kelgusi sanani tanlang.",
+	"calendar.date_selected":          "✅ Tanlangan sana: %s",
+	"calendar.return_title":           "📅 Qaytish sanasini tanlang\n\n%s %d\n\n",
+	"calendar.return_title_optional":  "📅 Qaytish sanasini tanlang\n\nQaytish yo'lini ham kuzatish uchun sanani tanlang yoki «Faqat borish» tugmasini bosing.\n\n%s %d\n\n",
+	"calendar.return_date_selected":   "✅ Qaytish sanasi: %s",
+	"calendar.return_before_outbound": "❌ Qaytish sanasi jo'nash sanasidan (%s) oldin bo'lishi mumkin emas. Boshqa sanani tanlang.",
+	"calendar.one_way_selected":       "➡️ Bir tomonlama safar",
+	"month.1":                         "Yanvar",
+	"month.2":                         "Fevral",
+	"month.3":                         "Mart",
+	"month.4":                         "Aprel",
+	"month.5":                         "May",
+	"month.6":                         "Iyun",
+	"month.7":                         "Iyul",
+	"month.8":                         "Avgust",
+	"month.9":                         "Sentabr",
+	"month.10":                        "Oktabr",
+	"month.11":                        "Noyabr",
+	"month.12":                        "Dekabr",
+	"weekday.mon":                     "Du",
+	"weekday.tue":                     "Se",
+	"weekday.wed":                     "Ch",
+	"weekday.thu":                     "Pa",
+	"weekday.fri":                     "Ju",
+	"weekday.sat":                     "Sh",
+	"weekday.sun":                     "Ya",
+
+	// Stations
+	"station.select_departure":   "Jo'nash stansiyasini tanlang:",
+	"station.departure_selected": "✅ Jo'nash stansiyasi: *%s*\n\nEndi borish stansiyasini tanlang:",
+	"station.invalid":            "❌ Stansiya noto'g'ri tanlandi. Qayta urinib ko'ring.",
+	"station.did_you_mean":       "🤔 \"%s\" stansiyasi topilmadi. Balki shulardan birini nazarda tutgandirsiz?",
+	"station.which_one":          "🤔 \"%s\" bir nechta stansiyaga mos keladi. Qaysi birini nazarda tutdingiz?",
+	"station.chosen":             "✅ %s",
+	"station.same":               "❌ Jo'nash va borish stansiyalari bir xil bo'lishi mumkin emas. Boshqa borish stansiyasini tanlang.",
+	"stations.list_header":       "🚉 *Mavjud stansiyalar:*\n\n",
+	"stations.list_footer":       "\n\n💡 Qidiruv so'rovlarida ushbu nomlardan foydalaning!",
+	"stations.overview": `🚉 *Mavjud stansiyalar (jami 16 ta):*
+
+*Yirik shaharlar:*
+🇺🇿 *Toshkent* - poytaxt
+🇺🇿 *Samarqand* - tarixiy markaz
+🇺🇿 *Buxoro* - qadimiy shahar
+🇺🇿 *Andijon* - sharqiy markaz
+🇺🇿 *Qarshi* - janubiy markaz
+🇺🇿 *Termiz* - janubiy chegara
+🇺🇿 *Nukus* - Qoraqalpog'iston
+🇺🇿 *Xiva* - tarixiy voha
+
+*Viloyat markazlari:*
+🇺🇿 *Jizzax* - markaziy hudud
+🇺🇿 *Navoiy* - tog'-kon markazi
+🇺🇿 *Namangan* - Farg'ona vodiysi
+🇺🇿 *Marg'ilon* - ipak shahri
+🇺🇿 *Qo'qon* - Farg'ona vodiysi markazi
+🇺🇿 *Guliston* - Sirdaryo viloyati
+🇺🇿 *Urganch* - Xorazm markazi
+🇺🇿 *Pop* - Namangan viloyati
+
+💡 *Barcha stansiyalarda poyezd qatnovi mavjud!*`,
+
+	// Search
+	"search.today_prompt":        "🔍 *Poyezd qidirish (bugun)*\n\nJo'nash stansiyasini tanlang:",
+	"search.confirmation":        "✅ *Qidiruvni tasdiqlash*\n\n🚉 Qayerdan: *%s*\n🎯 Qayerga: *%s*\n📅 Sana: *%s*\n\n🔍 Poyezdlar qidirilmoqda...",
+	"search.searching":           "🔍 %s → %s, %s sanasi uchun poyezdlar qidirilmoqda...",
+	"search.no_trains_menu":      "❌ *%s* → *%s* yo'nalishida *%s* sanasiga poyezdlar topilmadi.\n\nQuyidagilarni sinab ko'ring:\n• Boshqa sanalar\n• Boshqa stansiya nomlari\n• Mavjud stansiyalarni ko'rish uchun «Stansiyalar» tugmasi",
+	"search.no_trains_command":   "❌ *%s* → *%s* yo'nalishida *%s* sanasiga poyezdlar topilmadi.\n\nQuyidagilarni sinab ko'ring:\n• Boshqa sanalar\n• Boshqa stansiya nomlari\n• Mavjud stansiyalarni ko'rish uchun /stations buyrug'i",
+	"search.stale_results.one":   "🚧 Temir yo'l xizmati hozir javob bermayapti. Bu poyezdlar %d daqiqa oldin topilgan, joylar o'zgargan bo'lishi mumkin.\n\n",
+	"search.stale_results.other": "🚧 Temir yo'l xizmati hozir javob bermayapti. Bu poyezdlar %d daqiqa oldin topilgan, joylar o'zgargan bo'lishi mumkin.\n\n",
+	"search.usage":               "❌ Jo'nash va borish stansiyalarini kiriting.\n\nMisol: `/search Toshkent Samarqand`",
+	"search_date.usage":          "❌ Jo'nash va borish stansiyalari hamda sanani kiriting.\n\nMisol: `/search_date Toshkent Samarqand 2025-01-15`",
+
+	// Round trips
+	"roundtrip.prompt":       "🔁 *Borish-qaytish qidiruvi*\n\nAvval jo'nash sanasini, so'ng qaytish sanasini tanlang:",
+	"roundtrip.confirmation": "✅ *Borish-qaytishni tasdiqlash*\n\n🚉 Qayerdan: *%s*\n🎯 Qayerga: *%s*\n📅 Borish: *%s*\n🔙 Qaytish: *%s*\n\n🔍 Ikkala yo'nalishda poyezdlar qidirilmoqda...",
+	"roundtrip.no_trains":    "❌ Bu sanalarda *%s* va *%s* orasida hech bir yo'nalishda poyezdlar topilmadi.\n\nBoshqa sanalarni sinab ko'ring.",
+
+	// Flexible dates
+	"flex.prompt":       "📆 *Moslashuvchan sanalar*\n\nSanani tanlang, men undan %d kun oldin va keyingi kunlarni tekshiraman:",
+	"flex.usage":        "❌ Jo'nash va borish stansiyalarini kiriting.\n\nMisollar:\n`/flex Toshkent Buxoro` - kelgusi hafta\n`/flex Toshkent Buxoro 2025-01-15` - sana atrofidagi 3 kun\n`/flex Toshkent Buxoro 2025-01-15 5` - sana atrofidagi 5 kun",
+	"flex.invalid_days": "❌ Sana atrofidagi kunlar soni 0 dan %d gacha bo'lishi kerak.",
+	"flex.searching":    "🔍 %s → %s poyezdlari %s dan %s gacha tekshirilmoqda...",
+	"flex.header":       "📆 *%s → %s*\n\n",
+	"flex.footer":       "\n⭐ eng arzon kun · ✅ joylar bor · ❌ joy yo'q\n\nPoyezdlarni ko'rish uchun kunni bosing.",
+	"flex.no_trains":    "❌ *%s* → *%s* yo'nalishida *%s* dan *%s* gacha poyezdlar topilmadi.\n\nBoshqa sanalarni sinab ko'ring.",
+
+	// Connections
+	"connect.usage":     "❌ Iltimos, jo'nash va yetib borish stansiyalarini kiriting.\n\nMisol: `/connect Nukus Andijon 2025-01-15`",
+	"connect.searching": "🔄 %s dan %s ga almashib o'tiladigan yo'nalishlar qidirilmoqda...",
+	"connect.header":    "🔄 *Almashib o'tish bilan %s → %s, %s*\n\n",
+	"connect.none":      "❌ *%s* dan *%s* ga *%s* sanasida almashib o'tiladigan yo'nalish topilmadi.",
+
+	// Train details
+	"train.route":               "🚄 Yo'nalish: %s → %s\n",
+	"train.seats_header":        "\n💺 *Joy turlari va narxlar:*\n",
+	"train.car_seats.one":       "*%s* (%d ta joy): %s so'm\n",
+	"train.car_seats.other":     "*%s* (jami %d ta joy): %s so'm\n",
+	"train.found.one":           "🚂 *%d ta poyezd topildi:*\n\n",
+	"train.found.other":         "🚂 *%d ta poyezd topildi:*\n\n",
+	"train.none_found":          "❌ So'rovingiz bo'yicha poyezdlar topilmadi.",
+	"train.outbound_column":     "Borish",
+	"train.return_column":       "Qaytish",
+	"train.outbound_header":     "➡️ *Borish*\n",
+	"train.return_header":       "⬅️ *Qaytish*\n",
+	"train.day_available.one":   "%s %s %s - %s so'mdan, %d ta poyezd\n",
+	"train.day_available.other": "%s %s %s - %s so'mdan, %d ta poyezd\n",
+	"train.day_sold_out":        "❌ %s %s - joy yo'q\n",
+	"train.day_failed":          "⚠️ %s %s - qidiruv xatosi\n",
+	"train.itinerary":           "*%d.* %s → %s · %s · %s so'mdan\n",
+	"train.itinerary_leg":       "   🚆 %s %s: %s %s → %s %s\n",
+	"train.itinerary_transfer":  "   🔄 %s da almashish, kutish %s\n",
+	"train.duration":            "%d soat %02d daq",
+
+	// Prices
+	"price.range": "%s - %s so'm",
+	"price.from":  "%s so'mdan",
+	"price.up_to": "%s so'mgacha",
+	"price.any":   "Istalgan narx",
+
+	// Alerts
+	"alert.new_intro":           "🔔 *Yangi xabarnoma*\n\nMos chiptalar paydo bo'lishi bilan sizga xabar beraman.",
+	"alert.limit_reached.one":   "❌ Sizda allaqachon %d ta faol xabarnoma bor. Yangisini yaratishdan oldin uni /pause bilan to'xtating yoki /delete bilan o'chiring.",
+	"alert.limit_reached.other": "❌ Sizda allaqachon %d ta faol xabarnoma bor. Yangisini yaratishdan oldin bittasini /pause bilan to'xtating yoki /delete bilan o'chiring.",
+	"alert.none":                "🔕 Sizda hali xabarnomalar yo'q.\n\nYaratish uchun /alert yuboring.",
+	"alert.list_header.one":     "🔔 *Sizning xabarnomangiz (%d):*\n\n",
+	"alert.list_header.other":   "🔔 *Sizning xabarnomalaringiz (%d):*\n\n",
+	"alert.list_footer":         "\n💡 Xabarnomalarni /pause, /resume va /delete buyruqlari hamda xabarnoma ID si bilan boshqaring.",
+	"alert.deleted":             "🗑 `%s` xabarnomasi o'chirildi.",
+	"alert.paused":              "⏸ `%s` xabarnomasi to'xtatildi.",
+	"alert.resumed":             "▶️ `%s` xabarnomasi qayta yoqildi.",
+	"alert.id_required":         "❌ Xabarnoma ID sini kiriting.\n\nMisol: `/%s a1b2c3d4`\n\nXabarnomalaringiz ro'yxati: /alerts",
+	"alert.not_found":           "❌ Xabarnoma topilmadi. Xabarnomalaringiz ro'yxati: /alerts",
+	"alert.storage_error":       "❌ Hozir xabarnomalaringizga kirib bo'lmadi. Keyinroq qayta urinib ko'ring.",
+	"alert.status_active":       "🟢 Faol",
+	"alert.status_paused":       "⏸ To'xtatilgan",
+	"alert.any_seat_type":       "Istalgan",
+	"alert.notify_count":        "📨 Yuborilgan xabarlar: %d\n",
+	"alert.last_checked":        "🕐 Oxirgi tekshiruv: %s\n",
+	"alert.seat_types_prompt":   "✅ Yo'nalish: *%s → %s*\n\nSizni qiziqtirgan joy turlarini tanlang. Bir nechta turni belgilab, so'ng *%s* tugmasini bosing yoki *%s* ni tanlang.",
+	"alert.seat_types_selected": "💺 Tanlangan: %s\n\nYana qo'shing yoki %s tugmasini bosing.",
+	"alert.seat_type_unknown":   "❓ %s joy turi emas. Quyidagi klaviaturadan tanlang.",
+	"alert.direction_prompt":    "🧭 *Qaysi yo'nalishni kuzatay?*\n\n➡️ %s → %s, %s\n⬅️ %s → %s, %s",
+	"alert.min_price_prompt":    "💰 Chiptaning *eng kam* narxini so'mda kiriting yoki «O'tkazib yuborish» tugmasini bosing:",
+	"alert.max_price_prompt":    "💰 Chiptaning *eng yuqori* narxini so'mda kiriting yoki «O'tkazib yuborish» tugmasini bosing:",
+	"alert.max_below_min":       "❌ Eng yuqori narx eng kam narxdan past bo'lishi mumkin emas. Qayta urinib ko'ring.",
+	"alert.invalid_price":       "❌ Narx noto'g'ri. Raqam kiriting, masalan `300000`, yoki «O'tkazib yuborish» tugmasini bosing.",
+	"alert.created_header":      "✅ *Xabarnoma yaratildi!*\n\n",
+	"alert.created_footer":      "\nMos chiptalar paydo bo'lishi bilan sizga yozaman.",
+
+	// Alert notifications
+	"notification.header":       "🔔 *Chiptalar paydo bo'ldi!*\n\n",
+	"notification.leg_forward":  "➡️ *Borish safari*\n",
+	"notification.leg_return":   "⬅️ *Qaytish safari*\n",
+	"notification.seats_header": "\n💺 *Mos joylar:*\n",
+	"notification.seat.one":     "*%s* %s - %d ta joy: %s so'm\n",
+	"notification.seat.other":   "*%s* %s - %d ta joy: %s so'm\n",
+	"notification.alert_id":     "\n🆔 Xabarnoma: `%s`",
+}
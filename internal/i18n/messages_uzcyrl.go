@@ -0,0 +1,233 @@
+package i18n
+
+var uzCyrl = catalog{
+	// Menu buttons
+	"button.search_trains":     "🔍 Поезд қидириш",
+	"button.search_by_date":    "📅 Сана бўйича қидириш",
+	"button.view_stations":     "🚉 Станциялар",
+	"button.change_language":   "🌍 Тилни ўзгартириш",
+	"button.help":              "❓ Ёрдам",
+	"button.back":              "🔙 Бош меню",
+	"button.nearest_station":   "📍 Энг яқин станция",
+	"button.any_seat_type":     "🎫 Исталган жой тури",
+	"button.seats_done":        "✅ Тайёр",
+	"button.skip":              "⏭ Ўтказиб юбориш",
+	"button.round_trip":        "🔁 Бориш-қайтиш",
+	"button.one_way":           "➡️ Фақат бориш",
+	"button.direction_forward": "➡️ Фақат бориш",
+	"button.direction_return":  "⬅️ Фақат қайтиш",
+	"button.direction_both":    "🔁 Иккала йўналиш",
+	"button.flexible_dates":    "📆 Мослашувчан саналар",
+
+	// Main menu and help
+	"start.welcome": "🚂 *ChiptaTop'га хуш келибсиз!*\n\nМен сизга поезд чипталарини тезда топишда ёрдам бераман. Қуйидаги меню тугмаларидан фойдаланинг:",
+	"help.text": `🚂 *ChiptaTop бот бўйича ёрдам*
+
+🔍 *Қандай фойдаланиш:*
+• Ҳаракатланиш учун меню тугмаларидан фойдаланинг
+• Исталган станциялар орасида поезд қидиринг
+• Мавжуд саналар ва вақтларни кўринг
+• Керак бўлса тилни ўзгартиринг
+
+📋 *Мавжуд имкониятлар:*
+• Поезд қидириш - бугунги поездлар
+• Сана бўйича қидириш - танланган санадаги поездлар
+• Бориш-қайтиш - иккала йўналишдаги поездлар ёнма-ён
+• Мослашувчан саналар - сана атрофидаги ҳар бир кун учун жойлар ва энг арзон нарх (/flex ҳам)
+• Уланишлар - тўғридан-тўғри поезд бўлмаса, алмашиб ўтиладиган йўналишлар (/connect)
+• Станциялар - барча станциялар рўйхати
+• Тилни ўзгартириш - ўзбек/рус/инглиз тиллари
+
+🔔 *Чипта хабарномалари:*
+• /alert - чипталар пайдо бўлганда хабар олиш
+• /alerts - хабарномаларингиз рўйхати
+• /pause, /resume, /delete - хабарномани ID бўйича бошқариш
+
+💡 *Маслаҳатлар:*
+• Барча йирик шаҳарлар қўллаб-қувватланади
+• Натижаларда бўш жойлар ва нархлар кўрсатилади
+• Тил автоматик аниқланади
+• Энг яқин станцияни топиш учун жойлашувингизни юборинг`,
+	"language.prompt":  "🌍 *Тилни ўзгартириш*\n\nБот интерфейси учун тилни танланг:",
+	"language.changed": "🇺🇿 *Тил ўзгартирилди!*\n\nЎзбек тилига (кирилл) ўтилди. Менюлар ва қидирув натижалари энди ўзбек тилида бўлади.",
+
+	// Errors
+	"error.unknown_command":         "Номаълум буйруқ. Мавжуд буйруқларни кўриш учун /help юборинг.",
+	"error.not_understood":          "❓ Сизни тушунмадим. Меню тугмаларидан фойдаланинг ёки сўровни қуйидаги форматда юборинг:\n\n`қаердан қаерга`\nёки\n`қаердан қаерга YYYY-MM-DD`",
+	"error.invalid_date":            "❌ Сана формати нотўғри. YYYY-MM-DD форматидан фойдаланинг.\n\nМисол: `2025-01-15`",
+	"error.auth":                    "❌ Авторизация хатоси\n\nТемир йўл хизматида авторизациядан ўтиб бўлмади. Кейинроқ қайта уриниб кўринг.\n\nМуаммо такрорланса, хизмат вақтинча ишламаётган бўлиши мумкин.",
+	"error.search_failed":           "❌ Қидирув амалга ошмади\n\nБир неча уринишдан сўнг темир йўл хизматига уланиб бўлмади. Сабаблари:\n• Тармоқ билан боғлиқ муаммолар\n• Хизмат вақтинча ишламаяпти\n• Серверга юклама юқори\n\nБир неча дақиқадан сўнг қайта уриниб кўринг.",
+	"error.search_unexpected":       "❌ Қидирув хатоси\n\nПоездларни қидиришда кутилмаган хато юз берди. Кейинроқ қайта уриниб кўринг.",
+	"error.rate_limited":            "⏳ Сўровлар жуда кўп\n\nТемир йўл хизматига ҳозир жуда кўп сўров келмоқда. Бир дақиқадан сўнг қайта уриниб кўринг.",
+	"error.timeout":                 "⌛ Қидирув вақти тугади\n\nТемир йўл хизмати ўз вақтида жавоб бермади. Бироздан сўнг қайта уриниб кўринг.",
+	"error.service_down":            "🚧 Хизмат мавжуд эмас\n\nТемир йўл хизмати ҳозир жавоб бермаяпти. Бир неча дақиқадан сўнг қайта уриниб кўринг.",
+	"error.unknown_station":         "❌ Номаълум станция: %s. Мавжуд станцияларни кўриш учун /stations дан фойдаланинг.",
+	"error.unknown_station_suggest": "❌ Номаълум станция: %s. Балки сиз буни назарда тутгандирсиз: %s?",
+	"error.ambiguous_station":       "❓ %s бир нечта станцияга мос келади: %s. Илтимос, аниқроқ ёзинг.",
+
+	// Nearest station
+	"location.header":  "📍 *Сизга яқин станциялар:*\n\n",
+	"location.station": "• %s - %.0f км\n",
+	"location.footer":  "\nЖўнаш станцияси сифатида танлаш учун станцияни босинг.",
+	"location.none":    "❌ Жойлашуви маълум станциялар топилмади.",
+
+	// Calendar
+	"calendar.title":                  "📅 Саёҳат санасини танланг\n\n%s %d\n\n",
+	"calendar.past_date":              "❌ Ўтган санани танлаб бўлмайди. Келгуси санани танланг.",
+	"calendar.date_selected":          "✅ Танланган сана: %s",
+	"calendar.return_title":           "📅 Қайтиш санасини танланг\n\n%s %d\n\n",
+	"calendar.return_title_optional":  "📅 Қайтиш санасини танланг\n\nҚайтиш йўлини ҳам кузатиш учун санани танланг ёки «Фақат бориш» тугмасини босинг.\n\n%s %d\n\n",
+	"calendar.return_date_selected":   "✅ Қайтиш санаси: %s",
+	"calendar.return_before_outbound": "❌ Қайтиш санаси жўнаш санасидан (%s) олдин бўлиши мумкин эмас. Бошқа санани танланг.",
+	"calendar.one_way_selected":       "➡️ Бир томонлама сафар",
+	"month.1":                         "Январ",
+	"month.2":                         "Феврал",
+	"month.3":                         "Март",
+	"month.4":                         "Апрел",
+	"month.5":                         "Май",
+	"month.6":                         "Июн",
+	"month.7":                         "Июл",
+	"month.8":                         "Август",
+	"month.9":                         "Сентябр",
+	"month.10":                        "Октябр",
+	"month.11":                        "Ноябр",
+	"month.12":                        "Декабр",
+	"weekday.mon":                     "Ду",
+	"weekday.tue":                     "Се",
+	"weekday.wed":                     "Чо",
+	"weekday.thu":                     "Па",
+	"weekday.fri":                     "Жу",
+	"weekday.sat":                     "Ша",
+	"weekday.sun":                     "Як",
+
+	// Stations
+	"station.select_departure":   "Жўнаш станциясини танланг:",
+	"station.departure_selected": "✅ Жўнаш станцияси: *%s*\n\nЭнди бориш станциясини танланг:",
+	"station.invalid":            "❌ Станция нотўғри танланди. Қайта уриниб кўринг.",
+	"station.did_you_mean":       "🤔 \"%s\" станцияси топилмади. Балки шулардан бирини назарда тутгандирсиз?",
+	"station.which_one":          "🤔 \"%s\" бир нечта станцияга мос келади. Қайси бирини назарда тутдингиз?",
+	"station.chosen":             "✅ %s",
+	"station.same":               "❌ Жўнаш ва бориш станциялари бир хил бўлиши мумкин эмас. Бошқа бориш станциясини танланг.",
+	"stations.list_header":       "🚉 *Мавжуд станциялар:*\n\n",
+	"stations.list_footer":       "\n\n💡 Қидирув сўровларида ушбу номлардан фойдаланинг!",
+	"stations.overview": `🚉 *Мавжуд станциялар (жами 16 та):*
+
+*Йирик шаҳарлар:*
+🇺🇿 *Тошкент* - пойтахт
+🇺🇿 *Самарқанд* - тарихий марказ
+🇺🇿 *Бухоро* - қадимий шаҳар
+🇺🇿 *Андижон* - шарқий марказ
+🇺🇿 *Қарши* - жанубий марказ
+🇺🇿 *Термиз* - жанубий чегара
+🇺🇿 *Нукус* - Қорақалпоғистон
+🇺🇿 *Хива* - тарихий воҳа
+
+*Вилоят марказлари:*
+🇺🇿 *Жиззах* - марказий ҳудуд
+🇺🇿 *Навоий* - тоғ-кон маркази
+🇺🇿 *Наманган* - Фарғона водийси
+🇺🇿 *Марғилон* - ипак шаҳри
+🇺🇿 *Қўқон* - Фарғона водийси маркази
+🇺🇿 *Гулистон* - Сирдарё вилояти
+🇺🇿 *Урганч* - Хоразм маркази
+🇺🇿 *Поп* - Наманган вилояти
+
+💡 *Барча станцияларда поезд қатнови мавжуд!*`,
+
+	// Search
+	"search.today_prompt":        "🔍 *Поезд қидириш (бугун)*\n\nЖўнаш станциясини танланг:",
+	"search.confirmation":        "✅ *Қидирувни тасдиқлаш*\n\n🚉 Қаердан: *%s*\n🎯 Қаерга: *%s*\n📅 Сана: *%s*\n\n🔍 Поездлар қидирилмоқда...",
+	"search.searching":           "🔍 %s → %s, %s санаси учун поездлар қидирилмоқда...",
+	"search.no_trains_menu":      "❌ *%s* → *%s* йўналишида *%s* санасига поездлар топилмади.\n\nҚуйидагиларни синаб кўринг:\n• Бошқа саналар\n• Бошқа станция номлари\n• Мавжуд станцияларни кўриш учун «Станциялар» тугмаси",
+	"search.no_trains_command":   "❌ *%s* → *%s* йўналишида *%s* санасига поездлар топилмади.\n\nҚуйидагиларни синаб кўринг:\n• Бошқа саналар\n• Бошқа станция номлари\n• Мавжуд станцияларни кўриш учун /stations буйруғи",
+	"search.stale_results.one":   "🚧 Темир йўл хизмати ҳозир жавоб бермаяпти. Бу поездлар %d дақиқа олдин топилган, жойлар ўзгарган бўлиши мумкин.\n\n",
+	"search.stale_results.other": "🚧 Темир йўл хизмати ҳозир жавоб бермаяпти. Бу поездлар %d дақиқа олдин топилган, жойлар ўзгарган бўлиши мумкин.\n\n",
+	"search.usage":               "❌ Жўнаш ва бориш станцияларини киритинг.\n\nМисол: `/search Toshkent Samarqand`",
+	"search_date.usage":          "❌ Жўнаш ва бориш станциялари ҳамда санани киритинг.\n\nМисол: `/search_date Toshkent Samarqand 2025-01-15`",
+
+	// Round trips
+	"roundtrip.prompt":       "🔁 *Бориш-қайтиш қидируви*\n\nАввал жўнаш санасини, сўнг қайтиш санасини танланг:",
+	"roundtrip.confirmation": "✅ *Бориш-қайтишни тасдиқлаш*\n\n🚉 Қаердан: *%s*\n🎯 Қаерга: *%s*\n📅 Бориш: *%s*\n🔙 Қайтиш: *%s*\n\n🔍 Иккала йўналишда поездлар қидирилмоқда...",
+	"roundtrip.no_trains":    "❌ Бу саналарда *%s* ва *%s* орасида ҳеч бир йўналишда поездлар топилмади.\n\nБошқа саналарни синаб кўринг.",
+
+	// Flexible dates
+	"flex.prompt":       "📆 *Мослашувчан саналар*\n\nСанани танланг, мен ундан %d кун олдин ва кейинги кунларни текшираман:",
+	"flex.usage":        "❌ Жўнаш ва бориш станцияларини киритинг.\n\nМисоллар:\n`/flex Toshkent Buxoro` - келгуси ҳафта\n`/flex Toshkent Buxoro 2025-01-15` - сана атрофидаги 3 кун\n`/flex Toshkent Buxoro 2025-01-15 5` - сана атрофидаги 5 кун",
+	"flex.invalid_days": "❌ Сана атрофидаги кунлар сони 0 дан %d гача бўлиши керак.",
+	"flex.searching":    "🔍 %s → %s поездлари %s дан %s гача текширилмоқда...",
+	"flex.header":       "📆 *%s → %s*\n\n",
+	"flex.footer":       "\n⭐ энг арзон кун · ✅ жойлар бор · ❌ жой йўқ\n\nПоездларни кўриш учун кунни босинг.",
+	"flex.no_trains":    "❌ *%s* → *%s* йўналишида *%s* дан *%s* гача поездлар топилмади.\n\nБошқа саналарни синаб кўринг.",
+
+	// Connections
+	"connect.usage":     "❌ Илтимос, жўнаш ва етиб бориш станцияларини киритинг.\n\nМисол: `/connect Nukus Andijon 2025-01-15`",
+	"connect.searching": "🔄 %s дан %s га алмашиб ўтиладиган йўналишлар қидирилмоқда...",
+	"connect.header":    "🔄 *Алмашиб ўтиш билан %s → %s, %s*\n\n",
+	"connect.none":      "❌ *%s* дан *%s* га *%s* санасида алмашиб ўтиладиган йўналиш топилмади.",
+
+	// Train details
+	"train.route":               "🚄 Йўналиш: %s → %s\n",
+	"train.seats_header":        "\n💺 *Жой турлари ва нархлар:*\n",
+	"train.car_seats.one":       "*%s* (%d та жой): %s сўм\n",
+	"train.car_seats.other":     "*%s* (жами %d та жой): %s сўм\n",
+	"train.found.one":           "🚂 *%d та поезд топилди:*\n\n",
+	"train.found.other":         "🚂 *%d та поезд топилди:*\n\n",
+	"train.none_found":          "❌ Сўровингиз бўйича поездлар топилмади.",
+	"train.outbound_column":     "Бориш",
+	"train.return_column":       "Қайтиш",
+	"train.outbound_header":     "➡️ *Бориш*\n",
+	"train.return_header":       "⬅️ *Қайтиш*\n",
+	"train.day_available.one":   "%s %s %s - %s сўмдан, %d та поезд\n",
+	"train.day_available.other": "%s %s %s - %s сўмдан, %d та поезд\n",
+	"train.day_sold_out":        "❌ %s %s - жой йўқ\n",
+	"train.day_failed":          "⚠️ %s %s - қидирув хатоси\n",
+	"train.itinerary":           "*%d.* %s → %s · %s · %s сўмдан\n",
+	"train.itinerary_leg":       "   🚆 %s %s: %s %s → %s %s\n",
+	"train.itinerary_transfer":  "   🔄 %s да алмашиш, кутиш %s\n",
+	"train.duration":            "%d соат %02d дақ",
+
+	// Prices
+	"price.range": "%s - %s сўм",
+	"price.from":  "%s сўмдан",
+	"price.up_to": "%s сўмгача",
+	"price.any":   "Исталган нарх",
+
+	// Alerts
+	"alert.new_intro":           "🔔 *Янги хабарнома*\n\nМос чипталар пайдо бўлиши билан сизга хабар бераман.",
+	"alert.limit_reached.one":   "❌ Сизда аллақачон %d та фаол хабарнома бор. Янгисини яратишдан олдин уни /pause билан тўхтатинг ёки /delete билан ўчиринг.",
+	"alert.limit_reached.other": "❌ Сизда аллақачон %d та фаол хабарнома бор. Янгисини яратишдан олдин биттасини /pause билан тўхтатинг ёки /delete билан ўчиринг.",
+	"alert.none":                "🔕 Сизда ҳали хабарномалар йўқ.\n\nЯратиш учун /alert юборинг.",
+	"alert.list_header.one":     "🔔 *Сизнинг хабарномангиз (%d):*\n\n",
+	"alert.list_header.other":   "🔔 *Сизнинг хабарномаларингиз (%d):*\n\n",
+	"alert.list_footer":         "\n💡 Хабарномаларни /pause, /resume ва /delete буйруқлари ҳамда хабарнома ID си билан бошқаринг.",
+	"alert.deleted":             "🗑 `%s` хабарномаси ўчирилди.",
+	"alert.paused":              "⏸ `%s` хабарномаси тўхтатилди.",
+	"alert.resumed":             "▶️ `%s` хабарномаси қайта ёқилди.",
+	"alert.id_required":         "❌ Хабарнома ID сини киритинг.\n\nМисол: `/%s a1b2c3d4`\n\nХабарномаларингиз рўйхати: /alerts",
+	"alert.not_found":           "❌ Хабарнома топилмади. Хабарномаларингиз рўйхати: /alerts",
+	"alert.storage_error":       "❌ Ҳозир хабарномаларингизга кириб бўлмади. Кейинроқ қайта уриниб кўринг.",
+	"alert.status_active":       "🟢 Фаол",
+	"alert.status_paused":       "⏸ Тўхтатилган",
+	"alert.any_seat_type":       "Исталган",
+	"alert.notify_count":        "📨 Юборилган хабарлар: %d\n",
+	"alert.last_checked":        "🕐 Охирги текширув: %s\n",
+	"alert.seat_types_prompt":   "✅ Йўналиш: *%s → %s*\n\nСизни қизиқтирган жой турларини танланг. Бир нечта турни белгилаб, сўнг *%s* тугмасини босинг ёки *%s* ни танланг.",
+	"alert.seat_types_selected": "💺 Танланган: %s\n\nЯна қўшинг ёки %s тугмасини босинг.",
+	"alert.seat_type_unknown":   "❓ %s жой тури эмас. Қуйидаги клавиатурадан танланг.",
+	"alert.direction_prompt":    "🧭 *Қайси йўналишни кузатай?*\n\n➡️ %s → %s, %s\n⬅️ %s → %s, %s",
+	"alert.min_price_prompt":    "💰 Чиптанинг *энг кам* нархини сўмда киритинг ёки «Ўтказиб юбориш» тугмасини босинг:",
+	"alert.max_price_prompt":    "💰 Чиптанинг *энг юқори* нархини сўмда киритинг ёки «Ўтказиб юбориш» тугмасини босинг:",
+	"alert.max_below_min":       "❌ Энг юқори нарх энг кам нархдан паст бўлиши мумкин эмас. Қайта уриниб кўринг.",
+	"alert.invalid_price":       "❌ Нарх нотўғри. Рақам киритинг, масалан `300000`, ёки «Ўтказиб юбориш» тугмасини босинг.",
+	"alert.created_header":      "✅ *Хабарнома яратилди!*\n\n",
+	"alert.created_footer":      "\nМос чипталар пайдо бўлиши билан сизга ёзаман.",
+
+	// Alert notifications
+	"notification.header":       "🔔 *Чипталар пайдо бўлди!*\n\n",
+	"notification.leg_forward":  "➡️ *Бориш сафари*\n",
+	"notification.leg_return":   "⬅️ *Қайтиш сафари*\n",
+	"notification.seats_header": "\n💺 *Мос жойлар:*\n",
+	"notification.seat.one":     "*%s* %s - %d та жой: %s сўм\n",
+	"notification.seat.other":   "*%s* %s - %d та жой: %s сўм\n",
+	"notification.alert_id":     "\n🆔 Хабарнома: `%s`",
+}
@@ -0,0 +1,226 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/storage"
+)
+
+// DefaultInterval is used when no check interval is configured
+const DefaultInterval = 5 * time.Minute
+
+// checkTimeout limits how long a single alert check may take. It is well
+// under the default shutdown timeout, so that a check under way when the bot
+// stops leaves time to send its notifications.
+const checkTimeout = 15 * time.Second
+
+// Notifier delivers a notification about a matching train to the alert owner
+type Notifier func(payload train.NotificationPayload) error
+
+// Scheduler periodically evaluates ticket alerts and notifies users
+// when matching seats become available
+type Scheduler struct {
+	service  *train.Service
+	store    storage.Store
+	notify   Notifier
+	interval time.Duration
+
+	mu sync.Mutex // Serializes read-modify-write updates of stored alerts
+}
+
+// New creates a new alert scheduler backed by the given store
+func New(service *train.Service, store storage.Store, interval time.Duration, notify Notifier) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &Scheduler{
+		service:  service,
+		store:    store,
+		notify:   notify,
+		interval: interval,
+	}
+}
+
+// AddAlert registers an alert for monitoring, replacing any alert with the same ID
+func (s *Scheduler) AddAlert(alert train.TicketAlert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.SaveAlert(alert)
+}
+
+// RemoveAlert stops monitoring the alert with the given ID
+func (s *Scheduler) RemoveAlert(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.DeleteAlert(id)
+}
+
+// GetAlert returns the alert with the given ID or storage.ErrNotFound
+func (s *Scheduler) GetAlert(id string) (train.TicketAlert, error) {
+	return s.store.GetAlert(id)
+}
+
+// Alerts returns all registered alerts ordered by creation time
+func (s *Scheduler) Alerts() ([]train.TicketAlert, error) {
+	return s.store.ListAlerts()
+}
+
+// AlertsForChat returns all alerts owned by the given chat
+func (s *Scheduler) AlertsForChat(chatID int64) ([]train.TicketAlert, error) {
+	alerts, err := s.Alerts()
+	if err != nil {
+		return nil, err
+	}
+
+	var chatAlerts []train.TicketAlert
+	for _, alert := range alerts {
+		if alert.ChatID == chatID {
+			chatAlerts = append(chatAlerts, alert)
+		}
+	}
+	return chatAlerts, nil
+}
+
+// SetActive pauses or resumes the alert with the given ID. Resuming clears
+// the previous match state so the user is notified again on the next match.
+func (s *Scheduler) SetActive(id string, active bool) error {
+	return s.update(id, func(alert *train.TicketAlert) {
+		alert.IsActive = active
+		if active {
+			alert.LastMatched = false
+		}
+	})
+}
+
+// Run checks all active alerts every interval until the context is cancelled.
+// It returns once the alert being checked is done; the remaining alerts are
+// left for the next run.
+func (s *Scheduler) Run(ctx context.Context) error {
+	log.Printf("Alert scheduler started (interval: %v)", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.CheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Alert scheduler stopped")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll evaluates every active alert once
+func (s *Scheduler) CheckAll(ctx context.Context) {
+	alerts, err := s.Alerts()
+	if err != nil {
+		log.Printf("Failed to load alerts: %v", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		if ctx.Err() != nil {
+			return
+		}
+		if !alert.IsActive {
+			continue
+		}
+		s.checkAlert(ctx, alert)
+	}
+}
+
+// checkAlert evaluates a single alert and sends notifications when
+// matching seats appear for the first time since the last check
+func (s *Scheduler) checkAlert(ctx context.Context, alert train.TicketAlert) {
+	// Alerts for dates that have already passed in Tashkent can never match again
+	today := train.Today()
+	if travelDate := alert.LastTravelDate(); travelDate.Before(today) {
+		log.Printf("Alert %s expired (travel date %s), deactivating", alert.ID, travelDate.Format("2006-01-02"))
+		s.updateAndLog(alert.ID, func(a *train.TicketAlert) {
+			a.IsActive = false
+		})
+		return
+	}
+
+	// Polling yields to searches of users waiting for an answer. A check under
+	// way when the scheduler stops is finished, so its notifications are sent
+	// and its state saved.
+	checkCtx, cancel := context.WithTimeout(train.WithPriority(context.WithoutCancel(ctx), train.PriorityBackground), checkTimeout)
+	defer cancel()
+
+	trips, err := s.service.CheckAlertAvailability(checkCtx, alert)
+	checkedAt := time.Now()
+	if err != nil {
+		log.Printf("Alert %s check failed: %v", alert.ID, err)
+		s.updateAndLog(alert.ID, func(a *train.TicketAlert) {
+			a.LastChecked = checkedAt
+		})
+		return
+	}
+
+	matched := trips.Len() > 0
+	sent := 0
+
+	// Only notify on the transition from "no match" to "match" so users
+	// are not spammed with the same availability on every poll
+	if matched && !alert.LastMatched && s.notify != nil {
+		legs := []struct {
+			direction string
+			trains    []train.Train
+		}{
+			{train.DirectionForward, trips.Forward},
+			{train.DirectionReturn, trips.Return},
+		}
+		for _, leg := range legs {
+			for _, t := range leg.trains {
+				payload := s.service.BuildNotification(alert, t, leg.direction)
+				if err := s.notify(payload); err != nil {
+					log.Printf("Alert %s notification failed: %v", alert.ID, err)
+					continue
+				}
+				sent++
+			}
+		}
+		log.Printf("Alert %s triggered: %d matching train(s), %d notification(s) sent", alert.ID, trips.Len(), sent)
+	}
+
+	s.updateAndLog(alert.ID, func(a *train.TicketAlert) {
+		a.LastChecked = checkedAt
+		a.NotifyCount += sent
+		// Keep retrying delivery on the next check if every notification failed
+		a.LastMatched = matched && (alert.LastMatched || sent > 0)
+	})
+}
+
+// update applies fn to the stored alert and saves the result
+func (s *Scheduler) update(id string, fn func(alert *train.TicketAlert)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, err := s.store.GetAlert(id)
+	if err != nil {
+		return err
+	}
+
+	fn(&alert)
+	return s.store.SaveAlert(alert)
+}
+
+// updateAndLog updates an alert during a check. The alert may have been
+// deleted by its owner in the meantime, which is not an error.
+func (s *Scheduler) updateAndLog(id string, fn func(alert *train.TicketAlert)) {
+	if err := s.update(id, fn); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to update alert %s: %v", id, err)
+	}
+}
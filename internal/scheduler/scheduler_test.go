@@ -0,0 +1,207 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/scheduler"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/storage"
+)
+
+// trainOn returns an Afrosiyob from Toshkent to Samarqand running on date
+func trainOn(date time.Time, number string) train.Train {
+	day := date.Format("02.01.2006")
+	return train.Train{
+		Type:          "СКРСТ",
+		Number:        number,
+		DepartureDate: day + " 06:03",
+		ArrivalDate:   day + " 08:21",
+		TimeOnWay:     "02:18",
+		Brand:         "Afrosiyob",
+		SubRoute: train.SubRoute{
+			DepStationName: "TOSHKENT",
+			DepStationCode: "2900000",
+			ArvStationName: "SAMARQAND",
+			ArvStationCode: "2900700",
+		},
+		Cars: []train.Car{{
+			Type:      "O'rindiqli",
+			FreeSeats: 42,
+			Tariffs:   []train.Tariff{{ClassServiceType: "2Е", FreeSeats: 42, Tariff: 270000}},
+		}},
+	}
+}
+
+// fixture is a scheduler watching one alert against the railway fake
+type fixture struct {
+	t         *testing.T
+	railway   *railwaytest.Server
+	store     *storage.MemoryStore
+	scheduler *scheduler.Scheduler
+	date      time.Time // Travel date of the alert
+	sent      []train.NotificationPayload
+	notifyErr error // Returned by the notifier while set
+}
+
+// newFixture creates a scheduler with an active alert from Toshkent to
+// Samarqand on date
+func newFixture(t *testing.T, date time.Time) *fixture {
+	t.Helper()
+
+	f := &fixture{t: t, railway: railwaytest.NewServer(), store: storage.NewMemoryStore(), date: date}
+	t.Cleanup(f.railway.Close)
+
+	service := f.railway.NewService()
+	// Every check must reach the fake
+	service.SetSearchCacheTTL(0)
+
+	f.scheduler = scheduler.New(service, f.store, time.Hour, func(payload train.NotificationPayload) error {
+		if f.notifyErr != nil {
+			return f.notifyErr
+		}
+		f.sent = append(f.sent, payload)
+		return nil
+	})
+
+	err := f.scheduler.AddAlert(train.TicketAlert{
+		ID:        "alert-1",
+		UserID:    7,
+		ChatID:    7,
+		From:      "Toshkent",
+		To:        "Samarqand",
+		Date:      date,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		Language:  train.LanguageUzbek,
+	})
+	if err != nil {
+		t.Fatalf("AddAlert: %v", err)
+	}
+	return f
+}
+
+// setSeats makes the fake find a train on the alert date, or none
+func (f *fixture) setSeats(available bool) {
+	date := f.date.Format("2006-01-02")
+	if available {
+		f.railway.SetTrains("2900000", "2900700", date, trainOn(f.date, "778Ф"))
+	} else {
+		f.railway.SetTrains("2900000", "2900700", date)
+	}
+}
+
+// check runs one round of checks and returns the stored alert
+func (f *fixture) check() train.TicketAlert {
+	f.t.Helper()
+	f.scheduler.CheckAll(context.Background())
+	alert, err := f.store.GetAlert("alert-1")
+	if err != nil {
+		f.t.Fatalf("GetAlert: %v", err)
+	}
+	return alert
+}
+
+func TestNotifiesWhenSeatsAppear(t *testing.T) {
+	f := newFixture(t, train.Today().AddDate(0, 0, 1))
+
+	steps := []struct {
+		name            string
+		seats           bool
+		wantSent        int // Notifications sent so far
+		wantLastMatched bool
+	}{
+		{"no seats", false, 0, false},
+		{"seats appear", true, 1, true},
+		{"seats still there", true, 1, true},
+		{"sold out", false, 1, false},
+		{"seats appear again", true, 2, true},
+	}
+
+	for _, step := range steps {
+		f.setSeats(step.seats)
+		before := time.Now()
+		alert := f.check()
+
+		if len(f.sent) != step.wantSent {
+			t.Errorf("%s: %d notifications sent, want %d", step.name, len(f.sent), step.wantSent)
+		}
+		if alert.NotifyCount != step.wantSent {
+			t.Errorf("%s: NotifyCount = %d, want %d", step.name, alert.NotifyCount, step.wantSent)
+		}
+		if alert.LastMatched != step.wantLastMatched {
+			t.Errorf("%s: LastMatched = %v, want %v", step.name, alert.LastMatched, step.wantLastMatched)
+		}
+		if alert.LastChecked.Before(before) {
+			t.Errorf("%s: LastChecked = %v, want the time of the check", step.name, alert.LastChecked)
+		}
+	}
+
+	if payload := f.sent[0]; payload.Train.Number != "778Ф" || payload.Direction != train.DirectionForward || payload.Alert.ChatID != 7 {
+		t.Errorf("notification = %+v, want train 778Ф on the way there for chat 7", payload)
+	}
+}
+
+func TestRetriesFailedNotification(t *testing.T) {
+	f := newFixture(t, train.Today().AddDate(0, 0, 1))
+	f.setSeats(true)
+
+	f.notifyErr = errors.New("telegram is down")
+	alert := f.check()
+	if alert.LastMatched || alert.NotifyCount != 0 {
+		t.Fatalf("after a failed notification LastMatched = %v, NotifyCount = %d, want false and 0", alert.LastMatched, alert.NotifyCount)
+	}
+
+	f.notifyErr = nil
+	alert = f.check()
+	if len(f.sent) != 1 || !alert.LastMatched || alert.NotifyCount != 1 {
+		t.Errorf("retry sent %d notifications, LastMatched = %v, NotifyCount = %d, want 1, true and 1",
+			len(f.sent), alert.LastMatched, alert.NotifyCount)
+	}
+}
+
+func TestResumingAlertNotifiesAgain(t *testing.T) {
+	f := newFixture(t, train.Today().AddDate(0, 0, 1))
+	f.setSeats(true)
+	f.check()
+
+	if err := f.scheduler.SetActive("alert-1", false); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if alert := f.check(); alert.IsActive || !alert.LastMatched {
+		t.Fatalf("paused alert = %+v, want inactive and still matched", alert)
+	}
+	if err := f.scheduler.SetActive("alert-1", true); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	alert, err := f.store.GetAlert("alert-1")
+	if err != nil {
+		t.Fatalf("GetAlert: %v", err)
+	}
+	if !alert.IsActive || alert.LastMatched {
+		t.Fatalf("resumed alert = %+v, want active with the match cleared", alert)
+	}
+
+	f.check()
+	if len(f.sent) != 2 {
+		t.Errorf("%d notifications sent, want 2 (one before the pause, one after)", len(f.sent))
+	}
+}
+
+func TestDeactivatesPastAlerts(t *testing.T) {
+	f := newFixture(t, train.Today().AddDate(0, 0, -1))
+	f.setSeats(true)
+
+	if alert := f.check(); alert.IsActive {
+		t.Error("alert for yesterday is still active")
+	}
+	if got := f.railway.SearchRequests(); got != 0 {
+		t.Errorf("got %d searches for a past date, want 0", got)
+	}
+	if len(f.sent) != 0 {
+		t.Errorf("%d notifications sent for a past date, want 0", len(f.sent))
+	}
+}
@@ -0,0 +1,142 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Defaults of the client circuit breaker
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerPolicy decides when the client stops calling a failing railway API
+type BreakerPolicy struct {
+	Threshold int           // Consecutive failures that open the circuit
+	Cooldown  time.Duration // How long the circuit stays open before a probe
+}
+
+// DefaultBreakerPolicy returns the policy used by new clients
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		Threshold: DefaultBreakerThreshold,
+		Cooldown:  DefaultBreakerCooldown,
+	}
+}
+
+// withDefaults fills unset fields from DefaultBreakerPolicy
+func (p BreakerPolicy) withDefaults() BreakerPolicy {
+	def := DefaultBreakerPolicy()
+	if p.Threshold <= 0 {
+		p.Threshold = def.Threshold
+	}
+	if p.Cooldown <= 0 {
+		p.Cooldown = def.Cooldown
+	}
+	return p
+}
+
+// breakerState is the state of a circuit breaker
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Requests pass
+	breakerOpen                         // Requests fail fast until the cooldown is over
+	breakerHalfOpen                     // A single probe request decides whether to close
+)
+
+// breakerOutcome is how a request admitted by the breaker ended
+type breakerOutcome int
+
+const (
+	outcomeSuccess breakerOutcome = iota
+	outcomeFailure
+	outcomeIgnored // Cancelled by the caller, says nothing about the API
+)
+
+// circuitBreaker stops requests to a failing API. After Threshold consecutive
+// failures it opens and rejects requests with an UnavailableError. Once
+// Cooldown has passed a single probe is let through: success closes the
+// circuit, failure opens it again. It is safe for concurrent use.
+type circuitBreaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int       // Consecutive failures while closed
+	openedAt time.Time // When the circuit last opened
+	lastErr  error     // Failure that opened the circuit
+}
+
+// newCircuitBreaker creates a closed circuit breaker. Unset policy fields keep
+// their defaults.
+func newCircuitBreaker(policy BreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{policy: policy.withDefaults()}
+}
+
+// allow reports whether a request may be made now, returning an
+// UnavailableError while the circuit is open. A request that was allowed
+// must be reported with record.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		retryAt := b.openedAt.Add(b.policy.Cooldown)
+		if time.Now().Before(retryAt) {
+			return &UnavailableError{RetryAt: retryAt, Err: b.lastErr}
+		}
+		log.Printf("Railway API circuit half-open, probing")
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// A probe is already in flight
+		return &UnavailableError{RetryAt: time.Now().Add(b.policy.Cooldown), Err: b.lastErr}
+	default:
+		return nil
+	}
+}
+
+// failureOutcome classifies a request that failed without a response
+func failureOutcome(err error) breakerOutcome {
+	if errors.Is(err, context.Canceled) {
+		return outcomeIgnored
+	}
+	return outcomeFailure
+}
+
+// record reports how an allowed request ended
+func (b *circuitBreaker) record(outcome breakerOutcome, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch outcome {
+	case outcomeSuccess:
+		if b.state != breakerClosed {
+			log.Printf("Railway API circuit closed, service is back")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		b.lastErr = nil
+	case outcomeFailure:
+		b.failures++
+		b.lastErr = err
+		if b.state == breakerHalfOpen || b.failures >= b.policy.Threshold {
+			if b.state == breakerClosed {
+				log.Printf("Railway API circuit opened after %d consecutive failures: %v", b.failures, err)
+			}
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+	case outcomeIgnored:
+		// Let the next request probe instead
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+		}
+	}
+}
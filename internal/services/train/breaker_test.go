@@ -0,0 +1,138 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBreakerPolicyDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BreakerPolicy
+		want   BreakerPolicy
+	}{
+		{"unset", BreakerPolicy{}, DefaultBreakerPolicy()},
+		{"negative", BreakerPolicy{Threshold: -1, Cooldown: -time.Second}, DefaultBreakerPolicy()},
+		{"set", BreakerPolicy{Threshold: 2, Cooldown: time.Minute}, BreakerPolicy{Threshold: 2, Cooldown: time.Minute}},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.withDefaults(); got != tt.want {
+			t.Errorf("%s: withDefaults() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFailureOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want breakerOutcome
+	}{
+		{context.Canceled, outcomeIgnored},
+		{fmt.Errorf("search: %w", context.Canceled), outcomeIgnored},
+		{context.DeadlineExceeded, outcomeFailure},
+		{errors.New("connection refused"), outcomeFailure},
+	}
+	for _, tt := range tests {
+		if got := failureOutcome(tt.err); got != tt.want {
+			t.Errorf("failureOutcome(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	errDown := errors.New("railway.uz is down")
+
+	// Steps of the state machine: record an outcome, let the cooldown pass or
+	// ask for admission
+	type step struct {
+		record   *breakerOutcome
+		cooldown bool // Let the cooldown pass
+		allowed  bool // Only checked for admission steps
+	}
+	outcome := func(o breakerOutcome) step { return step{record: &o} }
+	allow := step{allowed: true}
+	reject := step{}
+	wait := step{cooldown: true}
+
+	tests := []struct {
+		name      string
+		steps     []step
+		wantState breakerState
+	}{
+		{"closed", []step{allow, outcome(outcomeSuccess), allow}, breakerClosed},
+		{"failures below threshold", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), allow,
+		}, breakerClosed},
+		{"success resets the count", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeSuccess),
+			outcome(outcomeFailure), outcome(outcomeFailure), allow,
+		}, breakerClosed},
+		{"opens at threshold", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeFailure), reject,
+		}, breakerOpen},
+		{"cancelled requests do not count", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeIgnored), allow,
+		}, breakerClosed},
+		{"single probe after cooldown", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeFailure), wait, allow, reject,
+		}, breakerHalfOpen},
+		{"successful probe closes", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeFailure), wait, allow,
+			outcome(outcomeSuccess), allow,
+		}, breakerClosed},
+		{"failed probe opens again", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeFailure), wait, allow,
+			outcome(outcomeFailure), reject,
+		}, breakerOpen},
+		{"cancelled probe lets the next one through", []step{
+			outcome(outcomeFailure), outcome(outcomeFailure), outcome(outcomeFailure), wait, allow,
+			outcome(outcomeIgnored), allow,
+		}, breakerHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker(BreakerPolicy{Threshold: 3, Cooldown: time.Minute})
+			for i, s := range tt.steps {
+				switch {
+				case s.record != nil:
+					b.record(*s.record, errDown)
+				case s.cooldown:
+					b.mu.Lock()
+					b.openedAt = b.openedAt.Add(-b.policy.Cooldown)
+					b.mu.Unlock()
+				default:
+					err := b.allow()
+					if s.allowed && err != nil {
+						t.Fatalf("step %d: allow() = %v, want allowed", i+1, err)
+					}
+					if !s.allowed {
+						var unavailable *UnavailableError
+						if !errors.As(err, &unavailable) || !errors.Is(err, errDown) {
+							t.Fatalf("step %d: allow() = %v, want an UnavailableError wrapping the failure", i+1, err)
+						}
+					}
+				}
+			}
+			if b.state != tt.wantState {
+				t.Errorf("state = %v, want %v", b.state, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestOpenBreakerReportsRetryTime(t *testing.T) {
+	b := newCircuitBreaker(BreakerPolicy{Threshold: 1, Cooldown: time.Minute})
+	b.record(outcomeFailure, errors.New("timeout"))
+
+	var unavailable *UnavailableError
+	if err := b.allow(); !errors.As(err, &unavailable) {
+		t.Fatalf("allow() = %v, want an UnavailableError", err)
+	}
+	if wait := time.Until(unavailable.RetryAt); wait <= 0 || wait > time.Minute {
+		t.Errorf("RetryAt is %v away, want within the cooldown", wait)
+	}
+}
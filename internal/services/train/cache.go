@@ -0,0 +1,122 @@
+package train
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSearchCacheTTL is how long a search response is reused by default
+const DefaultSearchCacheTTL = time.Minute
+
+// DefaultStaleResultsMaxAge is how long a search response is kept by default
+// to be shown while the railway API is down
+const DefaultStaleResultsMaxAge = time.Hour
+
+// searchCallTimeout bounds a shared search request, which does not stop when
+// the caller that started it gives up
+const searchCallTimeout = 60 * time.Second
+
+// searchKey identifies search requests with the same upstream response
+type searchKey struct {
+	From       string // Departure station code
+	To         string // Arrival station code
+	Date       string
+	ReturnDate string // Empty for one-way searches
+	Language   string
+}
+
+// searchCache keeps recent search responses for a short time and lets
+// identical concurrent searches share a single upstream request. Older
+// responses are kept up to maxAge as a fallback while the API is down.
+// Responses are shared between callers and must not be modified. It is safe
+// for concurrent use.
+type searchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // Zero disables caching; requests are still shared
+	maxAge  time.Duration // How long responses are kept as a fallback
+	entries map[searchKey]searchEntry
+	flights *flightGroup[searchKey, *SearchTrainsResponse]
+}
+
+// searchEntry is a cached response
+type searchEntry struct {
+	response  *SearchTrainsResponse
+	fetchedAt time.Time
+}
+
+// newSearchCache creates a cache reusing responses for ttl and keeping them
+// as a fallback for maxAge
+func newSearchCache(ttl, maxAge time.Duration) *searchCache {
+	return &searchCache{
+		ttl:     max(ttl, 0),
+		maxAge:  max(maxAge, 0),
+		entries: make(map[searchKey]searchEntry),
+		flights: newFlightGroup[searchKey, *SearchTrainsResponse](searchCallTimeout),
+	}
+}
+
+// setTTL changes how long responses are reused
+func (c *searchCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = max(ttl, 0)
+}
+
+// setMaxAge changes how long responses are kept as a fallback
+func (c *searchCache) setMaxAge(maxAge time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxAge = max(maxAge, 0)
+}
+
+// last returns the latest response for key kept as a fallback and when it
+// was fetched
+func (c *searchCache) last(key searchKey) (*SearchTrainsResponse, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) >= max(c.ttl, c.maxAge) {
+		return nil, time.Time{}, false
+	}
+	return entry.response, entry.fetchedAt, true
+}
+
+// get returns the cached response for key or makes the search with fetch,
+// joining an identical search already in flight. A shared search starts with
+// the priority of the caller that started it and becomes interactive when an
+// interactive caller joins. Failed searches are not cached.
+func (c *searchCache) get(ctx context.Context, key searchKey, fetch func(ctx context.Context) (*SearchTrainsResponse, error)) (*SearchTrainsResponse, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && time.Since(entry.fetchedAt) < c.ttl {
+		c.mu.Unlock()
+		return entry.response, nil
+	}
+	c.mu.Unlock()
+
+	return c.flights.do(ctx, key, func(ctx context.Context) (*SearchTrainsResponse, error) {
+		response, err := fetch(ctx)
+		c.finish(key, response, err)
+		return response, err
+	})
+}
+
+// finish stores the result of a shared search and removes entries too old
+// to be used
+func (c *searchCache) finish(key searchKey, response *SearchTrainsResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	keep := max(c.ttl, c.maxAge)
+	for k, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= keep {
+			delete(c.entries, k)
+		}
+	}
+
+	if keep > 0 && err == nil && response != nil {
+		c.entries[key] = searchEntry{response: response, fetchedAt: now}
+	}
+}
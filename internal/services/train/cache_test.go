@@ -0,0 +1,88 @@
+package train
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRaisedPriority(t *testing.T) {
+	ctx, raise := withRaisablePriority(WithPriority(context.Background(), PriorityBackground))
+	if got := PriorityFromContext(ctx); got != PriorityBackground {
+		t.Fatalf("PriorityFromContext() before raise = %v, want background", got)
+	}
+	raise()
+	raise() // Raising twice is harmless
+	if got := PriorityFromContext(ctx); got != PriorityInteractive {
+		t.Errorf("PriorityFromContext() after raise = %v, want interactive", got)
+	}
+}
+
+func TestSharedSearchTakesInteractivePriority(t *testing.T) {
+	l := NewLimiter(Limits{Rate: 1000, Burst: 10, MaxInFlight: 1})
+	release, ok := tryAcquire(l, PriorityInteractive)
+	if !ok {
+		t.Fatal("first request was not admitted")
+	}
+
+	admitted := make(chan string, 2)
+	acquire := func(ctx context.Context, name string) {
+		done, err := l.Acquire(ctx, PriorityFromContext(ctx))
+		if err != nil {
+			t.Errorf("%s: Acquire: %v", name, err)
+			return
+		}
+		admitted <- name
+		done()
+	}
+
+	// An unrelated background request waits ahead of the shared search
+	background := WithPriority(context.Background(), PriorityBackground)
+	go acquire(background, "other")
+	waitQueued(t, l, PriorityBackground, 1)
+
+	cache := newSearchCache(time.Minute, time.Hour)
+	key := searchKey{From: "2900000", To: "2900700", Date: "2025-09-02"}
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (*SearchTrainsResponse, error) {
+		fetches.Add(1)
+		acquire(ctx, "shared")
+		return &SearchTrainsResponse{}, nil
+	}
+
+	responses := make(chan *SearchTrainsResponse, 2)
+	get := func(ctx context.Context) {
+		response, err := cache.get(ctx, key, fetch)
+		if err != nil {
+			t.Errorf("get: %v", err)
+		}
+		responses <- response
+	}
+
+	go get(background)
+	waitQueued(t, l, PriorityBackground, 2)
+	go get(context.Background())
+	waitQueued(t, l, PriorityInteractive, 1)
+
+	release()
+
+	for i, want := range []string{"shared", "other"} {
+		select {
+		case got := <-admitted:
+			if got != want {
+				t.Errorf("admission %d = %s, want %s", i+1, got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("admission %d never happened", i+1)
+		}
+	}
+
+	first, second := <-responses, <-responses
+	if first == nil || first != second {
+		t.Error("callers did not share the response")
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetched %d times, want 1", got)
+	}
+}
@@ -0,0 +1,322 @@
+package train
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/fsutil"
+)
+
+// redacted replaces cookie values and tokens in cassettes
+const redacted = "REDACTED"
+
+// minSecretLength is the length below which a cookie value is too short to be
+// a credential, and redacting it from bodies would mangle them
+const minSecretLength = 6
+
+// Cassette is a recording of railway API requests and the responses they got.
+// Recorded responses can be replayed to reproduce what the API answered
+// without touching the network.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request. Credentials are never recorded.
+type RecordedRequest struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`               // URL path and query, without the host
+	Language string `json:"language,omitempty"` // Accept-Language header
+	Body     string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as received, with cookie values redacted
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Recorder is an http.RoundTripper that makes requests with another
+// RoundTripper and appends every response to a cassette file. Cookie values
+// are redacted, in headers and wherever they appear in a body. It is safe for
+// concurrent use.
+//
+// Recorder is a development tool: every request rewrites the whole cassette
+// under a lock, so that nothing recorded is lost when the bot is stopped, and
+// requests are serialized while it is written. Do not use it in production.
+type Recorder struct {
+	next http.RoundTripper
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that appends to the cassette at path, which
+// is created if missing. A nil next uses http.DefaultTransport.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	r := &Recorder{next: next, path: path}
+	cassette, err := LoadCassette(path)
+	switch {
+	case err == nil:
+		r.cassette = *cassette
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	return r, nil
+}
+
+// RoundTrip makes the request and records the response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Tokens the client sent may come back in a body too
+	secrets := cookieValues(req.Cookies())
+	secrets = append(secrets, req.Header.Get("X-XSRF-TOKEN"))
+	secrets = append(secrets, cookieValues(resp.Cookies())...)
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: redactHeader(resp.Header),
+			Body:   redact(string(body), secrets),
+		},
+	}
+	interaction.Request.Body = redact(interaction.Request.Body, secrets)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// instead of the network. A request gets the first response recorded for the
+// same method, path, language and body that was not replayed yet; once all
+// were replayed, the last one again. Without such a recording it gets the
+// first response for a request that differs only in its dates, so that a
+// recorded search answers searches on the same route for other dates too. It
+// is safe for concurrent use.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// NewReplayer returns a Replayer answering from the cassette
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		replayed: make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip answers the request with a recorded response
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction, ok := r.match(recorded)
+	if !ok {
+		return nil, fmt.Errorf("no recorded response for %s %s", recorded.Method, recorded.Path)
+	}
+
+	response := interaction.Response
+	header := response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// match finds the recorded interaction that answers a request
+func (r *Replayer) match(req RecordedRequest) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request != req {
+			continue
+		}
+		if !r.replayed[i] {
+			r.replayed[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.cassette.Interactions[last], true
+	}
+
+	undated := withoutDates(req)
+	for _, interaction := range r.cassette.Interactions {
+		if withoutDates(interaction.Request) == undated {
+			return interaction, true
+		}
+	}
+	return Interaction{}, false
+}
+
+// withoutDates returns the request with the dates left out of a JSON body
+func withoutDates(req RecordedRequest) RecordedRequest {
+	var body interface{}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		return req
+	}
+	removeDates(body)
+	undated, err := json.Marshal(body)
+	if err != nil {
+		return req
+	}
+	req.Body = string(undated)
+	return req
+}
+
+// removeDates deletes every date field of a decoded JSON value
+func removeDates(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		delete(value, "date")
+		for _, field := range value {
+			removeDates(field)
+		}
+	case []interface{}:
+		for _, item := range value {
+			removeDates(item)
+		}
+	}
+}
+
+// recordRequest returns what identifies a request in a cassette. The body is
+// read and put back for the request to be sent.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{
+		Method:   req.Method,
+		Path:     req.URL.RequestURI(),
+		Language: req.Header.Get("Accept-Language"),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return RecordedRequest{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		recorded.Body = string(body)
+	}
+	return recorded, nil
+}
+
+// redactHeader copies response headers with cookie values redacted. Cookies
+// lose their expiry so that replayed sessions never need a refresh.
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	redactedHeader.Del("Set-Cookie")
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		cookie.Value = redacted
+		cookie.Expires = time.Time{}
+		cookie.RawExpires = ""
+		if cookie.MaxAge > 0 {
+			cookie.MaxAge = 0
+		}
+		redactedHeader.Add("Set-Cookie", cookie.String())
+	}
+	return redactedHeader
+}
+
+// redact replaces every secret in text
+func redact(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			text = strings.ReplaceAll(text, secret, redacted)
+		}
+	}
+	return text
+}
+
+// cookieValues returns the values of cookies
+func cookieValues(cookies []*http.Cookie) []string {
+	values := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		values = append(values, cookie.Value)
+	}
+	return values
+}
@@ -0,0 +1,125 @@
+package train_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+)
+
+func TestRecorderRedactsCredentials(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetTrains("2900000", "2900700", "2025-09-02", sampleTrain("778Ф", "06:03", 77))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := train.NewRecorder(path, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	client := fake.NewClient()
+	client.SetTransport(recorder)
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("SearchTrains: %v", err)
+	}
+	token := fake.Token()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cassette not written: %v", err)
+	}
+	if strings.Contains(string(data), token) {
+		t.Errorf("cassette contains the XSRF token %q:\n%s", token, data)
+	}
+
+	cassette, err := train.LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	if got := len(cassette.Interactions); got != 2 {
+		t.Fatalf("got %d interactions, want the CSRF token request and the search", got)
+	}
+	if search := cassette.Interactions[1]; search.Request.Path != "/api/v3"+train.TrainsListEndpoint || !strings.Contains(search.Response.Body, "778Ф") {
+		t.Errorf("second interaction is not the search: %+v", search)
+	}
+}
+
+func TestReplayerAnswersWithoutNetwork(t *testing.T) {
+	fake := railwaytest.NewServer()
+	fake.SetTrains("2900000", "2900700", "2025-09-02", sampleTrain("778Ф", "06:03", 77))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := train.NewRecorder(path, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	client := fake.NewClient()
+	client.SetTransport(recorder)
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("recorded search: %v", err)
+	}
+	fake.Close()
+
+	cassette, err := train.LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	client = fake.NewClient()
+	client.SetTransport(train.NewReplayer(cassette))
+
+	// Another date is answered with the recorded search
+	req := searchRequest()
+	req.Directions.Forward.Date = "2025-09-03"
+	for i := 0; i < 2; i++ {
+		response, err := client.SearchTrains(context.Background(), req)
+		if err != nil {
+			t.Fatalf("replayed search %d: %v", i+1, err)
+		}
+		if trains := response.Data.Directions.Forward.Trains; len(trains) != 1 || trains[0].Number != "778Ф" {
+			t.Errorf("replayed search %d got trains %+v, want 778Ф", i+1, trains)
+		}
+	}
+
+	// A search on another route is not answered with the recorded one
+	other := searchRequest()
+	other.Directions.Forward.ArvStationCode = "2900800"
+	if _, err := client.SearchTrains(context.Background(), other); err == nil {
+		t.Errorf("search to Buxoro was answered, want an error")
+	}
+
+	// A search in another language is not answered in the recorded one
+	if _, err := client.SearchTrains(train.WithLanguage(context.Background(), train.LanguageRussian), req); err == nil {
+		t.Errorf("search in Russian was answered, want an error")
+	}
+
+	if _, err := client.ListStations(context.Background()); err == nil {
+		t.Errorf("stations were never recorded, want an error")
+	}
+}
+
+func TestReplayRecordedSearch(t *testing.T) {
+	cassette, err := train.LoadCassette("testdata/search_toshkent_samarqand.json")
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+
+	service := train.NewService()
+	service.SetTransport(train.NewReplayer(cassette))
+
+	trains, err := service.FindAvailableTrains(context.Background(), train.TrainSearchParams{
+		From: "Toshkent",
+		To:   "Samarqand",
+		Date: travelDate,
+	})
+	if err != nil {
+		t.Fatalf("FindAvailableTrains: %v", err)
+	}
+	if len(trains) != 1 || trains[0].Number != "778Ф" || trains[0].GetMinPrice() != 270000 {
+		t.Errorf("got trains %+v, want 778Ф from 270 000", trains)
+	}
+}
@@ -0,0 +1,355 @@
+package train
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/fsutil"
+)
+
+// DefaultStationsTTL is how long a fetched station handbook stays fresh
+const DefaultStationsTTL = 24 * time.Hour
+
+// stationsRetryInterval is the wait before retrying a failed handbook refresh
+const stationsRetryInterval = 10 * time.Minute
+
+// StationCatalog is the set of known stations with lookup by code and by any
+// name or alias. It is safe for concurrent use.
+type StationCatalog struct {
+	mu        sync.RWMutex
+	stations  []StationInfo
+	byCode    map[string]int
+	byName    map[string]int // Lowercased names and aliases
+	keys      []stationKey   // Normalized names and aliases for Resolve
+	updatedAt time.Time      // When the handbook was fetched, zero for the built-in stations
+}
+
+// stationCache is the on-disk format of the station catalog
+type stationCache struct {
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Stations  []StationInfo `json:"stations"`
+}
+
+// NewStationCatalog creates a catalog holding the given stations
+func NewStationCatalog(stations []StationInfo) *StationCatalog {
+	c := &StationCatalog{}
+	c.replace(stations, time.Time{})
+	return c
+}
+
+// replace swaps the catalog contents and rebuilds the indexes
+func (c *StationCatalog) replace(stations []StationInfo, updatedAt time.Time) {
+	byCode := make(map[string]int, len(stations))
+	byName := make(map[string]int, len(stations)*4)
+	var keys []stationKey
+
+	for i, station := range stations {
+		if _, exists := byCode[station.Code]; !exists {
+			byCode[station.Code] = i
+		}
+
+		for _, name := range station.Names() {
+			if normalized := NormalizeName(name); normalized != "" {
+				keys = append(keys, stationKey{key: normalized, index: i})
+			}
+
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" {
+				continue
+			}
+			// Earlier stations win, so curated names are never shadowed by the handbook
+			if _, exists := byName[key]; !exists {
+				byName[key] = i
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stations = stations
+	c.byCode = byCode
+	c.byName = byName
+	c.keys = keys
+	c.updatedAt = updatedAt
+}
+
+// All returns a copy of every station in the catalog
+func (c *StationCatalog) All() []StationInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stations := make([]StationInfo, len(c.stations))
+	copy(stations, c.stations)
+	return stations
+}
+
+// ByCode returns the station with the given code, or nil
+func (c *StationCatalog) ByCode(code string) *StationInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	i, ok := c.byCode[strings.TrimSpace(code)]
+	if !ok {
+		return nil
+	}
+	station := c.stations[i]
+	return &station
+}
+
+// ByName returns the station with the given name or alias in any language
+// (case-insensitive), or nil
+func (c *StationCatalog) ByName(name string) *StationInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	i, ok := c.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil
+	}
+	station := c.stations[i]
+	return &station
+}
+
+// KeyboardStations returns the stations offered on station keyboards, in display order
+func (c *StationCatalog) KeyboardStations() []StationInfo {
+	var stations []StationInfo
+	for _, station := range c.All() {
+		if station.KeyboardRank > 0 {
+			stations = append(stations, station)
+		}
+	}
+
+	sort.SliceStable(stations, func(i, j int) bool {
+		return stations[i].KeyboardRank < stations[j].KeyboardRank
+	})
+	return stations
+}
+
+// Hubs returns the junction stations used for connecting itineraries
+func (c *StationCatalog) Hubs() []StationInfo {
+	var hubs []StationInfo
+	for _, station := range c.All() {
+		if station.IsHub {
+			hubs = append(hubs, station)
+		}
+	}
+	return hubs
+}
+
+// StationDistance is a station with its distance from a point
+type StationDistance struct {
+	Station  StationInfo
+	Distance float64 // Great-circle distance in kilometers
+}
+
+// Nearest returns up to limit active stations with known coordinates,
+// closest to the point first
+func (c *StationCatalog) Nearest(point LatLng, limit int) []StationDistance {
+	var nearest []StationDistance
+	for _, station := range c.All() {
+		if !station.IsActive || station.Coordinates == nil {
+			continue
+		}
+		nearest = append(nearest, StationDistance{
+			Station:  station,
+			Distance: point.DistanceTo(*station.Coordinates),
+		})
+	}
+
+	sort.Slice(nearest, func(i, j int) bool {
+		return nearest[i].Distance < nearest[j].Distance
+	})
+	if limit > 0 && len(nearest) > limit {
+		nearest = nearest[:limit]
+	}
+	return nearest
+}
+
+// UpdatedAt returns when the handbook in the catalog was fetched, or the zero
+// time if the catalog holds only the built-in stations
+func (c *StationCatalog) UpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.updatedAt
+}
+
+// LoadFile replaces the catalog with a cache written by SaveFile
+func (c *StationCatalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read station cache: %w", err)
+	}
+
+	var cache stationCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return fmt.Errorf("failed to decode station cache %s: %w", path, err)
+	}
+	if len(cache.Stations) == 0 {
+		return fmt.Errorf("station cache %s is empty", path)
+	}
+
+	c.replace(withBuiltin(cache.Stations), cache.UpdatedAt)
+	return nil
+}
+
+// withBuiltin replaces cached copies of the built-in stations with the
+// current ones, so curated data added since the cache was written applies
+func withBuiltin(cached []StationInfo) []StationInfo {
+	stations := make([]StationInfo, len(builtinStations), len(builtinStations)+len(cached))
+	copy(stations, builtinStations)
+
+	builtin := make(map[string]bool, len(builtinStations))
+	for _, station := range builtinStations {
+		builtin[station.Code] = true
+	}
+
+	for _, station := range cached {
+		if !builtin[station.Code] {
+			stations = append(stations, station)
+		}
+	}
+	return stations
+}
+
+// SaveFile atomically writes the catalog to disk
+func (c *StationCatalog) SaveFile(path string) error {
+	data, err := json.MarshalIndent(stationCache{
+		UpdatedAt: c.UpdatedAt(),
+		Stations:  c.All(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode station cache: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write station cache: %w", err)
+	}
+
+	return nil
+}
+
+// Stations returns the station catalog used by the service
+func (s *Service) Stations() *StationCatalog {
+	return s.catalog
+}
+
+// RefreshStations fetches the station handbook and merges it with the
+// built-in stations. The Uzbek handbook is required; Russian and English
+// names are filled in when available.
+func (s *Service) RefreshStations(ctx context.Context) error {
+	handbooks := make(map[string][]Station)
+	for _, language := range []string{LanguageUzbek, LanguageRussian, LanguageEnglish} {
+		response, err := s.client.ListStations(WithLanguage(ctx, language))
+		if err == nil && (response.Data == nil || len(response.Data.Stations) == 0) {
+			err = fmt.Errorf("no stations received from API")
+		}
+		if err != nil {
+			if language == LanguageUzbek {
+				return fmt.Errorf("failed to fetch station handbook: %w", err)
+			}
+			log.Printf("Failed to fetch %s station names: %v", language, err)
+			continue
+		}
+		handbooks[language] = response.Data.Stations
+	}
+
+	stations := mergeHandbook(builtinStations, handbooks)
+	s.catalog.replace(stations, time.Now())
+
+	log.Printf("Station catalog refreshed: %d stations", len(stations))
+	return nil
+}
+
+// SyncStations keeps the station catalog fresh until ctx is done. It loads the
+// disk cache at cachePath, refreshes from the API whenever the catalog is older
+// than ttl and writes every refresh back to the cache.
+func (s *Service) SyncStations(ctx context.Context, cachePath string, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultStationsTTL
+	}
+	ctx = WithPriority(ctx, PriorityBackground)
+
+	if err := s.catalog.LoadFile(cachePath); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Station cache not loaded: %v", err)
+		}
+	} else {
+		log.Printf("Station catalog loaded from %s", cachePath)
+	}
+
+	for {
+		wait := ttl - time.Since(s.catalog.UpdatedAt())
+		if wait <= 0 {
+			wait = ttl
+			if err := s.RefreshStations(ctx); err != nil {
+				log.Printf("Station catalog refresh failed: %v", err)
+				wait = min(ttl, stationsRetryInterval)
+			} else if err := s.catalog.SaveFile(cachePath); err != nil {
+				log.Printf("Failed to save station cache: %v", err)
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// mergeHandbook adds the handbook stations to the built-in ones. Built-in
+// stations keep their curated names and flags; handbook names only fill gaps.
+func mergeHandbook(builtin []StationInfo, handbooks map[string][]Station) []StationInfo {
+	stations := make([]StationInfo, len(builtin))
+	copy(stations, builtin)
+
+	index := make(map[string]int, len(stations))
+	for i, station := range stations {
+		index[station.Code] = i
+	}
+
+	for _, handbook := range handbooks[LanguageUzbek] {
+		if handbook.Code == "" || handbook.Name == "" {
+			continue
+		}
+		if _, exists := index[handbook.Code]; exists {
+			continue
+		}
+		index[handbook.Code] = len(stations)
+		stations = append(stations, StationInfo{
+			Code:     handbook.Code,
+			Name:     handbook.Name,
+			NameUz:   handbook.Name,
+			Region:   handbook.Region,
+			IsActive: true,
+		})
+	}
+
+	fill := func(language string, field func(*StationInfo) *string) {
+		for _, handbook := range handbooks[language] {
+			i, exists := index[handbook.Code]
+			if !exists || handbook.Name == "" {
+				continue
+			}
+			if name := field(&stations[i]); *name == "" {
+				*name = handbook.Name
+			}
+		}
+	}
+	fill(LanguageRussian, func(s *StationInfo) *string { return &s.NameRu })
+	fill(LanguageEnglish, func(s *StationInfo) *string { return &s.NameEn })
+
+	return stations
+}
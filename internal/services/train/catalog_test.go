@@ -0,0 +1,185 @@
+package train
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeHandbook(t *testing.T) {
+	builtin := []StationInfo{{Code: "2900000", Name: "Toshkent", NameRu: "Ташкент", IsHub: true}}
+	handbooks := map[string][]Station{
+		LanguageUzbek: {
+			{Code: "2900000", Name: "TOSHKENT"},
+			{Code: "2900111", Name: "Yangi bekat", Region: "Toshkent"},
+			{Code: "", Name: "No code"},
+			{Code: "2900222", Name: ""},
+		},
+		LanguageRussian: {
+			{Code: "2900000", Name: "ТАШКЕНТ"},
+			{Code: "2900111", Name: "Новая станция"},
+			{Code: "2900999", Name: "Не в узбекском справочнике"},
+		},
+	}
+
+	stations := mergeHandbook(builtin, handbooks)
+
+	tests := []struct {
+		code   string
+		name   string
+		nameRu string
+		nameEn string
+		hub    bool
+	}{
+		{"2900000", "Toshkent", "Ташкент", "", true},
+		{"2900111", "Yangi bekat", "Новая станция", "", false},
+	}
+	if len(stations) != len(tests) {
+		t.Fatalf("got %d stations, want %d: %+v", len(stations), len(tests), stations)
+	}
+	for i, tt := range tests {
+		got := stations[i]
+		if got.Code != tt.code || got.Name != tt.name || got.NameRu != tt.nameRu || got.NameEn != tt.nameEn || got.IsHub != tt.hub {
+			t.Errorf("station %d = %+v, want %+v", i, got, tt)
+		}
+	}
+	if !stations[1].IsActive || stations[1].Region != "Toshkent" {
+		t.Errorf("handbook station = %+v, want active in Toshkent region", stations[1])
+	}
+	if builtin[0].Name != "Toshkent" || len(builtin) != 1 {
+		t.Errorf("mergeHandbook changed the built-in stations: %+v", builtin)
+	}
+}
+
+func TestCatalogLookups(t *testing.T) {
+	catalog := NewStationCatalog([]StationInfo{
+		{Code: "2900000", Name: "Toshkent", NameRu: "Ташкент", Aliases: []string{"tashkent"}, IsHub: true},
+		{Code: "2900700", Name: "Samarqand", IsHub: true},
+		{Code: "2900111", Name: "Yangi bekat"},
+		{Code: "2900000", Name: "Toshkent duplicate"},
+	})
+
+	tests := []struct {
+		name   string
+		lookup func() *StationInfo
+		want   string
+	}{
+		{"by code", func() *StationInfo { return catalog.ByCode("2900700") }, "Samarqand"},
+		{"by padded code", func() *StationInfo { return catalog.ByCode(" 2900700 ") }, "Samarqand"},
+		{"first station wins a code", func() *StationInfo { return catalog.ByCode("2900000") }, "Toshkent"},
+		{"by name in any case", func() *StationInfo { return catalog.ByName("YANGI BEKAT") }, "Yangi bekat"},
+		{"by Russian name", func() *StationInfo { return catalog.ByName("Ташкент") }, "Toshkent"},
+		{"by alias", func() *StationInfo { return catalog.ByName("Tashkent") }, "Toshkent"},
+		{"unknown code", func() *StationInfo { return catalog.ByCode("1") }, ""},
+		{"unknown name", func() *StationInfo { return catalog.ByName("Atlantis") }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.lookup()
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("got %+v, want no station", got)
+			case tt.want != "" && (got == nil || got.Name != tt.want):
+				t.Errorf("got %+v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if hubs := catalog.Hubs(); len(hubs) != 2 || hubs[0].Name != "Toshkent" || hubs[1].Name != "Samarqand" {
+		t.Errorf("Hubs() = %+v, want Toshkent and Samarqand", hubs)
+	}
+}
+
+func TestCatalogFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stations", "cache.json")
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	saved := NewStationCatalog(nil)
+	saved.replace(mergeHandbook(builtinStations, map[string][]Station{
+		LanguageUzbek: {{Code: "2900111", Name: "Yangi bekat"}},
+	}), updatedAt)
+	if err := saved.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	loaded := NewStationCatalog(nil)
+	if err := loaded.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if !loaded.UpdatedAt().Equal(updatedAt) {
+		t.Errorf("UpdatedAt() = %v, want %v", loaded.UpdatedAt(), updatedAt)
+	}
+	if got, want := len(loaded.All()), len(builtinStations)+1; got != want {
+		t.Errorf("loaded %d stations, want %d", got, want)
+	}
+	if loaded.ByCode("2900111") == nil {
+		t.Error("handbook station lost in the cache")
+	}
+	if station := loaded.ByName("Toshkent"); station == nil || station.KeyboardRank == 0 {
+		t.Errorf("built-in station lost its curated data: %+v", station)
+	}
+
+	if err := NewStationCatalog(nil).LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadFile of a missing cache succeeded")
+	}
+}
+
+func TestDistanceTo(t *testing.T) {
+	toshkent := LatLng{Latitude: 41.2920, Longitude: 69.2873}
+
+	tests := []struct {
+		name  string
+		to    LatLng
+		want  float64 // Kilometers
+		delta float64
+	}{
+		{"same point", toshkent, 0, 0.001},
+		{"Samarqand", LatLng{Latitude: 39.6775, Longitude: 66.9237}, 270, 5},
+		{"Buxoro", LatLng{Latitude: 39.7219, Longitude: 64.5513}, 440, 10},
+		{"one degree of latitude", LatLng{Latitude: 42.2920, Longitude: 69.2873}, 111.2, 0.5},
+	}
+
+	for _, tt := range tests {
+		got := toshkent.DistanceTo(tt.to)
+		if math.Abs(got-tt.want) > tt.delta {
+			t.Errorf("%s: DistanceTo() = %.1f km, want %.1f±%.1f", tt.name, got, tt.want, tt.delta)
+		}
+		if back := tt.to.DistanceTo(toshkent); math.Abs(back-got) > 1e-9 {
+			t.Errorf("%s: distance back %.3f km differs from %.3f km", tt.name, back, got)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	catalog := NewStationCatalog([]StationInfo{
+		{Code: "1", Name: "Far", IsActive: true, Coordinates: &LatLng{Latitude: 41, Longitude: 60}},
+		{Code: "2", Name: "Near", IsActive: true, Coordinates: &LatLng{Latitude: 41.3, Longitude: 69.3}},
+		{Code: "3", Name: "Closed", IsActive: false, Coordinates: &LatLng{Latitude: 41.29, Longitude: 69.28}},
+		{Code: "4", Name: "Unplaced", IsActive: true},
+		{Code: "5", Name: "Middle", IsActive: true, Coordinates: &LatLng{Latitude: 40, Longitude: 67}},
+	})
+	point := LatLng{Latitude: 41.29, Longitude: 69.28}
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{0, []string{"Near", "Middle", "Far"}},
+		{2, []string{"Near", "Middle"}},
+		{10, []string{"Near", "Middle", "Far"}},
+	}
+
+	for _, tt := range tests {
+		got := catalog.Nearest(point, tt.limit)
+		var names []string
+		for _, candidate := range got {
+			names = append(names, candidate.Station.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Nearest(limit %d) = %v, want %v", tt.limit, names, tt.want)
+		}
+	}
+}
@@ -4,21 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"regexp"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	BaseURL            = "https://eticket.railway.uz/api/v3"
-	BaseURLv1          = "https://eticket.railway.uz/api/v1"
-	TrainsListEndpoint = "/handbook/trains/list"
-	CSRFTokenEndpoint  = "/csrf-token"
-	UserAgent          = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36"
+	BaseURL              = "https://eticket.railway.uz/api/v3"
+	BaseURLv1            = "https://eticket.railway.uz/api/v1"
+	TrainsListEndpoint   = "/handbook/trains/list"
+	StationsListEndpoint = "/handbook/stations/list"
+	CSRFTokenEndpoint    = "/csrf-token"
+	UserAgent            = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36"
 )
 
 // Supported languages for Railway.uz API
@@ -28,12 +31,57 @@ const (
 	LanguageEnglish = "en"
 )
 
-// Client represents the train ticket API client
+// languageKey is the context key for per-request language overrides
+type languageKey struct{}
+
+// WithLanguage returns a context that makes API requests use the given
+// language instead of the client default
+func WithLanguage(ctx context.Context, language string) context.Context {
+	if language == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// LanguageFromContext returns the language set with WithLanguage, if any
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	language, ok := ctx.Value(languageKey{}).(string)
+	return language, ok && language != ""
+}
+
+// credentialsRefreshTimeout bounds a shared CSRF token refresh, which does not
+// stop when the request that started it is cancelled
+const credentialsRefreshTimeout = 30 * time.Second
+
+// Client represents the train ticket API client. It is safe for concurrent
+// use; settings may be changed while requests are in flight.
 type Client struct {
+	mu         sync.RWMutex // Guards the fields below
 	httpClient *http.Client
-	baseURL    string
+	baseURL    string // Search and handbook API
+	baseURLv1  string // CSRF token API
 	headers    map[string]string
 	language   string
+	retry      RetryPolicy
+	limiter    *Limiter
+	breaker    *circuitBreaker
+	session    *Session // Cookies and XSRF token of the railway.uz session
+
+	refreshes *flightGroup[struct{}, string] // Shares a CSRF token refresh between requests
+}
+
+// sessionJar is the cookie jar of the HTTP client. It forwards to the current
+// session so that SetSession never touches the HTTP client in use.
+type sessionJar struct {
+	client *Client
+}
+
+func (j sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.client.Session().SetCookies(u, cookies)
+}
+
+func (j sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.client.Session().Cookies(u)
 }
 
 // NewClient creates a new train API client
@@ -43,12 +91,15 @@ func NewClient(language string) *Client {
 		language = LanguageUzbek
 	}
 
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseURL:  BaseURL,
-		language: language,
+	c := &Client{
+		baseURL:   BaseURL,
+		baseURLv1: BaseURLv1,
+		language:  language,
+		retry:     DefaultRetryPolicy(),
+		limiter:   NewLimiter(DefaultLimits()),
+		breaker:   newCircuitBreaker(DefaultBreakerPolicy()),
+		session:   NewSession(),
+		refreshes: newFlightGroup[struct{}, string](credentialsRefreshTimeout),
 		headers: map[string]string{
 			"Accept":          "application/json",
 			"Accept-Language": language,
@@ -56,28 +107,177 @@ func NewClient(language string) *Client {
 			"User-Agent":      UserAgent,
 		},
 	}
+	c.httpClient = c.newHTTPClient(nil)
+	return c
 }
 
-// SetAuthHeaders sets authentication headers for the client
+// newHTTPClient returns an HTTP client keeping cookies in the session of the
+// client. A nil transport uses http.DefaultTransport.
+func (c *Client) newHTTPClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+		Jar:       sessionJar{client: c},
+	}
+}
+
+// SetAuthHeaders adds credentials copied from a browser to the session: the
+// cookies of a Cookie header and the XSRF token
 func (c *Client) SetAuthHeaders(xsrfToken, cookies string) {
-	c.headers["X-XSRF-TOKEN"] = xsrfToken
-	c.headers["Cookie"] = cookies
+	session := c.Session()
+	if cookies != "" {
+		if err := session.SetCookieHeader(c.apiURL(), cookies); err != nil {
+			log.Printf("Failed to use configured cookies: %v", err)
+		}
+	}
+	if xsrfToken != "" {
+		if err := session.SetCookieHeader(c.apiURL(), xsrfCookie+"="+xsrfToken); err != nil {
+			log.Printf("Failed to use configured XSRF token: %v", err)
+		}
+	}
+}
+
+// SetBaseURL points the client at another railway API, e.g. a local fake in
+// tests. baseURL replaces BaseURL and baseURLv1 replaces BaseURLv1; empty
+// arguments keep the current URL.
+func (c *Client) SetBaseURL(baseURL, baseURLv1 string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if baseURL != "" {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+	if baseURLv1 != "" {
+		c.baseURLv1 = strings.TrimSuffix(baseURLv1, "/")
+	}
+}
+
+// SetTransport makes requests with another http.RoundTripper, e.g. a Recorder
+// or a Replayer. A nil transport uses http.DefaultTransport.
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.httpClient = c.newHTTPClient(transport)
+}
+
+// apiURL returns the base URL of the search and handbook API
+func (c *Client) apiURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.baseURL
+}
+
+// SetSession replaces the cookie session, e.g. with one restored from disk
+func (c *Client) SetSession(session *Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.session = session
+}
+
+// Session returns the cookie session of the client
+func (c *Client) Session() *Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.session
 }
 
-// SetLanguage changes the Accept-Language header for API requests
+// HasValidSession reports whether the session holds an XSRF token that is
+// not about to expire
+func (c *Client) HasValidSession() bool {
+	return !c.Session().NeedsRefresh(c.apiURL())
+}
+
+// SetLanguage changes the default Accept-Language header for API requests.
+// Use WithLanguage to choose the language of a single request.
 func (c *Client) SetLanguage(language string) {
 	if language == "" {
 		language = LanguageUzbek // Default to Uzbek
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.language = language
 	c.headers["Accept-Language"] = language
 }
 
-// GetLanguage returns the current language setting
+// SetRetryPolicy changes how failed requests are retried. Unset fields keep
+// their defaults.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	policy = policy.withDefaults()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = policy
+}
+
+// GetLanguage returns the default language setting
 func (c *Client) GetLanguage() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.language
 }
 
+// retryPolicy returns the current retry policy
+func (c *Client) retryPolicy() RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.retry
+}
+
+// SetLimits changes the request rate and concurrency limits. Requests
+// already admitted finish under the old limits. Unset fields keep their
+// defaults.
+func (c *Client) SetLimits(limits Limits) {
+	limiter := NewLimiter(limits)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limiter = limiter
+}
+
+// SetBreakerPolicy changes when the circuit breaker opens and how long it
+// stays open. Unset fields keep their defaults.
+func (c *Client) SetBreakerPolicy(policy BreakerPolicy) {
+	breaker := newCircuitBreaker(policy)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.breaker = breaker
+}
+
+// send makes an HTTP request once the circuit breaker and the limiter admit
+// it, with the priority of the request context. While the API is down it
+// fails fast with an UnavailableError. The in-flight slot is held until the
+// response body is closed.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	c.mu.RLock()
+	httpClient, limiter, breaker := c.httpClient, c.limiter, c.breaker
+	c.mu.RUnlock()
+
+	if err := breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	release, err := limiter.Acquire(req.Context(), PriorityFromContext(req.Context()))
+	if err != nil {
+		breaker.record(outcomeIgnored, nil)
+		return nil, newTransportError(err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		release()
+		err = newTransportError(err)
+		breaker.record(failureOutcome(err), err)
+		return nil, err
+	}
+
+	// Any answer short of a server error means the API is up
+	if resp.StatusCode >= 500 {
+		breaker.record(outcomeFailure, fmt.Errorf("API answered with status %d", resp.StatusCode))
+	} else {
+		breaker.record(outcomeSuccess, nil)
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
 // makeRequest makes an HTTP request to the API
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
 	var reqBody io.Reader
@@ -89,22 +289,40 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reqBody)
+	c.mu.RLock()
+	baseURL, session := c.baseURL, c.session
+	headers := make(map[string]string, len(c.headers))
+	for key, value := range c.headers {
+		headers[key] = value
+	}
+	c.mu.RUnlock()
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+endpoint, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	for key, value := range c.headers {
+	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	// Refresh the session before its XSRF token expires instead of waiting for a 403
+	if session.NeedsRefresh(baseURL) {
+		if _, err := c.RefreshCSRFToken(ctx); err != nil {
+			log.Printf("Proactive session refresh failed: %v", err)
+		}
+	}
+	if token := session.XSRFToken(baseURL); token != "" {
+		req.Header.Set("X-XSRF-TOKEN", token)
 	}
 
-	return resp, nil
+	// Per-request language takes precedence over the client default
+	if language, ok := LanguageFromContext(ctx); ok {
+		req.Header.Set("Accept-Language", language)
+	}
+
+	return c.send(req)
 }
 
 // InitializeCredentials automatically obtains fresh CSRF token and cookies
@@ -117,65 +335,87 @@ func (c *Client) InitializeCredentials(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize CSRF token: %w", err)
 	}
 
-	// Set the token in headers
-	c.headers["X-XSRF-TOKEN"] = token
-
-	// Create initial cookies with the new token
-	cookies := fmt.Sprintf("XSRF-TOKEN=%s", token)
-	c.headers["Cookie"] = cookies
-
 	log.Printf("✅ Railway.uz API credentials initialized successfully")
-	log.Printf("   XSRF-TOKEN: %s", token[:8]+"...")
-	log.Printf("   Cookies: %s", cookies[:20]+"...")
+	log.Printf("   XSRF-TOKEN: %s", token[:min(8, len(token))]+"...")
 
 	return nil
 }
 
-// RefreshCSRFToken refreshes the CSRF token using the /api/v1/csrf-token endpoint
+// RefreshCSRFToken refreshes the CSRF token using the /api/v1/csrf-token
+// endpoint. Concurrent calls share a single refresh: the first one makes the
+// request and the others wait for its result.
 func (c *Client) RefreshCSRFToken(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", BaseURLv1+CSRFTokenEndpoint, nil)
+	return c.refreshes.do(ctx, struct{}{}, c.refreshCSRFToken)
+}
+
+// refreshCSRFToken makes the CSRF token request
+func (c *Client) refreshCSRFToken(ctx context.Context) (string, error) {
+	c.mu.RLock()
+	baseURLv1 := c.baseURLv1
+	c.mu.RUnlock()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURLv1+CSRFTokenEndpoint, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create CSRF request: %w", err)
 	}
 
 	// Set minimal headers for CSRF token request
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Language", c.language)
-	req.Header.Set("User-Agent", UserAgent)
-
-	// Add existing cookies if available
-	if cookies, exists := c.headers["Cookie"]; exists {
-		req.Header.Set("Cookie", cookies)
+	req.Header.Set("Accept-Language", c.GetLanguage())
+	if language, ok := LanguageFromContext(ctx); ok {
+		req.Header.Set("Accept-Language", language)
 	}
+	req.Header.Set("User-Agent", UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make CSRF request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("CSRF request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", newStatusError(resp, body)
 	}
 
-	// Extract XSRF-TOKEN from Set-Cookie header
-	for _, cookie := range resp.Header["Set-Cookie"] {
-		if strings.Contains(cookie, "XSRF-TOKEN=") {
-			// Extract token value using regex
-			re := regexp.MustCompile(`XSRF-TOKEN=([^;]+)`)
-			matches := re.FindStringSubmatch(cookie)
-			if len(matches) > 1 {
-				return matches[1], nil
-			}
-		}
+	// The session captured the Set-Cookie headers of the response
+	session := c.Session()
+	token := session.XSRFToken(c.apiURL())
+	if token == "" {
+		return "", &AuthError{StatusCode: resp.StatusCode, Err: fmt.Errorf("XSRF-TOKEN not found in response")}
 	}
 
-	return "", fmt.Errorf("XSRF-TOKEN not found in response")
+	if err := session.Save(); err != nil {
+		log.Printf("Failed to save session: %v", err)
+	}
+	return token, nil
 }
 
-// SearchTrains searches for available trains with automatic token refresh
+// renewStaleToken refreshes the CSRF token after it was rejected. A request
+// that sent a token which has since been replaced only needs to be repeated.
+func (c *Client) renewStaleToken(ctx context.Context, rejected string) error {
+	if token := c.Session().XSRFToken(c.apiURL()); token != "" && token != rejected {
+		return nil
+	}
+	_, err := c.RefreshCSRFToken(ctx)
+	return err
+}
+
+// SearchTrains searches for available trains, retrying according to the
+// client retry policy. An error in the response body is returned as an
+// *APIError.
 func (c *Client) SearchTrains(ctx context.Context, req *SearchTrainsRequest) (*SearchTrainsResponse, error) {
+	var result *SearchTrainsResponse
+	err := c.retryPolicy().Do(ctx, "Train search", func(ctx context.Context) error {
+		var err error
+		result, err = c.searchTrains(ctx, req)
+		return err
+	})
+	return result, err
+}
+
+// searchTrains makes a single search request with automatic token refresh
+func (c *Client) searchTrains(ctx context.Context, req *SearchTrainsRequest) (*SearchTrainsResponse, error) {
 	resp, err := c.makeRequest(ctx, "POST", TrainsListEndpoint, req)
 	if err != nil {
 		return nil, err
@@ -185,47 +425,77 @@ func (c *Client) SearchTrains(ctx context.Context, req *SearchTrainsRequest) (*S
 	// If we get a 403 CSRF error, try to refresh the token and retry once
 	if resp.StatusCode == 403 {
 		body, _ := io.ReadAll(resp.Body)
-		if strings.Contains(string(body), "CSRF") || strings.Contains(string(body), "Invalid CSRF Token") {
-			// Try to refresh the CSRF token
-			newToken, refreshErr := c.RefreshCSRFToken(ctx)
-			if refreshErr != nil {
-				return nil, fmt.Errorf("failed to refresh CSRF token: %w", refreshErr)
-			}
+		// Free the in-flight slot, the refresh may need it
+		resp.Body.Close()
+		if !strings.Contains(string(body), "CSRF") {
+			return nil, newStatusError(resp, body)
+		}
 
-			// Update the token in headers
-			c.headers["X-XSRF-TOKEN"] = newToken
-
-			// Update cookies to include new XSRF-TOKEN
-			if cookies, exists := c.headers["Cookie"]; exists {
-				// Replace or add XSRF-TOKEN in cookies
-				re := regexp.MustCompile(`XSRF-TOKEN=[^;]*`)
-				if re.MatchString(cookies) {
-					cookies = re.ReplaceAllString(cookies, "XSRF-TOKEN="+newToken)
-				} else {
-					cookies = "XSRF-TOKEN=" + newToken + ";" + cookies
-				}
-				c.headers["Cookie"] = cookies
-			} else {
-				c.headers["Cookie"] = "XSRF-TOKEN=" + newToken
+		// Refresh the session unless a concurrent request already did;
+		// the jar picks up the new cookies
+		if refreshErr := c.renewStaleToken(ctx, resp.Request.Header.Get("X-XSRF-TOKEN")); refreshErr != nil {
+			var authErr *AuthError
+			if errors.As(refreshErr, &authErr) {
+				return nil, refreshErr
 			}
+			return nil, fmt.Errorf("failed to refresh CSRF token: %w", refreshErr)
+		}
 
-			// Retry the request with new token
-			resp, err = c.makeRequest(ctx, "POST", TrainsListEndpoint, req)
-			if err != nil {
-				return nil, err
-			}
-			defer resp.Body.Close()
+		// Retry the request with new token
+		resp, err = c.makeRequest(ctx, "POST", TrainsListEndpoint, req)
+		if err != nil {
+			return nil, err
 		}
+		defer resp.Body.Close()
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError(resp, body)
 	}
 
 	var result SearchTrainsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, &DecodeError{Err: err}
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &result, nil
+}
+
+// ListStations fetches the station handbook in the request language,
+// retrying according to the client retry policy
+func (c *Client) ListStations(ctx context.Context) (*StationsListResponse, error) {
+	var result *StationsListResponse
+	err := c.retryPolicy().Do(ctx, "Station handbook request", func(ctx context.Context) error {
+		var err error
+		result, err = c.listStations(ctx)
+		return err
+	})
+	return result, err
+}
+
+// listStations makes a single station handbook request
+func (c *Client) listStations(ctx context.Context) (*StationsListResponse, error) {
+	resp, err := c.makeRequest(ctx, "GET", StationsListEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newStatusError(resp, body)
+	}
+
+	var result StationsListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	if result.Error != nil {
+		return nil, result.Error
 	}
 
 	return &result, nil
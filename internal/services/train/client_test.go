@@ -0,0 +1,345 @@
+package train_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+)
+
+// searchRequest is a one-way search from Toshkent to Samarqand
+func searchRequest() *train.SearchTrainsRequest {
+	return &train.SearchTrainsRequest{
+		Directions: train.Directions{
+			Forward: &train.Journey{
+				Date:           "2025-09-02",
+				DepStationCode: "2900000",
+				ArvStationCode: "2900700",
+			},
+		},
+	}
+}
+
+// sampleTrain returns a train from Toshkent to Samarqand with the given free seats
+func sampleTrain(number, departure string, freeSeats int) train.Train {
+	return train.Train{
+		Type:          "СКРСТ",
+		Number:        number,
+		DepartureDate: "02.09.2025 " + departure,
+		ArrivalDate:   "02.09.2025 23:00",
+		TimeOnWay:     "02:18",
+		Brand:         "Afrosiyob",
+		SubRoute: train.SubRoute{
+			DepStationName: "TOSHKENT",
+			DepStationCode: "2900000",
+			ArvStationName: "SAMARQAND",
+			ArvStationCode: "2900700",
+		},
+		Cars: []train.Car{{
+			Type:      "O'rindiqli",
+			FreeSeats: freeSeats,
+			Tariffs:   []train.Tariff{{ClassServiceType: "2Е", FreeSeats: freeSeats, Tariff: 270000}},
+		}},
+	}
+}
+
+func TestClientObtainsTokenBeforeFirstSearch(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetTrains("2900000", "2900700", "2025-09-02", sampleTrain("778Ф", "06:03", 77))
+
+	client := fake.NewClient()
+	response, err := client.SearchTrains(context.Background(), searchRequest())
+	if err != nil {
+		t.Fatalf("SearchTrains: %v", err)
+	}
+
+	if got := len(response.Data.Directions.Forward.Trains); got != 1 {
+		t.Errorf("got %d trains, want 1", got)
+	}
+	if got := fake.CSRFRequests(); got != 1 {
+		t.Errorf("got %d CSRF requests, want 1", got)
+	}
+	if got := fake.SearchRequests(); got != 1 {
+		t.Errorf("got %d search requests, want 1", got)
+	}
+	if token := client.Session().XSRFToken(fake.BaseURL()); token != fake.Token() {
+		t.Errorf("session token %q, want %q", token, fake.Token())
+	}
+}
+
+func TestClientRefreshesRejectedToken(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+
+	client := fake.NewClient()
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("first search: %v", err)
+	}
+
+	fake.ExpireToken()
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("search after expiry: %v", err)
+	}
+
+	if got := fake.CSRFRequests(); got != 2 {
+		t.Errorf("got %d CSRF requests, want 2", got)
+	}
+	// The rejected search is repeated once with the new token
+	if got := fake.SearchRequests(); got != 3 {
+		t.Errorf("got %d search requests, want 3", got)
+	}
+}
+
+func TestClientSharesConcurrentRefresh(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+
+	client := fake.NewClient()
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("first search: %v", err)
+	}
+	fake.ExpireToken()
+
+	const searches = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, searches)
+	for i := 0; i < searches; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.SearchTrains(context.Background(), searchRequest())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent search: %v", err)
+		}
+	}
+	if got := fake.CSRFRequests(); got != 2 {
+		t.Errorf("got %d CSRF requests, want 2 (one refresh shared by all searches)", got)
+	}
+}
+
+func TestClientIsSafeForConcurrentUse(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+
+	client := fake.NewClient()
+	languages := []string{"uz", "ru", "en"}
+
+	// Run with -race: settings change while searches read them
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			client.SetLanguage(languages[i%len(languages)])
+			_ = client.GetLanguage()
+			_ = client.HasValidSession()
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+				t.Errorf("concurrent search: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClientRefreshWaiterCanGiveUp(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+
+	client := fake.NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.RefreshCSRFToken(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RefreshCSRFToken() with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	// The refresh started by the cancelled call still completes for others
+	token, err := client.RefreshCSRFToken(context.Background())
+	if err != nil || token == "" {
+		t.Fatalf("RefreshCSRFToken() = %q, %v, want a token", token, err)
+	}
+	if got := fake.CSRFRequests(); got > 2 {
+		t.Errorf("got %d CSRF requests, want at most 2", got)
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.QueueSearchReplies(
+		railwaytest.Reply{Status: http.StatusBadGateway},
+		railwaytest.Reply{Status: http.StatusServiceUnavailable},
+	)
+
+	client := fake.NewClient()
+	if _, err := client.SearchTrains(context.Background(), searchRequest()); err != nil {
+		t.Fatalf("SearchTrains: %v", err)
+	}
+	if got := fake.SearchRequests(); got != 3 {
+		t.Errorf("got %d search requests, want 3", got)
+	}
+}
+
+func TestClientClassifiesFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		replies  []railwaytest.Reply
+		check    func(error) bool
+		requests int
+	}{
+		{
+			name:    "server error",
+			replies: []railwaytest.Reply{{Status: 500}, {Status: 500}, {Status: 500}},
+			check: func(err error) bool {
+				var e *train.UpstreamError
+				return errors.As(err, &e) && e.StatusCode == 500
+			},
+			requests: 3,
+		},
+		{
+			name:    "rate limit longer than the retry policy allows",
+			replies: []railwaytest.Reply{{Status: http.StatusTooManyRequests, RetryAfter: "60"}},
+			check: func(err error) bool {
+				var e *train.RateLimitError
+				return errors.As(err, &e) && e.RetryAfter == time.Minute
+			},
+			requests: 1,
+		},
+		{
+			name:    "unauthorized",
+			replies: []railwaytest.Reply{{Status: http.StatusUnauthorized}},
+			check: func(err error) bool {
+				var e *train.AuthError
+				return errors.As(err, &e)
+			},
+			requests: 1,
+		},
+		{
+			name:    "malformed body",
+			replies: []railwaytest.Reply{{Body: "not json"}},
+			check: func(err error) bool {
+				var e *train.DecodeError
+				return errors.As(err, &e)
+			},
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := railwaytest.NewServer()
+			defer fake.Close()
+			fake.QueueSearchReplies(tt.replies...)
+
+			_, err := fake.NewClient().SearchTrains(context.Background(), searchRequest())
+			if err == nil || !tt.check(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fake.SearchRequests(); got != tt.requests {
+				t.Errorf("got %d search requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestClientTimesOutSlowResponses(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.QueueSearchReplies(railwaytest.Reply{Delay: 5 * time.Second})
+
+	client := fake.NewClient()
+	client.SetRetryPolicy(train.RetryPolicy{MaxAttempts: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.SearchTrains(ctx, searchRequest())
+	var timeoutErr *train.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("got %v, want a TimeoutError", err)
+	}
+}
+
+func TestClientFailsFastWhileCircuitIsOpen(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.QueueSearchReplies(railwaytest.Reply{Status: 500}, railwaytest.Reply{Status: 500})
+
+	client := fake.NewClient()
+	client.SetRetryPolicy(train.RetryPolicy{MaxAttempts: 1})
+	client.SetBreakerPolicy(train.BreakerPolicy{Threshold: 2, Cooldown: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchTrains(context.Background(), searchRequest()); err == nil {
+			t.Fatalf("search %d succeeded, want a server error", i+1)
+		}
+	}
+
+	_, err := client.SearchTrains(context.Background(), searchRequest())
+	var downErr *train.UnavailableError
+	if !errors.As(err, &downErr) {
+		t.Fatalf("got %v, want an UnavailableError", err)
+	}
+	if train.IsRetryable(err) {
+		t.Errorf("UnavailableError should not be retried")
+	}
+	if got := fake.SearchRequests(); got != 2 {
+		t.Errorf("got %d search requests, want 2", got)
+	}
+}
+
+func TestClientListsStationsInRequestLanguage(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetStations(train.LanguageRussian, train.Station{Code: "2900000", Name: "Ташкент"})
+
+	client := fake.NewClient()
+	response, err := client.ListStations(train.WithLanguage(context.Background(), train.LanguageRussian))
+	if err != nil {
+		t.Fatalf("ListStations: %v", err)
+	}
+
+	stations := response.Data.Stations
+	if len(stations) != 1 || stations[0].Name != "Ташкент" {
+		t.Errorf("got stations %+v, want Ташкент", stations)
+	}
+}
+
+func TestLanguageFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}{
+		{"none", context.Background(), "", false},
+		{"russian", train.WithLanguage(context.Background(), train.LanguageRussian), train.LanguageRussian, true},
+		{"empty keeps default", train.WithLanguage(context.Background(), ""), "", false},
+		{"innermost wins", train.WithLanguage(train.WithLanguage(context.Background(), train.LanguageRussian), train.LanguageEnglish), train.LanguageEnglish, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := train.LanguageFromContext(tt.ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("LanguageFromContext() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
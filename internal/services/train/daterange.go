@@ -0,0 +1,153 @@
+package train
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+)
+
+// MaxRangeDays is the longest date range searched at once
+const MaxRangeDays = 15
+
+// DaySummary aggregates the available trains of a single travel day
+type DaySummary struct {
+	Date      time.Time `json:"date"`
+	Trains    []Train   `json:"trains"`    // Trains with available seats
+	MinPrice  int       `json:"minPrice"`  // Cheapest available tariff, 0 when nothing is available
+	FreeSeats int       `json:"freeSeats"` // Free seats across all trains of the day
+	Err       error     `json:"-"`         // Set when the search for this day failed
+}
+
+// HasSeats reports whether any train of the day has free seats
+func (d *DaySummary) HasSeats() bool {
+	return len(d.Trains) > 0
+}
+
+// SearchDateRange searches every day from params.Date through endDate concurrently
+// and returns one summary per day in date order. Days whose search failed carry
+// the error in DaySummary.Err; an error is returned only if every day failed.
+func (s *Service) SearchDateRange(ctx context.Context, params TrainSearchParams, endDate time.Time) ([]DaySummary, error) {
+	start := startOfDay(params.Date)
+	end := startOfDay(endDate)
+	if end.Before(start) {
+		return nil, fmt.Errorf("end date %s is before start date %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+
+	var days []DaySummary
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		days = append(days, DaySummary{Date: date})
+	}
+	if len(days) > MaxRangeDays {
+		return nil, fmt.Errorf("date range of %d days exceeds the limit of %d days", len(days), MaxRangeDays)
+	}
+
+	searches := make([]TrainSearchParams, len(days))
+	for i, day := range days {
+		searches[i] = params
+		searches[i].Date = day.Date
+		searches[i].ReturnDate = time.Time{}
+	}
+
+	results, errs := s.findAvailableConcurrently(ctx, searches)
+
+	succeeded := false
+	for i := range days {
+		day := &days[i]
+		if errs[i] != nil {
+			day.Err = errs[i]
+			continue
+		}
+		succeeded = true
+
+		day.Trains = results[i]
+		for _, train := range day.Trains {
+			day.FreeSeats += train.GetTotalFreeSeats()
+			if price := train.GetMinPrice(); price > 0 && (day.MinPrice == 0 || price < day.MinPrice) {
+				day.MinPrice = price
+			}
+		}
+	}
+
+	if !succeeded {
+		return nil, fmt.Errorf("failed to search date range: %w", errs[0])
+	}
+	return days, nil
+}
+
+// SearchAroundDate searches the days within ±days of params.Date. Days in the
+// past are skipped.
+func (s *Service) SearchAroundDate(ctx context.Context, params TrainSearchParams, days int) ([]DaySummary, error) {
+	start, end := DateWindow(params.Date, days)
+
+	rangeParams := params
+	rangeParams.Date = start
+	return s.SearchDateRange(ctx, rangeParams, end)
+}
+
+// DateWindow returns the first and last day within ±days of date, starting no
+// earlier than today in the railway's time zone
+func DateWindow(date time.Time, days int) (start, end time.Time) {
+	start = startOfDay(date.AddDate(0, 0, -days))
+	// Today is a UTC midnight; compare it as a day in the location of date
+	year, month, day := Today().Date()
+	if today := time.Date(year, month, day, 0, 0, 0, 0, date.Location()); start.Before(today) {
+		start = today
+	}
+	return start, startOfDay(date.AddDate(0, 0, days))
+}
+
+// CheapestDay returns the index of the day with the lowest available price,
+// or -1 when no day has seats
+func CheapestDay(days []DaySummary) int {
+	cheapest := -1
+	for i, day := range days {
+		if day.MinPrice == 0 {
+			continue
+		}
+		if cheapest == -1 || day.MinPrice < days[cheapest].MinPrice {
+			cheapest = i
+		}
+	}
+	return cheapest
+}
+
+// weekdayKeys maps time.Weekday to the weekday message keys
+var weekdayKeys = [...]string{"weekday.sun", "weekday.mon", "weekday.tue", "weekday.wed", "weekday.thu", "weekday.fri", "weekday.sat"}
+
+// FormatDateRangeResults formats one line per day with the cheapest price and
+// number of trains, marking the cheapest day
+func (s *Service) FormatDateRangeResults(days []DaySummary, locale string) string {
+	l := i18n.New(locale)
+	cheapest := CheapestDay(days)
+
+	var builder strings.Builder
+	for i, day := range days {
+		weekday := l.T(weekdayKeys[day.Date.Weekday()])
+		date := day.Date.Format("02.01")
+
+		switch {
+		case day.Err != nil:
+			builder.WriteString(l.T("train.day_failed", weekday, date))
+		case !day.HasSeats():
+			builder.WriteString(l.T("train.day_sold_out", weekday, date))
+		default:
+			marker := "✅"
+			if i == cheapest {
+				marker = "⭐"
+			}
+			builder.WriteString(l.N("train.day_available", len(day.Trains),
+				marker, weekday, date, s.formatPrice(day.MinPrice), len(day.Trains)))
+		}
+	}
+
+	return builder.String()
+}
+
+// startOfDay returns midnight of the date in its own location
+func startOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,115 @@
+package train
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+)
+
+func TestDateWindow(t *testing.T) {
+	today := Today()
+	day := func(offset int) time.Time { return today.AddDate(0, 0, offset) }
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		days      int
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"around a later date", day(10), 3, day(7), day(13)},
+		{"single day", day(10), 0, day(10), day(10)},
+		{"clipped at today", day(1), 3, day(0), day(4)},
+		{"today", day(0), 2, day(0), day(2)},
+		{"time of day dropped", day(10).Add(15 * time.Hour), 1, day(9), day(11)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := DateWindow(tt.date, tt.days)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("DateWindow() = %s..%s, want %s..%s", start.Format("2006-01-02"), end.Format("2006-01-02"),
+					tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestCheapestDay(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []int
+		want   int
+	}{
+		{"no days", nil, -1},
+		{"sold out", []int{0, 0}, -1},
+		{"single", []int{0, 270000}, 1},
+		{"cheapest", []int{300000, 250000, 0, 270000}, 1},
+		{"first of equal", []int{250000, 250000}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := make([]DaySummary, len(tt.prices))
+			for i, price := range tt.prices {
+				days[i].MinPrice = price
+			}
+			if got := CheapestDay(days); got != tt.want {
+				t.Errorf("CheapestDay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchDateRangeRejectsBadRanges(t *testing.T) {
+	service := NewService()
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		end  time.Time
+		want string
+	}{
+		{"end before start", start.AddDate(0, 0, -1), "before start date"},
+		{"too long", start.AddDate(0, 0, MaxRangeDays), "exceeds the limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SearchDateRange(context.Background(), TrainSearchParams{From: "Toshkent", To: "Samarqand", Date: start}, tt.end)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SearchDateRange() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDateRangeResults(t *testing.T) {
+	service := NewService()
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	available := Train{Cars: []Car{{Tariffs: []Tariff{{FreeSeats: 3, Tariff: 270000}}}}}
+
+	days := []DaySummary{
+		{Date: monday, Trains: []Train{available}, MinPrice: 270000},
+		{Date: monday.AddDate(0, 0, 1)},
+		{Date: monday.AddDate(0, 0, 2), Err: context.DeadlineExceeded},
+	}
+
+	got := service.FormatDateRangeResults(days, i18n.English)
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want one per day:\n%s", len(lines), got)
+	}
+	if !strings.HasPrefix(lines[0], "⭐") || !strings.Contains(lines[0], "02.03") || !strings.Contains(lines[0], "270 000") {
+		t.Errorf("cheapest day line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "03.03") || strings.Contains(lines[1], "⭐") {
+		t.Errorf("sold out day line = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "04.03") {
+		t.Errorf("failed day line = %q", lines[2])
+	}
+}
@@ -0,0 +1,18 @@
+package train
+
+import "time"
+
+// railwayLocation is the time zone of the times returned by the railway API
+var railwayLocation = time.FixedZone("UZT", 5*60*60)
+
+// RailwayDate returns the calendar day t falls on in the railway's time zone,
+// at midnight UTC like the travel dates users pick
+func RailwayDate(t time.Time) time.Time {
+	year, month, day := t.In(railwayLocation).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the current day in the railway's time zone, see RailwayDate
+func Today() time.Time {
+	return RailwayDate(time.Now())
+}
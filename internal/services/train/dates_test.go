@@ -0,0 +1,25 @@
+package train
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRailwayDate(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"UTC evening is the next day in Tashkent", time.Date(2025, 9, 1, 20, 30, 0, 0, time.UTC), time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)},
+		{"UTC afternoon is the same day", time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"Tashkent midnight", time.Date(2025, 9, 2, 0, 0, 0, 0, railwayLocation), time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)},
+		{"last minute of the year", time.Date(2025, 12, 31, 18, 59, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"first minute of the year", time.Date(2025, 12, 31, 19, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := RailwayDate(tt.at); !got.Equal(tt.want) {
+			t.Errorf("%s: RailwayDate(%v) = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody limits how much of an error response body is kept in errors
+const maxErrorBody = 512
+
+// AuthError reports a request rejected for missing or stale credentials,
+// such as a 403 with an invalid CSRF token
+type AuthError struct {
+	StatusCode int    // HTTP status, 0 if the failure was not an HTTP response
+	Body       string // Start of the response body
+	Err        error  // Underlying failure, e.g. of a CSRF token refresh
+}
+
+func (e *AuthError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("authentication failed: %v", e.Err)
+	}
+	return fmt.Sprintf("authentication failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError reports a request rejected with 429 Too Many Requests
+type RateLimitError struct {
+	RetryAfter time.Duration // Wait requested by the server, 0 if not given
+	Body       string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %v", e.RetryAfter)
+	}
+	return "rate limited"
+}
+
+// UpstreamError reports an unexpected HTTP status from the railway API,
+// usually a 5xx while the service is down or overloaded
+type UpstreamError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Wait requested by the server, 0 if not given
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// UnavailableError reports a request that was not made because the railway
+// API kept failing and the client circuit breaker is open
+type UnavailableError struct {
+	RetryAt time.Time // When the API will be tried again
+	Err     error     // Failure that opened the circuit
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("railway service unavailable: %v", e.Err)
+	}
+	return "railway service unavailable"
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError reports a response body that could not be decoded
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError reports a request that did not complete in time
+type TimeoutError struct {
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request timed out: %v", e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkError reports a request that failed before a response was received
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("failed to make request: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Error makes an API error returned in a response body usable as an error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s - %s", e.Code, e.Message)
+}
+
+// IsRetryable reports whether a failed request may succeed if repeated.
+// Credentials, unknown stations and malformed responses will not change by
+// themselves; rate limits, timeouts and upstream failures may.
+func IsRetryable(err error) bool {
+	var (
+		authErr     *AuthError
+		downErr     *UnavailableError
+		stationErr  *StationError
+		apiErr      *APIError
+		decodeErr   *DecodeError
+		rateErr     *RateLimitError
+		timeoutErr  *TimeoutError
+		networkErr  *NetworkError
+		upstreamErr *UpstreamError
+	)
+
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &downErr):
+		// Checked first, it wraps the retryable failure that opened the circuit
+		return false
+	case errors.As(err, &authErr), errors.As(err, &stationErr), errors.As(err, &apiErr), errors.As(err, &decodeErr):
+		return false
+	case errors.As(err, &rateErr), errors.As(err, &timeoutErr), errors.As(err, &networkErr):
+		return true
+	case errors.As(err, &upstreamErr):
+		return upstreamErr.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// newStatusError classifies a non-200 response
+func newStatusError(resp *http.Response, body []byte) error {
+	text := string(body)
+	if len(text) > maxErrorBody {
+		text = text[:maxErrorBody]
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &AuthError{StatusCode: resp.StatusCode, Body: text}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")), Body: text}
+	default:
+		return &UpstreamError{
+			StatusCode: resp.StatusCode,
+			Body:       text,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+}
+
+// newTransportError classifies a failure of http.Client.Do
+func newTransportError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &TimeoutError{Err: err}
+	}
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &NetworkError{Err: err}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
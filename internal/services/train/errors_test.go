@@ -0,0 +1,164 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"cancelled", context.Canceled, false},
+		{"wrapped cancel", fmt.Errorf("search: %w", context.Canceled), false},
+		{"auth", &AuthError{StatusCode: http.StatusForbidden}, false},
+		{"unknown station", &StationError{Query: "Atlantis"}, false},
+		{"api error", &APIError{Code: "400", Message: "bad date"}, false},
+		{"decode", &DecodeError{Err: errors.New("unexpected EOF")}, false},
+		{"rate limit", &RateLimitError{}, true},
+		{"timeout", &TimeoutError{Err: context.DeadlineExceeded}, true},
+		{"network", &NetworkError{Err: errors.New("connection refused")}, true},
+		{"server error", &UpstreamError{StatusCode: http.StatusBadGateway}, true},
+		{"client error", &UpstreamError{StatusCode: http.StatusNotFound}, false},
+		{"wrapped server error", fmt.Errorf("search: %w", &UpstreamError{StatusCode: http.StatusInternalServerError}), true},
+		{"circuit open", &UnavailableError{Err: &UpstreamError{StatusCode: http.StatusServiceUnavailable}}, false},
+		{"plain error", errors.New("something"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	long := make([]byte, maxErrorBody+100)
+	for i := range long {
+		long[i] = 'x'
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       []byte
+		check      func(err error) bool
+	}{
+		{"unauthorized", http.StatusUnauthorized, "", nil, func(err error) bool {
+			var authErr *AuthError
+			return errors.As(err, &authErr) && authErr.StatusCode == http.StatusUnauthorized
+		}},
+		{"forbidden", http.StatusForbidden, "", []byte("CSRF token mismatch"), func(err error) bool {
+			var authErr *AuthError
+			return errors.As(err, &authErr) && authErr.Body == "CSRF token mismatch"
+		}},
+		{"too many requests", http.StatusTooManyRequests, "30", nil, func(err error) bool {
+			var rateErr *RateLimitError
+			return errors.As(err, &rateErr) && rateErr.RetryAfter == 30*time.Second
+		}},
+		{"server error", http.StatusServiceUnavailable, "5", nil, func(err error) bool {
+			var upstreamErr *UpstreamError
+			return errors.As(err, &upstreamErr) && upstreamErr.StatusCode == http.StatusServiceUnavailable && upstreamErr.RetryAfter == 5*time.Second
+		}},
+		{"long body is cut", http.StatusInternalServerError, "", long, func(err error) bool {
+			var upstreamErr *UpstreamError
+			return errors.As(err, &upstreamErr) && len(upstreamErr.Body) == maxErrorBody
+		}},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		if tt.retryAfter != "" {
+			resp.Header.Set("Retry-After", tt.retryAfter)
+		}
+		if err := newStatusError(resp, tt.body); !tt.check(err) {
+			t.Errorf("%s: newStatusError() = %#v", tt.name, err)
+		}
+	}
+}
+
+func TestNewTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"deadline", fmt.Errorf("get: %w", context.DeadlineExceeded), "timeout"},
+		{"net timeout", os.ErrDeadlineExceeded, "timeout"},
+		{"cancelled", fmt.Errorf("get: %w", context.Canceled), "cancelled"},
+		{"refused", errors.New("dial tcp: connection refused"), "network"},
+	}
+
+	for _, tt := range tests {
+		err := newTransportError(tt.err)
+		var (
+			timeoutErr *TimeoutError
+			networkErr *NetworkError
+			got        string
+		)
+		switch {
+		case errors.As(err, &timeoutErr):
+			got = "timeout"
+		case errors.As(err, &networkErr):
+			got = "network"
+		case errors.Is(err, context.Canceled):
+			got = "cancelled"
+		}
+		if got != tt.want {
+			t.Errorf("%s: newTransportError() = %#v, want a %s error", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", 0, 0},
+		{"0", 0, 0},
+		{"-5", 0, 0},
+		{"soon", 0, 0},
+		{"120", 2 * time.Minute, 2 * time.Minute},
+		{" 7 ", 7 * time.Second, 7 * time.Second},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 55 * time.Second, time.Minute},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v to %v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestSearchReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error":{"code":"400","message":"bad date"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(LanguageUzbek)
+	client.SetBaseURL(server.URL, server.URL)
+	client.SetRetryPolicy(RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	response, err := client.SearchTrains(context.Background(), &SearchTrainsRequest{
+		Directions: Directions{Forward: &Journey{Date: "2026-03-01", DepStationCode: "2900000", ArvStationCode: "2900700"}},
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "bad date" {
+		t.Fatalf("SearchTrains() = %v, %v, want the API error", response, err)
+	}
+}
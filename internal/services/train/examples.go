@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
 )
 
 // ExampleUsage demonstrates how to use the train service
@@ -32,7 +34,7 @@ func ExampleUsage() {
 
 	log.Printf("Found %d available trains", len(trains))
 	for _, train := range trains {
-		log.Printf("Train: %s", service.FormatTrainInfo(train))
+		log.Printf("Train: %s", service.FormatTrainInfo(train, i18n.English))
 		log.Printf("Total free seats: %d, Min price: %d UZS", train.GetTotalFreeSeats(), train.GetMinPrice())
 	}
 
@@ -61,7 +63,7 @@ func ExampleUsage() {
 	if len(matchingTrains) > 0 {
 		log.Printf("Alert triggered! Found %d matching trains:", len(matchingTrains))
 		for _, train := range matchingTrains {
-			log.Printf("Matching train: %s", service.FormatTrainInfo(train))
+			log.Printf("Matching train: %s", service.FormatTrainInfo(train, i18n.English))
 		}
 	} else {
 		log.Printf("No trains match the alert criteria yet")
@@ -0,0 +1,71 @@
+package train
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup lets concurrent calls with the same key share a single run of
+// their work. The work outlives the caller that started it, others may be
+// waiting, and is bounded by timeout instead. It starts with the priority of
+// that caller and becomes interactive when an interactive caller joins. It is
+// safe for concurrent use.
+type flightGroup[K comparable, V any] struct {
+	timeout time.Duration // Bounds a shared run
+
+	mu    sync.Mutex
+	calls map[K]*flightCall[V] // Runs in progress
+}
+
+// flightCall is a run shared by every call with its key made while it runs
+type flightCall[V any] struct {
+	done  chan struct{} // Closed when the run finished
+	raise func()        // Makes the run interactive
+	value V
+	err   error
+}
+
+// newFlightGroup creates a group bounding each shared run by timeout
+func newFlightGroup[K comparable, V any](timeout time.Duration) *flightGroup[K, V] {
+	return &flightGroup[K, V]{
+		timeout: timeout,
+		calls:   make(map[K]*flightCall[V]),
+	}
+}
+
+// do returns the result of fn, joining a run for key already in progress.
+// A caller that gives up gets a transport error for its context while the
+// run goes on.
+func (g *flightGroup[K, V]) do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
+	g.mu.Lock()
+	call := g.calls[key]
+	if call == nil {
+		call = &flightCall[V]{done: make(chan struct{})}
+		g.calls[key] = call
+
+		callCtx, raise := withRaisablePriority(context.WithoutCancel(ctx))
+		callCtx, cancel := context.WithTimeout(callCtx, g.timeout)
+		call.raise = raise
+		go func() {
+			defer cancel()
+			call.value, call.err = fn(callCtx)
+
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	} else if PriorityFromContext(ctx) == PriorityInteractive {
+		call.raise()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, newTransportError(ctx.Err())
+	}
+}
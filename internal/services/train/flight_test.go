@@ -0,0 +1,54 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlightGroupSharesRun(t *testing.T) {
+	g := newFlightGroup[string, int](time.Second)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		runs.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	// A caller that gives up leaves the run to the others
+	ctx, cancel := context.WithCancel(context.Background())
+	gaveUp := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "key", fn)
+		gaveUp <- err
+	}()
+	for runs.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-gaveUp; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := g.do(context.Background(), "key", fn); got != 42 || err != nil {
+				t.Errorf("do() = %d, %v, want 42", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := runs.Load(); got != 1 {
+		t.Errorf("got %d runs, want 1 shared by every caller", got)
+	}
+}
@@ -0,0 +1,287 @@
+package train
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Defaults of the client request limits
+const (
+	DefaultRequestRate  = 2.0 // Requests per second
+	DefaultRequestBurst = 5
+	DefaultMaxInFlight  = 4
+)
+
+// Priority orders requests waiting for the limiter. Interactive requests are
+// always admitted before background ones.
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota // A user is waiting for the answer
+	PriorityBackground                  // Alert polling, station sync and other periodic work
+	priorityLevels
+)
+
+// priorityKey is the context key for request priorities
+type priorityKey struct{}
+
+// WithPriority returns a context whose API requests wait for the limiter with
+// the given priority. Requests without a priority are interactive.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority set with WithPriority,
+// PriorityInteractive if none or if the priority was raised
+func PriorityFromContext(ctx context.Context) Priority {
+	if priorityRaised(ctx) {
+		return PriorityInteractive
+	}
+	priority, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok || priority < 0 || priority >= priorityLevels {
+		return PriorityInteractive
+	}
+	return priority
+}
+
+// priorityRaiseKey is the context key for priority raises
+type priorityRaiseKey struct{}
+
+// priorityRaise makes the requests of a context interactive once raised
+type priorityRaise struct {
+	raised chan struct{} // Closed when raised
+	once   sync.Once
+}
+
+// withRaisablePriority returns a context whose requests become interactive,
+// including those already waiting for the limiter, once raise is called.
+// Shared searches use it when a user joins a background search.
+func withRaisablePriority(ctx context.Context) (_ context.Context, raise func()) {
+	r := &priorityRaise{raised: make(chan struct{})}
+	return context.WithValue(ctx, priorityRaiseKey{}, r), func() {
+		r.once.Do(func() { close(r.raised) })
+	}
+}
+
+// priorityRaisedChan returns the channel closed when the priority of ctx is
+// raised, nil if it cannot be
+func priorityRaisedChan(ctx context.Context) <-chan struct{} {
+	if r, ok := ctx.Value(priorityRaiseKey{}).(*priorityRaise); ok {
+		return r.raised
+	}
+	return nil
+}
+
+// priorityRaised reports whether the priority of ctx was raised
+func priorityRaised(ctx context.Context) bool {
+	raised := priorityRaisedChan(ctx)
+	if raised == nil {
+		return false
+	}
+	select {
+	case <-raised:
+		return true
+	default:
+		return false
+	}
+}
+
+// Limits bounds the load the client puts on railway.uz
+type Limits struct {
+	Rate        float64 // Sustained requests per second
+	Burst       int     // Requests that may start at once after a quiet period
+	MaxInFlight int     // Requests waiting for a response at the same time
+}
+
+// DefaultLimits returns the limits used by new clients
+func DefaultLimits() Limits {
+	return Limits{
+		Rate:        DefaultRequestRate,
+		Burst:       DefaultRequestBurst,
+		MaxInFlight: DefaultMaxInFlight,
+	}
+}
+
+// withDefaults fills unset fields from DefaultLimits
+func (l Limits) withDefaults() Limits {
+	def := DefaultLimits()
+	if l.Rate <= 0 {
+		l.Rate = def.Rate
+	}
+	if l.Burst <= 0 {
+		l.Burst = def.Burst
+	}
+	if l.MaxInFlight <= 0 {
+		l.MaxInFlight = def.MaxInFlight
+	}
+	return l
+}
+
+// Limiter admits requests with a token bucket and caps how many are in flight.
+// Waiting requests are admitted by priority, then in arrival order. It is
+// safe for concurrent use.
+type Limiter struct {
+	limits Limits
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time // When tokens were last refilled
+	inFlight int
+	queues   [priorityLevels][]*limiterWaiter
+	timer    *time.Timer // Wakes the queue when the next token is due
+}
+
+// limiterWaiter is a request waiting for admission
+type limiterWaiter struct {
+	ready   chan struct{} // Closed when the request is admitted
+	granted bool
+}
+
+// NewLimiter creates a limiter with a full bucket. Unset limits keep their
+// defaults.
+func NewLimiter(limits Limits) *Limiter {
+	limits = limits.withDefaults()
+	return &Limiter{
+		limits: limits,
+		tokens: float64(limits.Burst),
+		last:   time.Now(),
+	}
+}
+
+// Limits returns the limits of the limiter
+func (l *Limiter) Limits() Limits {
+	return l.limits
+}
+
+// Acquire waits until a request may start and returns the function that
+// must be called once it has finished. A request whose context priority is
+// raised while it waits moves to the interactive queue.
+func (l *Limiter) Acquire(ctx context.Context, priority Priority) (func(), error) {
+	if priority < 0 || priority >= priorityLevels {
+		priority = PriorityInteractive
+	}
+
+	waiter := &limiterWaiter{ready: make(chan struct{})}
+
+	l.mu.Lock()
+	l.queues[priority] = append(l.queues[priority], waiter)
+	l.dispatchLocked()
+	l.mu.Unlock()
+
+	raised := priorityRaisedChan(ctx)
+	for {
+		select {
+		case <-waiter.ready:
+			return l.releaseFunc(), nil
+		case <-raised:
+			raised = nil
+			l.mu.Lock()
+			if priority != PriorityInteractive && l.removeWaiterLocked(priority, waiter) {
+				priority = PriorityInteractive
+				l.queues[priority] = append(l.queues[priority], waiter)
+				l.dispatchLocked()
+			}
+			l.mu.Unlock()
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Admitted while giving up: hand the slot to the next request
+	if waiter.granted {
+		l.inFlight--
+		l.dispatchLocked()
+		return nil, ctx.Err()
+	}
+
+	l.removeWaiterLocked(priority, waiter)
+	return nil, ctx.Err()
+}
+
+// removeWaiterLocked removes a waiting request from its queue and reports
+// whether it was still queued
+func (l *Limiter) removeWaiterLocked(priority Priority, waiter *limiterWaiter) bool {
+	queue := l.queues[priority]
+	for i, w := range queue {
+		if w == waiter {
+			l.queues[priority] = append(queue[:i:i], queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// releaseFunc returns a function that frees an in-flight slot once, however
+// often it is called
+func (l *Limiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.inFlight--
+			l.dispatchLocked()
+		})
+	}
+}
+
+// dispatchLocked admits waiting requests while there are tokens and free
+// slots. Without a token it schedules another dispatch for when one is due.
+func (l *Limiter) dispatchLocked() {
+	now := time.Now()
+	l.tokens = min(float64(l.limits.Burst), l.tokens+now.Sub(l.last).Seconds()*l.limits.Rate)
+	l.last = now
+
+	for l.inFlight < l.limits.MaxInFlight {
+		var waiter *limiterWaiter
+		for priority := range l.queues {
+			if len(l.queues[priority]) > 0 {
+				waiter = l.queues[priority][0]
+				if l.tokens >= 1 {
+					l.queues[priority] = l.queues[priority][1:]
+				}
+				break
+			}
+		}
+		if waiter == nil {
+			return
+		}
+
+		if l.tokens < 1 {
+			if l.timer == nil {
+				wait := time.Duration((1 - l.tokens) / l.limits.Rate * float64(time.Second))
+				l.timer = time.AfterFunc(wait, func() {
+					l.mu.Lock()
+					defer l.mu.Unlock()
+					l.timer = nil
+					l.dispatchLocked()
+				})
+			}
+			return
+		}
+
+		l.tokens--
+		l.inFlight++
+		waiter.granted = true
+		close(waiter.ready)
+	}
+}
+
+// releaseBody frees the in-flight slot of a request when its response body
+// is closed
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,169 @@
+package train
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimitsDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		want   Limits
+	}{
+		{"unset", Limits{}, DefaultLimits()},
+		{"negative", Limits{Rate: -1, Burst: -1, MaxInFlight: -1}, DefaultLimits()},
+		{"set", Limits{Rate: 10, Burst: 1, MaxInFlight: 2}, Limits{Rate: 10, Burst: 1, MaxInFlight: 2}},
+	}
+	for _, tt := range tests {
+		if got := tt.limits.withDefaults(); got != tt.want {
+			t.Errorf("%s: withDefaults() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Priority
+	}{
+		{"unset", context.Background(), PriorityInteractive},
+		{"interactive", WithPriority(context.Background(), PriorityInteractive), PriorityInteractive},
+		{"background", WithPriority(context.Background(), PriorityBackground), PriorityBackground},
+		{"out of range", WithPriority(context.Background(), priorityLevels), PriorityInteractive},
+	}
+	for _, tt := range tests {
+		if got := PriorityFromContext(tt.ctx); got != tt.want {
+			t.Errorf("%s: PriorityFromContext() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// tryAcquire acquires a slot, giving up after a short wait
+func tryAcquire(l *Limiter, priority Priority) (func(), bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release, err := l.Acquire(ctx, priority)
+	return release, err == nil
+}
+
+// waitQueued waits until n requests of the priority are waiting
+func waitQueued(t *testing.T, l *Limiter, priority Priority, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		queued := len(l.queues[priority])
+		l.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d requests of priority %d never queued", n, priority)
+}
+
+func TestLimiterBurst(t *testing.T) {
+	// Practically no refill, so only the burst is admitted
+	l := NewLimiter(Limits{Rate: 0.001, Burst: 2, MaxInFlight: 10})
+
+	for i := 0; i < 2; i++ {
+		if _, ok := tryAcquire(l, PriorityInteractive); !ok {
+			t.Fatalf("request %d of the burst was not admitted", i+1)
+		}
+	}
+	if _, ok := tryAcquire(l, PriorityInteractive); ok {
+		t.Error("request beyond the burst was admitted")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if queued := len(l.queues[PriorityInteractive]); queued != 0 {
+		t.Errorf("%d cancelled requests still queued", queued)
+	}
+}
+
+func TestLimiterRefillsTokens(t *testing.T) {
+	l := NewLimiter(Limits{Rate: 50, Burst: 1, MaxInFlight: 10})
+
+	if _, ok := tryAcquire(l, PriorityInteractive); !ok {
+		t.Fatal("first request was not admitted")
+	}
+	start := time.Now()
+	if _, err := l.Acquire(context.Background(), PriorityInteractive); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if waited := time.Since(start); waited < 10*time.Millisecond {
+		t.Errorf("second request waited %v, want about 20ms for the next token", waited)
+	}
+}
+
+func TestLimiterMaxInFlight(t *testing.T) {
+	l := NewLimiter(Limits{Rate: 1000, Burst: 10, MaxInFlight: 1})
+
+	release, ok := tryAcquire(l, PriorityInteractive)
+	if !ok {
+		t.Fatal("first request was not admitted")
+	}
+	if _, ok := tryAcquire(l, PriorityInteractive); ok {
+		t.Fatal("second request was admitted while the first is in flight")
+	}
+
+	release()
+	release() // Releasing twice frees a single slot
+
+	second, ok := tryAcquire(l, PriorityInteractive)
+	if !ok {
+		t.Fatal("request was not admitted after the slot was freed")
+	}
+	if _, ok := tryAcquire(l, PriorityInteractive); ok {
+		t.Error("a double release freed two slots")
+	}
+	second()
+}
+
+func TestLimiterAdmitsInteractiveFirst(t *testing.T) {
+	l := NewLimiter(Limits{Rate: 1000, Burst: 10, MaxInFlight: 1})
+
+	release, ok := tryAcquire(l, PriorityInteractive)
+	if !ok {
+		t.Fatal("first request was not admitted")
+	}
+
+	admitted := make(chan string, 3)
+	enqueue := func(name string, priority Priority) {
+		go func() {
+			done, err := l.Acquire(context.Background(), priority)
+			if err != nil {
+				t.Errorf("%s: Acquire: %v", name, err)
+				return
+			}
+			admitted <- name
+			done()
+		}()
+	}
+
+	// Background requests arrive first and wait in arrival order
+	enqueue("background 1", PriorityBackground)
+	waitQueued(t, l, PriorityBackground, 1)
+	enqueue("background 2", PriorityBackground)
+	waitQueued(t, l, PriorityBackground, 2)
+	enqueue("interactive", PriorityInteractive)
+	waitQueued(t, l, PriorityInteractive, 1)
+
+	release()
+
+	want := []string{"interactive", "background 1", "background 2"}
+	for i, name := range want {
+		select {
+		case got := <-admitted:
+			if got != name {
+				t.Errorf("admission %d = %s, want %s", i+1, got, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("admission %d never happened", i+1)
+		}
+	}
+}
@@ -94,6 +94,17 @@ type Station struct {
 	Country string `json:"country"`
 }
 
+// StationsListResponse represents the response from the station handbook API
+type StationsListResponse struct {
+	Data  *StationsListData `json:"data,omitempty"`
+	Error *APIError         `json:"error,omitempty"`
+}
+
+// StationsListData contains the stations of the handbook
+type StationsListData struct {
+	Stations []Station `json:"stations"`
+}
+
 // Legacy structures for backward compatibility
 type SeatClass struct {
 	Type        string  `json:"type"`      // e.g., "ECONOMY", "BUSINESS", "LUXURY"
@@ -114,9 +125,24 @@ type APIError struct {
 
 // TrainSearchParams represents user-friendly search parameters
 type TrainSearchParams struct {
-	From string    `json:"from"` // Station name or code
-	To   string    `json:"to"`   // Station name or code
-	Date time.Time `json:"date"` // Travel date
+	From       string    `json:"from"`                 // Station name or code
+	To         string    `json:"to"`                   // Station name or code
+	Date       time.Time `json:"date"`                 // Travel date
+	ReturnDate time.Time `json:"returnDate,omitempty"` // Return travel date for round trips, zero for one-way
+	Language   string    `json:"language,omitempty"`   // Response language, client default if empty
+}
+
+// Round trip legs watched by a ticket alert
+const (
+	DirectionForward = "forward"
+	DirectionReturn  = "return"
+	DirectionBoth    = "both"
+)
+
+// RoundTripTrains holds the trains found for each leg of a round trip
+type RoundTripTrains struct {
+	Forward []Train `json:"forward"`
+	Return  []Train `json:"return"`
 }
 
 // TicketAlert represents a ticket availability alert
@@ -134,13 +160,41 @@ type TicketAlert struct {
 	CreatedAt   time.Time `json:"createdAt"`   // When alert was created
 	LastChecked time.Time `json:"lastChecked"` // Last check time
 	NotifyCount int       `json:"notifyCount"` // Number of notifications sent
+	LastMatched bool      `json:"lastMatched"` // Whether the last check found matching seats
+	Language    string    `json:"language"`    // Language of the owner, used for API requests
+	ReturnDate  time.Time `json:"returnDate"`  // Return travel date, zero for one-way alerts
+	Direction   string    `json:"direction"`   // Legs to watch: forward (default), return or both
 }
 
 // NotificationPayload represents data for sending notifications
 type NotificationPayload struct {
-	Alert TicketAlert `json:"alert"`
-	Train Train       `json:"train"`
-	Seats []SeatClass `json:"availableSeats"`
+	Alert     TicketAlert `json:"alert"`
+	Train     Train       `json:"train"`
+	Seats     []SeatClass `json:"availableSeats"`
+	Direction string      `json:"direction"` // Round trip leg of the train: forward or return
+}
+
+// WatchesForward reports whether the alert watches the outbound leg
+func (a *TicketAlert) WatchesForward() bool {
+	return a.Direction != DirectionReturn || a.ReturnDate.IsZero()
+}
+
+// WatchesReturn reports whether the alert watches the way back
+func (a *TicketAlert) WatchesReturn() bool {
+	return !a.ReturnDate.IsZero() && (a.Direction == DirectionReturn || a.Direction == DirectionBoth)
+}
+
+// LastTravelDate returns the latest travel date watched by the alert
+func (a *TicketAlert) LastTravelDate() time.Time {
+	if a.WatchesReturn() {
+		return a.ReturnDate
+	}
+	return a.Date
+}
+
+// Len returns the number of trains on both legs
+func (r *RoundTripTrains) Len() int {
+	return len(r.Forward) + len(r.Return)
 }
 
 // Helper methods for Train struct
@@ -169,6 +223,16 @@ func (t *Train) GetDate() string {
 	return t.DepartureDate
 }
 
+// ParseDeparture parses departureDate in the railway time zone
+func (t *Train) ParseDeparture() (time.Time, error) {
+	return time.ParseInLocation(trainTimeLayout, t.DepartureDate, railwayLocation)
+}
+
+// ParseArrival parses arrivalDate in the railway time zone
+func (t *Train) ParseArrival() (time.Time, error) {
+	return time.ParseInLocation(trainTimeLayout, t.ArrivalDate, railwayLocation)
+}
+
 // HasAvailableSeats checks if train has any available seats
 func (t *Train) HasAvailableSeats() bool {
 	for _, car := range t.Cars {
@@ -0,0 +1,173 @@
+package train
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+)
+
+// sampleResponse is a search response as returned by the railway API
+const sampleResponse = `{
+	"data": {
+		"directions": {
+			"forward": {
+				"trains": [
+					{
+						"type": "СКРСТ",
+						"number": "778Ф",
+						"departureDate": "02.09.2025 06:03",
+						"timeOnWay": "02:18",
+						"originRoute": {
+							"depStationName": "Toshkent Markaziy",
+							"arvStationName": "Buxoro"
+						},
+						"arrivalDate": "02.09.2025 08:21",
+						"brand": "Afrosiyob",
+						"cars": [
+							{
+								"type": "O'rindiqli",
+								"freeSeats": 77,
+								"tariffs": [
+									{"classServiceType": "1В", "freeSeats": 11, "tariff": 545000},
+									{"classServiceType": "2Е", "freeSeats": 66, "tariff": 270000}
+								]
+							}
+						],
+						"subRoute": {
+							"depStationName": "TOSHKENT",
+							"depStationCode": "2900000",
+							"arvStationName": "SAMARQAND",
+							"arvStationCode": "2900700"
+						},
+						"trainId": null,
+						"comment": null
+					}
+				]
+			}
+		}
+	},
+	"error": null
+}`
+
+func TestParseSearchResponse(t *testing.T) {
+	var response SearchTrainsResponse
+	if err := json.Unmarshal([]byte(sampleResponse), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data == nil || response.Data.Directions.Forward == nil {
+		t.Fatal("no forward direction in response")
+	}
+
+	trains := response.Data.Directions.Forward.Trains
+	if len(trains) != 1 {
+		t.Fatalf("got %d trains, want 1", len(trains))
+	}
+
+	train := trains[0]
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"number", train.Number, "778Ф"},
+		{"departure", train.GetDepartureTime(), "06:03"},
+		{"arrival", train.GetArrivalTime(), "08:21"},
+		{"date", train.GetDate(), "02.09.2025"},
+		{"available", train.HasAvailableSeats(), true},
+		{"free seats", train.GetTotalFreeSeats(), 77},
+		{"min price", train.GetMinPrice(), 270000},
+		{"departure code", train.SubRoute.DepStationCode, "2900000"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	formatted := NewService().FormatTrainInfo(train, i18n.English)
+	if !strings.Contains(formatted, "778Ф") || !strings.Contains(formatted, "545 000") {
+		t.Errorf("formatted train lacks number or price:\n%s", formatted)
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	service := NewService()
+
+	tests := []struct {
+		price int
+		want  string
+	}{
+		{270000, "270 000"},
+		{545000, "545 000"},
+		{1000, "1 000"},
+		{999, "999"},
+		{1234567, "1 234 567"},
+	}
+	for _, tt := range tests {
+		if got := service.formatPrice(tt.price); got != tt.want {
+			t.Errorf("formatPrice(%d) = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestGetStationCode(t *testing.T) {
+	service := NewService()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Toshkent", "2900000"},
+		{"toshkent", "2900000"},
+		{"Tashkent", "2900000"},
+		{"Samarqand", "2900700"},
+		{"samarkand", "2900700"},
+		{"Самарканд", "2900700"},
+		{"Buxoro", "2900800"},
+		{"2900000", "2900000"},
+		{"Unknown City", "Unknown City"},
+	}
+	for _, tt := range tests {
+		if got := service.GetStationCode(tt.input); got != tt.want {
+			t.Errorf("GetStationCode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAlertLegs(t *testing.T) {
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	returnDate := date.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name        string
+		returnDate  time.Time
+		direction   string
+		wantForward bool
+		wantReturn  bool
+		wantLast    time.Time
+	}{
+		{"one way", time.Time{}, "", true, false, date},
+		{"one way ignores direction", time.Time{}, DirectionReturn, true, false, date},
+		{"round trip default", returnDate, "", true, false, date},
+		{"round trip forward", returnDate, DirectionForward, true, false, date},
+		{"round trip return", returnDate, DirectionReturn, false, true, returnDate},
+		{"round trip both", returnDate, DirectionBoth, true, true, returnDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := TicketAlert{Date: date, ReturnDate: tt.returnDate, Direction: tt.direction}
+			if got := alert.WatchesForward(); got != tt.wantForward {
+				t.Errorf("WatchesForward() = %v, want %v", got, tt.wantForward)
+			}
+			if got := alert.WatchesReturn(); got != tt.wantReturn {
+				t.Errorf("WatchesReturn() = %v, want %v", got, tt.wantReturn)
+			}
+			if got := alert.LastTravelDate(); !got.Equal(tt.wantLast) {
+				t.Errorf("LastTravelDate() = %v, want %v", got, tt.wantLast)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package train
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+)
+
+// Defaults for journey planning
+const (
+	DefaultMinTransfer = 45 * time.Minute
+	DefaultMaxTransfer = 12 * time.Hour
+	DefaultMaxResults  = 5
+)
+
+// trainTimeLayout is the layout of Train.DepartureDate and Train.ArrivalDate
+const trainTimeLayout = "02.01.2006 15:04"
+
+// JourneyOptions controls how connecting itineraries are built
+type JourneyOptions struct {
+	MinTransfer time.Duration // Shortest time to change trains at a hub
+	MaxTransfer time.Duration // Longest acceptable wait at a hub
+	MaxResults  int           // Number of itineraries returned
+	Hubs        []string      // Transfer stations, the hubs of the service catalog if empty
+}
+
+// Itinerary is a journey by a direct train or by two trains with a change at a hub
+type Itinerary struct {
+	Legs       []Train       `json:"legs"`
+	Hub        string        `json:"hub,omitempty"` // Transfer station, empty for direct trains
+	Departure  time.Time     `json:"departure"`
+	Arrival    time.Time     `json:"arrival"`
+	Duration   time.Duration `json:"duration"`   // From the first departure to the last arrival
+	Transfer   time.Duration `json:"transfer"`   // Waiting time at the hub
+	TotalPrice int           `json:"totalPrice"` // Sum of the cheapest available tariff of each leg
+}
+
+// IsDirect reports whether the itinerary needs no change of trains
+func (i *Itinerary) IsDirect() bool {
+	return len(i.Legs) == 1
+}
+
+// PlanJourney finds direct trains and connections with one change at a hub
+// station and returns them ranked by total travel time, then by price
+func (s *Service) PlanJourney(ctx context.Context, params TrainSearchParams, opts JourneyOptions) ([]Itinerary, error) {
+	opts = opts.withDefaults(s.catalog)
+
+	fromCode := s.GetStationCode(params.From)
+	toCode := s.GetStationCode(params.To)
+
+	var hubs []string
+	for _, hub := range opts.Hubs {
+		if code := s.GetStationCode(hub); code != fromCode && code != toCode {
+			hubs = append(hubs, hub)
+		}
+	}
+
+	// First pass: the direct route and the first leg to every hub
+	searches := []TrainSearchParams{legParams(params, params.From, params.To, params.Date)}
+	for _, hub := range hubs {
+		searches = append(searches, legParams(params, params.From, hub, params.Date))
+	}
+
+	results, errs := s.findAvailableConcurrently(ctx, searches)
+
+	var itineraries []Itinerary
+	succeeded := false
+
+	if errs[0] == nil {
+		succeeded = true
+		for _, train := range results[0] {
+			if itinerary, ok := newItinerary("", train); ok {
+				itineraries = append(itineraries, itinerary)
+			}
+		}
+	}
+
+	// Second pass: onward legs from hubs that can be reached, on every day a
+	// connection could depart
+	type onward struct {
+		hub       string
+		firstLegs []Train
+	}
+	var onwards []onward
+	var onwardSearches []TrainSearchParams
+
+	for i, hub := range hubs {
+		if errs[i+1] != nil {
+			log.Printf("Journey planner: first leg to %s failed: %v", hub, errs[i+1])
+			continue
+		}
+		succeeded = true
+
+		firstLegs := results[i+1]
+		for _, date := range connectionDates(firstLegs, opts) {
+			onwards = append(onwards, onward{hub: hub, firstLegs: firstLegs})
+			onwardSearches = append(onwardSearches, legParams(params, hub, params.To, date))
+		}
+	}
+
+	if !succeeded {
+		return nil, fmt.Errorf("failed to plan journey: %w", errs[0])
+	}
+
+	onwardResults, onwardErrs := s.findAvailableConcurrently(ctx, onwardSearches)
+	for i, o := range onwards {
+		if onwardErrs[i] != nil {
+			log.Printf("Journey planner: onward leg from %s failed: %v", o.hub, onwardErrs[i])
+			continue
+		}
+		itineraries = append(itineraries, connect(o.hub, o.firstLegs, onwardResults[i], opts)...)
+	}
+
+	sort.SliceStable(itineraries, func(i, j int) bool {
+		if itineraries[i].Duration != itineraries[j].Duration {
+			return itineraries[i].Duration < itineraries[j].Duration
+		}
+		return itineraries[i].TotalPrice < itineraries[j].TotalPrice
+	})
+
+	if len(itineraries) > opts.MaxResults {
+		itineraries = itineraries[:opts.MaxResults]
+	}
+	return itineraries, nil
+}
+
+func (o JourneyOptions) withDefaults(catalog *StationCatalog) JourneyOptions {
+	if o.MinTransfer <= 0 {
+		o.MinTransfer = DefaultMinTransfer
+	}
+	if o.MaxTransfer <= 0 {
+		o.MaxTransfer = DefaultMaxTransfer
+	}
+	if o.MaxResults <= 0 {
+		o.MaxResults = DefaultMaxResults
+	}
+	if len(o.Hubs) == 0 {
+		for _, station := range catalog.Hubs() {
+			o.Hubs = append(o.Hubs, station.Name)
+		}
+	}
+	return o
+}
+
+// legParams returns the search parameters of a single leg
+func legParams(params TrainSearchParams, from, to string, date time.Time) TrainSearchParams {
+	return TrainSearchParams{
+		From:     from,
+		To:       to,
+		Date:     date,
+		Language: params.Language,
+	}
+}
+
+// connectionDates returns the days on which an onward train may depart after
+// any of the first legs
+func connectionDates(firstLegs []Train, opts JourneyOptions) []time.Time {
+	var earliest, latest time.Time
+	for _, train := range firstLegs {
+		arrival, err := train.ParseArrival()
+		if err != nil {
+			continue
+		}
+		if earliest.IsZero() || arrival.Before(earliest) {
+			earliest = arrival
+		}
+		if latest.IsZero() || arrival.After(latest) {
+			latest = arrival
+		}
+	}
+	if earliest.IsZero() {
+		return nil
+	}
+
+	var dates []time.Time
+	last := startOfDay(latest.Add(opts.MaxTransfer))
+	for date := startOfDay(earliest.Add(opts.MinTransfer)); !date.After(last); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// connect joins every first leg with the onward trains that leave the hub
+// within the allowed transfer window
+func connect(hub string, firstLegs, onwardLegs []Train, opts JourneyOptions) []Itinerary {
+	var itineraries []Itinerary
+	for _, first := range firstLegs {
+		arrival, err := first.ParseArrival()
+		if err != nil {
+			continue
+		}
+
+		for _, second := range onwardLegs {
+			departure, err := second.ParseDeparture()
+			if err != nil {
+				continue
+			}
+
+			transfer := departure.Sub(arrival)
+			if transfer < opts.MinTransfer || transfer > opts.MaxTransfer {
+				continue
+			}
+
+			if itinerary, ok := newItinerary(hub, first, second); ok {
+				itinerary.Transfer = transfer
+				itineraries = append(itineraries, itinerary)
+			}
+		}
+	}
+	return itineraries
+}
+
+// newItinerary builds an itinerary from consecutive legs
+func newItinerary(hub string, legs ...Train) (Itinerary, bool) {
+	departure, err := legs[0].ParseDeparture()
+	if err != nil {
+		return Itinerary{}, false
+	}
+	arrival, err := legs[len(legs)-1].ParseArrival()
+	if err != nil {
+		return Itinerary{}, false
+	}
+
+	itinerary := Itinerary{
+		Legs:      legs,
+		Hub:       hub,
+		Departure: departure,
+		Arrival:   arrival,
+		Duration:  arrival.Sub(departure),
+	}
+	for _, leg := range legs {
+		itinerary.TotalPrice += leg.GetMinPrice()
+	}
+	return itinerary, true
+}
+
+// FormatItineraries formats connecting itineraries for display in the given locale
+func (s *Service) FormatItineraries(itineraries []Itinerary, locale string) string {
+	l := i18n.New(locale)
+
+	var builder strings.Builder
+	for i, itinerary := range itineraries {
+		builder.WriteString(l.T("train.itinerary", i+1,
+			itinerary.Departure.Format("02.01 15:04"), itinerary.Arrival.Format("02.01 15:04"),
+			formatDuration(itinerary.Duration, l), s.formatPrice(itinerary.TotalPrice)))
+
+		for j, leg := range itinerary.Legs {
+			if j > 0 {
+				hub := itinerary.Hub
+				if station := s.catalog.ByName(hub); station != nil {
+					hub = station.LocalizedName(locale)
+				}
+				builder.WriteString(l.T("train.itinerary_transfer", hub, formatDuration(itinerary.Transfer, l)))
+			}
+			builder.WriteString(l.T("train.itinerary_leg", leg.Brand, leg.Number,
+				leg.SubRoute.DepStationName, leg.GetDepartureTime(),
+				leg.SubRoute.ArvStationName, leg.GetArrivalTime()))
+		}
+
+		if i < len(itineraries)-1 {
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// formatDuration formats a duration as hours and minutes
+func formatDuration(d time.Duration, l *i18n.Localizer) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	return l.T("train.duration", minutes/60, minutes%60)
+}
@@ -0,0 +1,123 @@
+package train
+
+import (
+	"testing"
+	"time"
+)
+
+// leg returns a train running from departure to arrival, given as
+// "02.01.2006 15:04", with a single tariff
+func leg(number, departure, arrival string, price int) Train {
+	return Train{
+		Number:        number,
+		DepartureDate: departure,
+		ArrivalDate:   arrival,
+		Cars:          []Car{{FreeSeats: 10, Tariffs: []Tariff{{FreeSeats: 10, Tariff: price}}}},
+	}
+}
+
+func TestConnect(t *testing.T) {
+	opts := JourneyOptions{MinTransfer: 45 * time.Minute, MaxTransfer: 3 * time.Hour}
+	first := leg("1", "02.09.2025 06:00", "02.09.2025 08:00", 200000)
+
+	tests := []struct {
+		name         string
+		departure    string
+		wantConnects bool
+		wantTransfer time.Duration
+	}{
+		{"too tight", "02.09.2025 08:30", false, 0},
+		{"shortest transfer", "02.09.2025 08:45", true, 45 * time.Minute},
+		{"comfortable", "02.09.2025 09:30", true, 90 * time.Minute},
+		{"longest transfer", "02.09.2025 11:00", true, 3 * time.Hour},
+		{"too long", "02.09.2025 11:01", false, 0},
+		{"leaves before arrival", "02.09.2025 07:00", false, 0},
+		{"next day", "03.09.2025 07:00", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			second := leg("2", tt.departure, "03.09.2025 20:00", 100000)
+			got := connect("Samarqand", []Train{first}, []Train{second}, opts)
+			if !tt.wantConnects {
+				if len(got) != 0 {
+					t.Errorf("connect() = %d itineraries, want none", len(got))
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("connect() = %d itineraries, want 1", len(got))
+			}
+			if got[0].Transfer != tt.wantTransfer {
+				t.Errorf("transfer = %v, want %v", got[0].Transfer, tt.wantTransfer)
+			}
+			if got[0].Hub != "Samarqand" || got[0].TotalPrice != 300000 || len(got[0].Legs) != 2 {
+				t.Errorf("itinerary = %+v, want both legs via Samarqand for 300 000", got[0])
+			}
+		})
+	}
+}
+
+func TestConnectionDates(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, railwayLocation) }
+
+	tests := []struct {
+		name      string
+		firstLegs []Train
+		maxWait   time.Duration
+		want      []time.Time
+	}{
+		{"no trains", nil, 3 * time.Hour, nil},
+		{"unparsable arrival", []Train{leg("1", "02.09.2025 06:00", "soon", 0)}, 3 * time.Hour, nil},
+		{"same day", []Train{leg("1", "02.09.2025 06:00", "02.09.2025 08:00", 0)}, 3 * time.Hour, []time.Time{day(2)}},
+		{"wait past midnight", []Train{leg("1", "02.09.2025 18:00", "02.09.2025 22:00", 0)}, 12 * time.Hour, []time.Time{day(2), day(3)}},
+		{"arrival after midnight", []Train{leg("1", "02.09.2025 18:00", "02.09.2025 23:30", 0)}, 3 * time.Hour, []time.Time{day(3)}},
+		{"several first legs", []Train{
+			leg("1", "02.09.2025 06:00", "02.09.2025 08:00", 0),
+			leg("2", "02.09.2025 20:00", "03.09.2025 01:00", 0),
+		}, 3 * time.Hour, []time.Time{day(2), day(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connectionDates(tt.firstLegs, JourneyOptions{MinTransfer: 45 * time.Minute, MaxTransfer: tt.maxWait})
+			if len(got) != len(tt.want) {
+				t.Fatalf("connectionDates() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("connectionDates()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewItinerary(t *testing.T) {
+	itinerary, ok := newItinerary("", leg("1", "02.09.2025 06:03", "02.09.2025 08:21", 270000))
+	if !ok {
+		t.Fatal("newItinerary() failed for a valid train")
+	}
+	if !itinerary.IsDirect() || itinerary.Duration != 2*time.Hour+18*time.Minute || itinerary.TotalPrice != 270000 {
+		t.Errorf("newItinerary() = %+v, want a direct 2h18m trip for 270 000", itinerary)
+	}
+
+	if _, ok := newItinerary("", leg("1", "", "02.09.2025 08:21", 0)); ok {
+		t.Error("newItinerary() succeeded without a departure time")
+	}
+}
+
+func TestJourneyOptionsDefaults(t *testing.T) {
+	got := JourneyOptions{}.withDefaults(NewStationCatalog(builtinStations))
+	if got.MinTransfer != DefaultMinTransfer || got.MaxTransfer != DefaultMaxTransfer || got.MaxResults != DefaultMaxResults {
+		t.Errorf("withDefaults() = %+v, want the package defaults", got)
+	}
+	if len(got.Hubs) == 0 {
+		t.Error("withDefaults() has no hubs")
+	}
+
+	set := JourneyOptions{MinTransfer: time.Hour, MaxTransfer: 2 * time.Hour, MaxResults: 1, Hubs: []string{"Navoiy"}}.withDefaults(NewStationCatalog(nil))
+	if set.MinTransfer != time.Hour || set.MaxTransfer != 2*time.Hour || set.MaxResults != 1 || len(set.Hubs) != 1 {
+		t.Errorf("withDefaults() = %+v, want the given options kept", set)
+	}
+}
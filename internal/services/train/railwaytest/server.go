@@ -0,0 +1,336 @@
+// Package railwaytest provides a local fake of the eticket.railway.uz API for
+// tests that must not touch the network.
+package railwaytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// Paths served by the fake, matching the real API
+const (
+	csrfPath     = "/api/v1" + train.CSRFTokenEndpoint
+	trainsPath   = "/api/v3" + train.TrainsListEndpoint
+	stationsPath = "/api/v3" + train.StationsListEndpoint
+)
+
+// testLimits lets tests make requests as fast as they like
+var testLimits = train.Limits{Rate: 1000, Burst: 1000, MaxInFlight: 100}
+
+// testRetryPolicy retries without slowing tests down
+var testRetryPolicy = train.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+// tokenMaxAge is the lifetime of the XSRF cookies the fake hands out
+const tokenMaxAge = time.Hour
+
+// Reply is a scripted answer to a request. The zero Reply makes the fake
+// answer as usual.
+type Reply struct {
+	Status     int           // HTTP status, 200 if zero
+	Body       string        // Raw body, replaces the usual answer if set
+	RetryAfter string        // Retry-After header, if set
+	Delay      time.Duration // Wait before answering, cut short if the client gives up
+	CSRFError  bool          // Answer 403 Invalid CSRF Token as if the token was stale
+}
+
+// Server is a fake railway API. Searches are answered with the trains set for
+// the route and date, unless a scripted reply is queued. Every search must
+// carry the XSRF token the fake handed out last, like the real API. It is
+// safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	mu             sync.Mutex
+	token          string // XSRF token searches must carry
+	tokens         int    // Tokens handed out so far
+	trains         map[string][]train.Train
+	stations       map[string][]train.Station // Handbook by language
+	searchReplies  []Reply
+	csrfReplies    []Reply
+	searches       []*train.SearchTrainsRequest
+	searchRequests int
+	csrfRequests   int
+}
+
+// NewServer starts a fake railway API. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		trains:   make(map[string][]train.Train),
+		stations: make(map[string][]train.Station),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(csrfPath, s.handleCSRF)
+	mux.HandleFunc(trainsPath, s.handleTrains)
+	mux.HandleFunc(stationsPath, s.handleStations)
+	s.server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Close shuts the fake down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// BaseURL is the fake counterpart of train.BaseURL
+func (s *Server) BaseURL() string {
+	return s.server.URL + "/api/v3"
+}
+
+// BaseURLv1 is the fake counterpart of train.BaseURLv1
+func (s *Server) BaseURLv1() string {
+	return s.server.URL + "/api/v1"
+}
+
+// NewClient returns a client pointed at the fake that retries quickly and
+// is not rate limited
+func (s *Server) NewClient() *train.Client {
+	client := train.NewClient(train.LanguageUzbek)
+	client.SetBaseURL(s.BaseURL(), s.BaseURLv1())
+	client.SetRetryPolicy(testRetryPolicy)
+	client.SetLimits(testLimits)
+	return client
+}
+
+// NewService returns a service pointed at the fake that retries quickly and
+// is not rate limited
+func (s *Server) NewService() *train.Service {
+	service := train.NewService()
+	service.SetBaseURL(s.BaseURL(), s.BaseURLv1())
+	service.SetRetryPolicy(testRetryPolicy)
+	service.SetLimits(testLimits)
+	return service
+}
+
+// SetTrains sets the trains found from one station code to another on a
+// date formatted as 2006-01-02
+func (s *Server) SetTrains(from, to, date string, trains ...train.Train) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trains[routeKey(from, to, date)] = trains
+}
+
+// SetStations sets the station handbook returned for a language
+func (s *Server) SetStations(language string, stations ...train.Station) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stations[language] = stations
+}
+
+// QueueSearchReplies scripts the answers to the next searches, in order
+func (s *Server) QueueSearchReplies(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchReplies = append(s.searchReplies, replies...)
+}
+
+// QueueCSRFReplies scripts the answers to the next CSRF token requests, in
+// order. A reply with a 200 status still hands out a token.
+func (s *Server) QueueCSRFReplies(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.csrfReplies = append(s.csrfReplies, replies...)
+}
+
+// ExpireToken makes the token handed out last invalid, so the next search
+// is rejected with a CSRF error
+func (s *Server) ExpireToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// Token returns the XSRF token searches must carry, empty if none was
+// handed out or it expired
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// SearchRequests returns the number of search requests received
+func (s *Server) SearchRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.searchRequests
+}
+
+// CSRFRequests returns the number of CSRF token requests received
+func (s *Server) CSRFRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.csrfRequests
+}
+
+// Searches returns the search requests that carried a valid token
+func (s *Server) Searches() []*train.SearchTrainsRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*train.SearchTrainsRequest(nil), s.searches...)
+}
+
+func (s *Server) handleCSRF(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	s.csrfRequests++
+	reply := pop(&s.csrfReplies)
+	s.mu.Unlock()
+
+	if !wait(r, reply.Delay) {
+		return
+	}
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		writeReply(w, reply, statusBody(reply.Status))
+		return
+	}
+
+	s.mu.Lock()
+	s.tokens++
+	token := fmt.Sprintf("token-%d", s.tokens)
+	s.token = token
+	s.mu.Unlock()
+
+	maxAge := int(tokenMaxAge / time.Second)
+	http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: token, Path: "/", MaxAge: maxAge})
+	http.SetCookie(w, &http.Cookie{Name: "SESSION", Value: "session-" + token, Path: "/", MaxAge: maxAge, HttpOnly: true})
+	writeReply(w, reply, `{"data":null,"error":null}`)
+}
+
+func (s *Server) handleTrains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.searchRequests++
+	reply := pop(&s.searchReplies)
+	valid := s.token != "" && r.Header.Get("X-XSRF-TOKEN") == s.token && hasCookie(r, "XSRF-TOKEN", s.token)
+	s.mu.Unlock()
+
+	if !wait(r, reply.Delay) {
+		return
+	}
+	if reply.CSRFError || !valid {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Invalid CSRF Token"}`)
+		return
+	}
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		writeReply(w, reply, statusBody(reply.Status))
+		return
+	}
+
+	var req train.SearchTrainsRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Directions.Forward == nil {
+		http.Error(w, `{"message":"Bad request"}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.searches = append(s.searches, &req)
+	response := train.SearchTrainsResponse{Data: &train.TrainSearchData{}}
+	response.Data.Directions.Forward = s.direction(req.Directions.Forward)
+	if req.Directions.Return != nil {
+		response.Data.Directions.Return = s.direction(req.Directions.Return)
+	}
+	s.mu.Unlock()
+
+	data, _ := json.Marshal(response)
+	writeReply(w, reply, string(data))
+}
+
+func (s *Server) handleStations(w http.ResponseWriter, r *http.Request) {
+	language := r.Header.Get("Accept-Language")
+
+	s.mu.Lock()
+	stations := s.stations[language]
+	s.mu.Unlock()
+
+	data, _ := json.Marshal(train.StationsListResponse{Data: &train.StationsListData{Stations: stations}})
+	writeReply(w, Reply{}, string(data))
+}
+
+// direction returns the trains set for a journey. Callers hold s.mu.
+func (s *Server) direction(journey *train.Journey) *train.DirectionTrains {
+	trains := s.trains[routeKey(journey.DepStationCode, journey.ArvStationCode, journey.Date)]
+	return &train.DirectionTrains{Trains: append([]train.Train{}, trains...)}
+}
+
+// routeKey identifies the trains of a route on a date
+func routeKey(from, to, date string) string {
+	return from + "|" + to + "|" + date
+}
+
+// pop removes and returns the first reply of a queue, the zero Reply if empty
+func pop(queue *[]Reply) Reply {
+	if len(*queue) == 0 {
+		return Reply{}
+	}
+	reply := (*queue)[0]
+	*queue = (*queue)[1:]
+	return reply
+}
+
+// wait sleeps for delay and reports whether the client is still waiting
+func wait(r *http.Request, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// hasCookie reports whether the request carries a cookie with the value
+func hasCookie(r *http.Request, name, value string) bool {
+	cookie, err := r.Cookie(name)
+	return err == nil && cookie.Value == value
+}
+
+// statusBody is the body of an error answer without a scripted body
+func statusBody(status int) string {
+	data, _ := json.Marshal(map[string]string{"message": http.StatusText(status)})
+	return string(data)
+}
+
+// writeReply writes the scripted reply, or body with the reply status
+func writeReply(w http.ResponseWriter, reply Reply, body string) {
+	if reply.Body != "" {
+		body = reply.Body
+	}
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if reply.RetryAfter != "" {
+		w.Header().Set("Retry-After", reply.RetryAfter)
+	}
+	if strings.HasPrefix(strings.TrimSpace(body), "{") {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
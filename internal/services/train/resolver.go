@@ -0,0 +1,256 @@
+package train
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxStationCandidates limits the suggestions returned with a StationError
+const maxStationCandidates = 5
+
+// Station resolution failures, matched with errors.Is
+var (
+	ErrUnknownStation   = errors.New("unknown station")
+	ErrAmbiguousStation = errors.New("ambiguous station")
+)
+
+// StationError reports a station name that does not resolve to exactly one
+// station. Candidates holds the closest stations, best match first.
+type StationError struct {
+	Query      string
+	Ambiguous  bool
+	Candidates []StationInfo
+}
+
+func (e *StationError) Error() string {
+	var names []string
+	for _, candidate := range e.Candidates {
+		names = append(names, candidate.Name)
+	}
+
+	if e.Ambiguous {
+		return fmt.Sprintf("ambiguous station %q: could be %s", e.Query, strings.Join(names, ", "))
+	}
+	if len(names) > 0 {
+		return fmt.Sprintf("unknown station %q, did you mean %s?", e.Query, strings.Join(names, ", "))
+	}
+	return fmt.Sprintf("unknown station %q", e.Query)
+}
+
+// Unwrap makes errors.Is match ErrUnknownStation or ErrAmbiguousStation
+func (e *StationError) Unwrap() error {
+	if e.Ambiguous {
+		return ErrAmbiguousStation
+	}
+	return ErrUnknownStation
+}
+
+// stationKey is a normalized station name or alias used for matching
+type stationKey struct {
+	key   string
+	index int // Position of the station in the catalog
+}
+
+// Resolve finds the single station meant by a code, name or alias in any
+// language or script. Apostrophe variants and Cyrillic spellings are
+// normalized, and small typos are tolerated. A *StationError is returned
+// when the query matches no station or several equally well.
+func (c *StationCatalog) Resolve(query string) (*StationInfo, error) {
+	query = strings.TrimSpace(query)
+
+	if station := c.ByCode(query); station != nil {
+		return station, nil
+	}
+	if station := c.ByName(query); station != nil {
+		return station, nil
+	}
+
+	key := NormalizeName(query)
+	if key == "" {
+		return nil, &StationError{Query: query}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Same name once apostrophes, case and script are ignored
+	if indexes := c.matchKeys(func(k string) bool { return k == key }); len(indexes) > 0 {
+		return c.resolved(query, indexes)
+	}
+
+	// Beginning of a single station name, e.g. "samarq"
+	if len([]rune(key)) >= 3 {
+		if indexes := c.matchKeys(func(k string) bool { return strings.HasPrefix(k, key) }); len(indexes) > 0 {
+			return c.resolved(query, indexes)
+		}
+	}
+
+	// Typos: closest names by edit distance
+	distances := make(map[int]int)
+	for _, name := range c.keys {
+		d := levenshtein(key, name.key)
+		if best, seen := distances[name.index]; !seen || d < best {
+			distances[name.index] = d
+		}
+	}
+
+	indexes := make([]int, 0, len(distances))
+	for index := range distances {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if distances[indexes[i]] != distances[indexes[j]] {
+			return distances[indexes[i]] < distances[indexes[j]]
+		}
+		return indexes[i] < indexes[j]
+	})
+
+	tolerance := typoTolerance(key)
+	var near []int
+	for _, index := range indexes {
+		if distances[index] <= tolerance {
+			near = append(near, index)
+		}
+	}
+	if len(near) > 0 {
+		// Only the best matches compete; a clear winner is accepted
+		best := distances[near[0]]
+		var tied []int
+		for _, index := range near {
+			if distances[index] == best {
+				tied = append(tied, index)
+			}
+		}
+		return c.resolved(query, tied)
+	}
+
+	// Nothing close enough: suggest the nearest stations
+	var suggestions []int
+	for _, index := range indexes {
+		if distances[index] > 2*tolerance || len(suggestions) == maxStationCandidates {
+			break
+		}
+		suggestions = append(suggestions, index)
+	}
+	return nil, &StationError{Query: query, Candidates: c.stationsAt(suggestions)}
+}
+
+// matchKeys returns the stations having a normalized name that satisfies
+// match, in catalog order. Callers must hold c.mu.
+func (c *StationCatalog) matchKeys(match func(key string) bool) []int {
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, name := range c.keys {
+		if !seen[name.index] && match(name.key) {
+			seen[name.index] = true
+			indexes = append(indexes, name.index)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// resolved returns the station if exactly one matched, or an ambiguity error.
+// Callers must hold c.mu.
+func (c *StationCatalog) resolved(query string, indexes []int) (*StationInfo, error) {
+	if len(indexes) == 1 {
+		station := c.stations[indexes[0]]
+		return &station, nil
+	}
+
+	if len(indexes) > maxStationCandidates {
+		indexes = indexes[:maxStationCandidates]
+	}
+	return nil, &StationError{Query: query, Ambiguous: true, Candidates: c.stationsAt(indexes)}
+}
+
+// stationsAt returns copies of the stations at the given positions. Callers
+// must hold c.mu.
+func (c *StationCatalog) stationsAt(indexes []int) []StationInfo {
+	stations := make([]StationInfo, 0, len(indexes))
+	for _, index := range indexes {
+		stations = append(stations, c.stations[index])
+	}
+	return stations
+}
+
+// typoTolerance is the largest edit distance accepted as a typo of key
+func typoTolerance(key string) int {
+	n := len([]rune(key))
+	switch {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// cyrillicToLatin transliterates Uzbek and Russian Cyrillic letters to the
+// Uzbek Latin alphabet, without apostrophes
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "j", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "x", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sh", 'ъ': "",
+	'ы': "i", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+}
+
+// NormalizeName lowercases a name, transliterates Cyrillic to Latin and
+// drops apostrophes, punctuation and spaces, so "Qo‘qon", "Qoqon" and
+// "Қўқон" all become "qoqon". Station names and other words users type are
+// compared in this form.
+func NormalizeName(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			builder.WriteString(latin)
+			continue
+		}
+		if isApostrophe(r) {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// isApostrophe reports whether r is one of the marks written in o‘ and g‘.
+// The modifier letters count as letters for unicode.IsLetter.
+func isApostrophe(r rune) bool {
+	switch r {
+	case '\'', '`', '‘', '’', 'ʻ', 'ʼ', 'ʹ':
+		return true
+	}
+	return false
+}
+
+// levenshtein returns the edit distance between two strings in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,179 @@
+package train
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"toshkent", "toshkent", 0},
+		{"toshkent", "tashkent", 1},
+		{"samarqand", "samarkand", 1},
+		{"buxoro", "bukhara", 4},
+		{"qoqon", "qqon", 1},
+		{"navoiy", "navoi", 1},
+		{"ab", "ba", 2},
+		{"ташкент", "ташкет", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Toshkent", "toshkent"},
+		{"  TOSHKENT ", "toshkent"},
+		{"Qo'qon", "qoqon"},
+		{"Qo‘qon", "qoqon"},
+		{"Qoʻqon", "qoqon"},
+		{"Qo`qon", "qoqon"},
+		{"Қўқон", "qoqon"},
+		{"Ташкент", "tashkent"},
+		{"Тошкент", "toshkent"},
+		{"Самарқанд", "samarqand"},
+		{"Ғиждувон", "gijduvon"},
+		{"Шаҳрисабз", "shahrisabz"},
+		{"Toshkent-Janubiy", "toshkentjanubiy"},
+		{"Qarshi 2", "qarshi2"},
+		{"'-. ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTypoTolerance(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"xiva", 1},
+		{"qoqon", 2},
+		{"toshkent", 2},
+		{"samarqand", 3},
+		{"гулистан", 2},
+	}
+
+	for _, tt := range tests {
+		if got := typoTolerance(tt.key); got != tt.want {
+			t.Errorf("typoTolerance(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	catalog := NewStationCatalog(builtinStations)
+
+	tests := []struct {
+		query string
+		want  string // Station name, empty if the query must fail
+	}{
+		{"2900000", "Toshkent"},
+		{"Toshkent", "Toshkent"},
+		{"tashkent", "Toshkent"},
+		{"Ташкент", "Toshkent"},
+		{"Тошкент", "Toshkent"},
+		{"Samarkand", "Samarqand"},
+		{"Самарқанд", "Samarqand"},
+		{"samarq", "Samarqand"},
+		{"Samarqnad", "Samarqand"},
+		{"Toshknet", "Toshkent"},
+		{"Bukhara", "Buxoro"},
+		{"Buxaro", "Buxoro"},
+		{"Atlantis", ""},
+		{"", ""},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		station, err := catalog.Resolve(tt.query)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Resolve(%q) = %s, want an error", tt.query, station.Name)
+			} else if !errors.Is(err, ErrUnknownStation) {
+				t.Errorf("Resolve(%q) error = %v, want ErrUnknownStation", tt.query, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q) error = %v, want %s", tt.query, err, tt.want)
+			continue
+		}
+		if station.Name != tt.want {
+			t.Errorf("Resolve(%q) = %s, want %s", tt.query, station.Name, tt.want)
+		}
+	}
+}
+
+func TestResolveAmbiguous(t *testing.T) {
+	catalog := NewStationCatalog([]StationInfo{
+		{Code: "1", Name: "Qarshi"},
+		{Code: "2", Name: "Qarshi-2"},
+		{Code: "3", Name: "Termiz"},
+		{Code: "4", Name: "Termez-Yuk"},
+		{Code: "5", Name: "Jizzax"},
+		{Code: "6", Name: "Jizzak"},
+	})
+
+	tests := []struct {
+		query      string
+		want       string
+		ambiguous  bool
+		candidates []string
+	}{
+		{"Qarshi", "Qarshi", false, nil},
+		{"qarsh", "", true, []string{"Qarshi", "Qarshi-2"}},
+		{"Qarshi 2", "Qarshi-2", false, nil},
+		{"Termez", "Termez-Yuk", false, nil},
+		{"Jizzaq", "", true, []string{"Jizzax", "Jizzak"}},
+		{"Toshkent", "", false, nil},
+	}
+
+	for _, tt := range tests {
+		station, err := catalog.Resolve(tt.query)
+		if tt.want != "" {
+			if err != nil || station.Name != tt.want {
+				t.Errorf("Resolve(%q) = %v, %v, want %s", tt.query, station, err, tt.want)
+			}
+			continue
+		}
+
+		var stationErr *StationError
+		if !errors.As(err, &stationErr) {
+			t.Errorf("Resolve(%q) error = %v, want a *StationError", tt.query, err)
+			continue
+		}
+		if stationErr.Ambiguous != tt.ambiguous || errors.Is(err, ErrAmbiguousStation) != tt.ambiguous {
+			t.Errorf("Resolve(%q) ambiguous = %v, want %v", tt.query, stationErr.Ambiguous, tt.ambiguous)
+		}
+		if tt.ambiguous {
+			var names []string
+			for _, candidate := range stationErr.Candidates {
+				names = append(names, candidate.Name)
+			}
+			if len(names) != len(tt.candidates) || names[0] != tt.candidates[0] || names[1] != tt.candidates[1] {
+				t.Errorf("Resolve(%q) candidates = %v, want %v", tt.query, names, tt.candidates)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Defaults of the client retry policy
+const (
+	DefaultRetryAttempts  = 3
+	DefaultRetryBaseDelay = 1 * time.Second
+	DefaultRetryMaxDelay  = 10 * time.Second
+	DefaultRetryJitter    = 0.5
+)
+
+// NoJitter as RetryPolicy.Jitter makes every delay exactly the computed backoff
+const NoJitter = -1
+
+// RetryPolicy decides how failed API requests are repeated. The delay doubles
+// with every attempt up to MaxDelay and is randomized by Jitter so that
+// concurrent callers don't retry in lockstep. A Retry-After sent by the
+// server replaces the computed delay.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first, 1 disables retries
+	BaseDelay   time.Duration // Delay before the second attempt
+	MaxDelay    time.Duration // Upper bound of a delay; a longer Retry-After ends retrying
+	Jitter      float64       // Fraction of each delay that is randomized, up to 1; NoJitter disables it
+
+	// Retryable reports whether an error is worth another attempt,
+	// IsRetryable if nil
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns the policy used by new clients
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      DefaultRetryJitter,
+		Retryable:   IsRetryable,
+	}
+}
+
+// withDefaults fills unset fields from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = def.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = def.MaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	if p.Jitter == 0 {
+		p.Jitter = def.Jitter
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	}
+	if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.Retryable == nil {
+		p.Retryable = def.Retryable
+	}
+	return p
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry)
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// Keep at least (1 - Jitter) of the delay and randomize the rest
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable,
+// or runs out of attempts. The last error is returned.
+func (p RetryPolicy) Do(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			if attempt > 1 {
+				log.Printf("%s succeeded on attempt %d", operation, attempt)
+			}
+			return nil
+		}
+
+		if attempt == p.MaxAttempts || !p.Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := p.Backoff(attempt)
+		if wait := retryAfter(err); wait > 0 {
+			if wait > p.MaxDelay {
+				log.Printf("%s failed, server asked to wait %v, not retrying: %v", operation, wait, err)
+				return err
+			}
+			delay = wait
+		}
+
+		log.Printf("%s failed (attempt %d/%d), retrying in %v: %v", operation, attempt, p.MaxAttempts, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// retryAfter returns the wait requested by the server with the error, if any
+func retryAfter(err error) time.Duration {
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.RetryAfter
+	}
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.RetryAfter
+	}
+	return 0
+}
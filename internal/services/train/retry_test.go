@@ -0,0 +1,136 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   RetryPolicy
+	}{
+		{"unset", RetryPolicy{}, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 0.5}},
+		{"set", RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Second, Jitter: 0.2},
+			RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Second, Jitter: 0.2}},
+		{"no jitter", RetryPolicy{Jitter: NoJitter}, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 0}},
+		{"jitter above 1", RetryPolicy{Jitter: 3}, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 1}},
+		{"max below base", RetryPolicy{BaseDelay: time.Minute}, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Minute, Jitter: 0.5}},
+	}
+
+	for _, tt := range tests {
+		got := tt.policy.withDefaults()
+		if got.Retryable == nil {
+			t.Errorf("%s: withDefaults() has no Retryable", tt.name)
+		}
+		if got.MaxAttempts != tt.want.MaxAttempts || got.BaseDelay != tt.want.BaseDelay ||
+			got.MaxDelay != tt.want.MaxDelay || got.Jitter != tt.want.Jitter {
+			t.Errorf("%s: withDefaults() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffDoubles(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: NoJitter}.withDefaults()
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.retry); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultBackoffSpreadsDelays(t *testing.T) {
+	// Only the delays are set, as the bot does from its config
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}.withDefaults()
+
+	const samples = 100
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < samples; i++ {
+		delay := policy.Backoff(2)
+		if delay < time.Second || delay > 2*time.Second {
+			t.Fatalf("Backoff(2) = %v, want between 1s and 2s", delay)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < samples/2 {
+		t.Errorf("got %d distinct delays out of %d, want callers spread out", len(seen), samples)
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}.withDefaults()
+	serverError := &UpstreamError{StatusCode: 502}
+
+	tests := []struct {
+		name         string
+		errs         []error // Returned by consecutive attempts, nil once exhausted
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"first attempt succeeds", nil, 1, false},
+		{"succeeds on retry", []error{serverError}, 2, false},
+		{"runs out of attempts", []error{serverError, serverError, serverError, serverError}, 3, true},
+		{"not retryable", []error{&AuthError{StatusCode: 403}}, 1, true},
+		{"retry-after too long", []error{&RateLimitError{RetryAfter: time.Minute}}, 1, true},
+		{"short retry-after", []error{&RateLimitError{RetryAfter: time.Millisecond}}, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := fast.Do(context.Background(), "test", func(context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+			switch {
+			case !tt.wantErr && err != nil:
+				t.Errorf("Do() = %v, want success", err)
+			case tt.wantErr && err != tt.errs[attempts-1]:
+				t.Errorf("Do() = %v, want the error of the last attempt", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoStopsWhenCancelled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}.withDefaults()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	start := time.Now()
+	err := policy.Do(ctx, "test", func(context.Context) error {
+		attempts++
+		return &NetworkError{Err: errors.New("connection reset")}
+	})
+
+	if err == nil || attempts != 1 {
+		t.Errorf("Do() = %v after %d attempts, want the first failure", err, attempts)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do() waited %v after the context was done", elapsed)
+	}
+}
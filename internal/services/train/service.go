@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
 )
 
 // Service provides train ticket search and monitoring functionality
 type Service struct {
 	client   *Client
-	stations map[string]Station // Cache for station lookup
+	catalog  *StationCatalog // Stations of this service, seeded with the built-in ones
+	searches *searchCache    // Recent search responses shared between callers
 }
 
 // NewService creates a new train service with default language (Uzbek)
@@ -18,11 +25,12 @@ func NewService() *Service {
 	return NewServiceWithLanguage(LanguageUzbek)
 }
 
-// NewServiceWithLanguage creates a new train service with specified language
+// NewServiceWithLanguage creates a new train service with specified default language
 func NewServiceWithLanguage(language string) *Service {
 	return &Service{
 		client:   NewClient(language),
-		stations: make(map[string]Station),
+		catalog:  NewStationCatalog(builtinStations),
+		searches: newSearchCache(DefaultSearchCacheTTL, DefaultStaleResultsMaxAge),
 	}
 }
 
@@ -31,45 +39,123 @@ func (s *Service) SetAuthCredentials(xsrfToken, cookies string) {
 	s.client.SetAuthHeaders(xsrfToken, cookies)
 }
 
+// SetBaseURL points the service at another railway API, see Client.SetBaseURL
+func (s *Service) SetBaseURL(baseURL, baseURLv1 string) {
+	s.client.SetBaseURL(baseURL, baseURLv1)
+}
+
+// OpenSession restores the railway.uz session saved at path and keeps saving
+// it there, so restarts don't need a fresh handshake
+func (s *Service) OpenSession(path string) error {
+	session, err := OpenSession(path)
+	if err != nil {
+		return err
+	}
+	s.client.SetSession(session)
+	return nil
+}
+
+// HasValidSession reports whether the session holds an XSRF token that is
+// not about to expire
+func (s *Service) HasValidSession() bool {
+	return s.client.HasValidSession()
+}
+
+// SaveSession writes the session to disk if it was opened from a file
+func (s *Service) SaveSession() error {
+	return s.client.Session().Save()
+}
+
+// SetTransport makes railway requests with another http.RoundTripper, see
+// Client.SetTransport
+func (s *Service) SetTransport(transport http.RoundTripper) {
+	s.client.SetTransport(transport)
+}
+
+// SetRetryPolicy changes how failed API requests are retried
+func (s *Service) SetRetryPolicy(policy RetryPolicy) {
+	s.client.SetRetryPolicy(policy)
+}
+
+// SetLimits changes how fast and how many API requests may be made
+func (s *Service) SetLimits(limits Limits) {
+	s.client.SetLimits(limits)
+}
+
+// SetSearchCacheTTL changes how long search responses are reused. Zero
+// disables the cache; identical concurrent searches are still shared.
+func (s *Service) SetSearchCacheTTL(ttl time.Duration) {
+	s.searches.setTTL(ttl)
+}
+
+// SetStaleResultsMaxAge changes how long search responses are kept to be
+// shown while the railway API is down
+func (s *Service) SetStaleResultsMaxAge(maxAge time.Duration) {
+	s.searches.setMaxAge(maxAge)
+}
+
+// SetBreakerPolicy changes when API requests stop being made to a failing
+// railway API
+func (s *Service) SetBreakerPolicy(policy BreakerPolicy) {
+	s.client.SetBreakerPolicy(policy)
+}
+
 // InitializeCredentials automatically obtains fresh Railway.uz API credentials
 func (s *Service) InitializeCredentials(ctx context.Context) error {
 	return s.client.InitializeCredentials(ctx)
 }
 
-// SetLanguage changes the language for API requests
-func (s *Service) SetLanguage(language string) {
-	s.client.SetLanguage(language)
-}
-
-// GetLanguage returns the current language setting
+// GetLanguage returns the default language used when a search does not specify one
 func (s *Service) GetLanguage() string {
 	return s.client.GetLanguage()
 }
 
 // SearchTrains searches for available trains between stations
 func (s *Service) SearchTrains(ctx context.Context, params TrainSearchParams) (*SearchTrainsResponse, error) {
+	// Unknown stations are rejected before any request is made
+	fromCode, err := s.ResolveStationCode(params.From)
+	if err != nil {
+		return nil, err
+	}
+	toCode, err := s.ResolveStationCode(params.To)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert user-friendly params to API request format
 	req := &SearchTrainsRequest{
 		Directions: Directions{
 			Forward: &Journey{
 				Date:           params.Date.Format("2006-01-02"),
-				DepStationCode: s.GetStationCode(params.From),
-				ArvStationCode: s.GetStationCode(params.To),
+				DepStationCode: fromCode,
+				ArvStationCode: toCode,
 			},
 		},
 	}
 
-	log.Printf("Searching trains from %s to %s on %s", params.From, params.To, params.Date.Format("2006-01-02"))
+	// Round trips ask for the way back in the same request
+	if !params.ReturnDate.IsZero() {
+		req.Directions.Return = &Journey{
+			Date:           params.ReturnDate.Format("2006-01-02"),
+			DepStationCode: toCode,
+			ArvStationCode: fromCode,
+		}
+		log.Printf("Searching round trip from %s to %s on %s, returning on %s",
+			params.From, params.To, params.Date.Format("2006-01-02"), params.ReturnDate.Format("2006-01-02"))
+	} else {
+		log.Printf("Searching trains from %s to %s on %s", params.From, params.To, params.Date.Format("2006-01-02"))
+	}
+
+	ctx = WithLanguage(ctx, params.Language)
 
-	response, err := s.client.SearchTrains(ctx, req)
+	// Identical searches within the cache TTL share one upstream response
+	response, err := s.searches.get(ctx, s.searchKey(req, params.Language), func(ctx context.Context) (*SearchTrainsResponse, error) {
+		return s.client.SearchTrains(ctx, req)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search trains: %w", err)
 	}
 
-	if response.Error != nil {
-		return nil, fmt.Errorf("API error: %s - %s", response.Error.Code, response.Error.Message)
-	}
-
 	if response.Data == nil {
 		return nil, fmt.Errorf("no data received from API")
 	}
@@ -77,6 +163,51 @@ func (s *Service) SearchTrains(ctx context.Context, params TrainSearchParams) (*
 	return response, nil
 }
 
+// searchKey returns the cache key of a search request
+func (s *Service) searchKey(req *SearchTrainsRequest, language string) searchKey {
+	key := searchKey{
+		From:     req.Directions.Forward.DepStationCode,
+		To:       req.Directions.Forward.ArvStationCode,
+		Date:     req.Directions.Forward.Date,
+		Language: language,
+	}
+	if req.Directions.Return != nil {
+		key.ReturnDate = req.Directions.Return.Date
+	}
+	if key.Language == "" {
+		key.Language = s.client.GetLanguage()
+	}
+	return key
+}
+
+// LastAvailableTrains returns the trains with available seats of the latest
+// response kept for a one-way search and when it was fetched. It is meant for
+// showing outdated results while the railway API is down.
+func (s *Service) LastAvailableTrains(params TrainSearchParams) ([]Train, time.Time, bool) {
+	fromCode, err := s.ResolveStationCode(params.From)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	toCode, err := s.ResolveStationCode(params.To)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+
+	response, fetchedAt, ok := s.searches.last(s.searchKey(&SearchTrainsRequest{
+		Directions: Directions{
+			Forward: &Journey{
+				Date:           params.Date.Format("2006-01-02"),
+				DepStationCode: fromCode,
+				ArvStationCode: toCode,
+			},
+		},
+	}, params.Language))
+	if !ok || response.Data == nil {
+		return nil, time.Time{}, false
+	}
+	return availableTrains(response.Data.Directions.Forward), fetchedAt, true
+}
+
 // FindAvailableTrains returns only trains with available seats
 func (s *Service) FindAvailableTrains(ctx context.Context, params TrainSearchParams) ([]Train, error) {
 	response, err := s.SearchTrains(ctx, params)
@@ -84,62 +215,183 @@ func (s *Service) FindAvailableTrains(ctx context.Context, params TrainSearchPar
 		return nil, err
 	}
 
-	if response.Data == nil || response.Data.Directions.Forward == nil {
+	if response.Data == nil {
 		return []Train{}, nil
 	}
 
-	var availableTrains []Train
-	for _, train := range response.Data.Directions.Forward.Trains {
+	return availableTrains(response.Data.Directions.Forward), nil
+}
+
+// FindAvailableRoundTrip searches both legs of a round trip in a single request
+// and returns the trains with available seats for each leg
+func (s *Service) FindAvailableRoundTrip(ctx context.Context, params TrainSearchParams) (*RoundTripTrains, error) {
+	if params.ReturnDate.IsZero() {
+		return nil, fmt.Errorf("return date is required for a round trip search")
+	}
+	if params.ReturnDate.Before(params.Date) {
+		return nil, fmt.Errorf("return date %s is before departure date %s",
+			params.ReturnDate.Format("2006-01-02"), params.Date.Format("2006-01-02"))
+	}
+
+	response, err := s.SearchTrains(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoundTripTrains{
+		Forward: availableTrains(response.Data.Directions.Forward),
+		Return:  availableTrains(response.Data.Directions.Return),
+	}, nil
+}
+
+// searchConcurrency bounds the parallel upstream requests of a single
+// multi-search operation such as a date range or journey plan
+const searchConcurrency = 3
+
+// findAvailableConcurrently runs FindAvailableTrains for every search with a
+// bounded number of requests in flight. Results and errors follow the order
+// of the searches.
+func (s *Service) findAvailableConcurrently(ctx context.Context, searches []TrainSearchParams) ([][]Train, []error) {
+	results := make([][]Train, len(searches))
+	errs := make([]error, len(searches))
+
+	sem := make(chan struct{}, searchConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range searches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			results[i], errs[i] = s.FindAvailableTrains(ctx, searches[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// availableTrains returns the trains of a direction that have free seats
+func availableTrains(direction *DirectionTrains) []Train {
+	if direction == nil {
+		return []Train{}
+	}
+
+	var trains []Train
+	for _, train := range direction.Trains {
 		if train.HasAvailableSeats() {
-			availableTrains = append(availableTrains, train)
+			trains = append(trains, train)
 		}
 	}
 
-	return availableTrains, nil
+	return trains
 }
 
 // CheckTicketAvailability checks if tickets are available for the given alert criteria
+// on any of the legs the alert watches
 func (s *Service) CheckTicketAvailability(ctx context.Context, alert TicketAlert) ([]Train, error) {
-	params := TrainSearchParams{
-		From: alert.From,
-		To:   alert.To,
-		Date: alert.Date,
-	}
-
-	trains, err := s.FindAvailableTrains(ctx, params)
+	trips, err := s.CheckAlertAvailability(ctx, alert)
 	if err != nil {
 		return nil, err
 	}
 
-	var matchingTrains []Train
+	return append(trips.Forward, trips.Return...), nil
+}
+
+// CheckAlertAvailability returns the trains matching the alert criteria for each
+// leg the alert watches. Legs whose travel date has already passed are skipped.
+func (s *Service) CheckAlertAvailability(ctx context.Context, alert TicketAlert) (*RoundTripTrains, error) {
+	today := Today()
+	watchForward := alert.WatchesForward() && !alert.Date.Before(today)
+	watchReturn := alert.WatchesReturn() && !alert.ReturnDate.Before(today)
+
+	trips := &RoundTripTrains{}
+
+	switch {
+	case watchForward && watchReturn:
+		found, err := s.FindAvailableRoundTrip(ctx, TrainSearchParams{
+			From:       alert.From,
+			To:         alert.To,
+			Date:       alert.Date,
+			ReturnDate: alert.ReturnDate,
+			Language:   alert.Language,
+		})
+		if err != nil {
+			return nil, err
+		}
+		trips = found
+
+	case watchForward:
+		trains, err := s.FindAvailableTrains(ctx, TrainSearchParams{
+			From:     alert.From,
+			To:       alert.To,
+			Date:     alert.Date,
+			Language: alert.Language,
+		})
+		if err != nil {
+			return nil, err
+		}
+		trips.Forward = trains
+
+	case watchReturn:
+		// The way back alone is a one-way search in the opposite direction
+		trains, err := s.FindAvailableTrains(ctx, TrainSearchParams{
+			From:     alert.To,
+			To:       alert.From,
+			Date:     alert.ReturnDate,
+			Language: alert.Language,
+		})
+		if err != nil {
+			return nil, err
+		}
+		trips.Return = trains
+	}
+
+	trips.Forward = s.matchingTrains(trips.Forward, alert)
+	trips.Return = s.matchingTrains(trips.Return, alert)
+
+	return trips, nil
+}
+
+// matchingTrains returns the trains that match the alert criteria
+func (s *Service) matchingTrains(trains []Train, alert TicketAlert) []Train {
+	var matching []Train
 	for _, train := range trains {
 		if s.matchesAlertCriteria(train, alert) {
-			matchingTrains = append(matchingTrains, train)
+			matching = append(matching, train)
 		}
 	}
-
-	return matchingTrains, nil
+	return matching
 }
 
-// FormatTrainInfo formats train information for display
-func (s *Service) FormatTrainInfo(train Train) string {
+// FormatTrainInfo formats train information for display in the given locale
+func (s *Service) FormatTrainInfo(train Train, locale string) string {
+	l := i18n.New(locale)
+
 	var builder strings.Builder
 
 	builder.WriteString(fmt.Sprintf("🚂 *%s* (%s)\n", train.Brand, train.Number))
 	builder.WriteString(fmt.Sprintf("📍 %s → %s\n", train.SubRoute.DepStationName, train.SubRoute.ArvStationName))
 	builder.WriteString(fmt.Sprintf("🕐 %s - %s (%s)\n", train.GetDepartureTime(), train.GetArrivalTime(), train.TimeOnWay))
 	builder.WriteString(fmt.Sprintf("📅 %s\n", train.GetDate()))
-	builder.WriteString(fmt.Sprintf("🚄 Route: %s → %s\n", train.OriginRoute.DepStationName, train.OriginRoute.ArvStationName))
+	builder.WriteString(l.T("train.route", train.OriginRoute.DepStationName, train.OriginRoute.ArvStationName))
 
 	if len(train.Cars) > 0 {
-		builder.WriteString("\n💺 *Seat types and prices:*\n")
+		builder.WriteString(l.T("train.seats_header"))
 		for _, car := range train.Cars {
 			// Show car type with total seats and price
 			if len(car.Tariffs) > 0 {
 				// Use the first tariff price as representative for this car type
 				price := s.formatPrice(car.Tariffs[0].Tariff)
-				builder.WriteString(fmt.Sprintf("*%s* (%d total seats): %s UZS\n",
-					car.Type, car.FreeSeats, price))
+				builder.WriteString(l.N("train.car_seats", car.FreeSeats, car.Type, car.FreeSeats, price))
 			}
 		}
 	}
@@ -147,17 +399,19 @@ func (s *Service) FormatTrainInfo(train Train) string {
 	return builder.String()
 }
 
-// FormatSearchResults formats multiple trains for display
-func (s *Service) FormatSearchResults(trains []Train) string {
+// FormatSearchResults formats multiple trains for display in the given locale
+func (s *Service) FormatSearchResults(trains []Train, locale string) string {
+	l := i18n.New(locale)
+
 	if len(trains) == 0 {
-		return "❌ No trains found for your search criteria."
+		return l.T("train.none_found")
 	}
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("🚂 *Found %d train(s):*\n\n", len(trains)))
+	builder.WriteString(l.N("train.found", len(trains), len(trains)))
 
 	for i, train := range trains {
-		builder.WriteString(s.FormatTrainInfo(train))
+		builder.WriteString(s.FormatTrainInfo(train, locale))
 		if i < len(trains)-1 {
 			builder.WriteString("\n" + strings.Repeat("─", 30) + "\n\n")
 		}
@@ -166,92 +420,184 @@ func (s *Service) FormatSearchResults(trains []Train) string {
 	return builder.String()
 }
 
-// GetStationCode returns the station code for a given station name or code
+// FormatRoundTripResults formats both legs of a round trip for display in the given
+// locale: an overview with the legs side by side followed by the details of each leg
+func (s *Service) FormatRoundTripResults(trips *RoundTripTrains, locale string) string {
+	l := i18n.New(locale)
+
+	forward := s.roundTripColumn(trips.Forward)
+	back := s.roundTripColumn(trips.Return)
+	rows := len(forward)
+	if len(back) > rows {
+		rows = len(back)
+	}
+
+	var builder strings.Builder
+
+	// Monospace block so the columns stay aligned
+	builder.WriteString("```\n")
+	builder.WriteString(padRight(l.T("train.outbound_column"), roundTripColumnWidth) + " │ " + l.T("train.return_column") + "\n")
+	for i := 0; i < rows; i++ {
+		left, right := "—", "—"
+		if i < len(forward) {
+			left = forward[i]
+		}
+		if i < len(back) {
+			right = back[i]
+		}
+		builder.WriteString(padRight(left, roundTripColumnWidth) + " │ " + right + "\n")
+	}
+	builder.WriteString("```\n\n")
+
+	builder.WriteString(l.T("train.outbound_header"))
+	builder.WriteString(s.FormatSearchResults(trips.Forward, locale))
+	builder.WriteString("\n\n" + strings.Repeat("═", 30) + "\n\n")
+	builder.WriteString(l.T("train.return_header"))
+	builder.WriteString(s.FormatSearchResults(trips.Return, locale))
+
+	return builder.String()
+}
+
+// roundTripColumnWidth is the width of one leg in the round trip overview
+const roundTripColumnWidth = 18
+
+// roundTripColumn summarizes each train as departure time, number and cheapest price
+func (s *Service) roundTripColumn(trains []Train) []string {
+	column := make([]string, 0, len(trains))
+	for _, train := range trains {
+		column = append(column, fmt.Sprintf("%s %s %s",
+			train.GetDepartureTime(), train.Number, s.formatPrice(train.GetMinPrice())))
+	}
+	return column
+}
+
+// padRight pads text with spaces to the given width in runes
+func padRight(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// GetStationCode returns the station code for a given station name or code.
+// Input that does not resolve to a single station is returned as is.
 func (s *Service) GetStationCode(stationNameOrCode string) string {
-	// Real station codes from Uzbekistan railways
-	stationCodes := map[string]string{
-		"andijon":   "2900680",
-		"andijan":   "2900680", // Alternative spelling
-		"buxoro":    "2900800",
-		"bukhara":   "2900800", // Alternative spelling
-		"guliston":  "2900850",
-		"jizzax":    "2900720",
-		"jizzakh":   "2900720", // Alternative spelling
-		"margilon":  "2900920",
-		"margilan":  "2900920", // Alternative spelling
-		"namangan":  "2900940",
-		"navoiy":    "2900930",
-		"nukus":     "2900970",
-		"pop":       "2900693",
-		"qarshi":    "2900750",
-		"qoqon":     "2900880",
-		"kokand":    "2900880", // Alternative spelling
-		"samarqand": "2900700",
-		"samarkand": "2900700", // Alternative spelling
-		"termiz":    "2900255",
-		"termez":    "2900255", // Alternative spelling
-		"toshkent":  "2900000",
-		"tashkent":  "2900000", // Alternative spelling
-		"urgench":   "2900790",
-		"xiva":      "2900172",
-		"khiva":     "2900172", // Alternative spelling
-	}
-
-	// If it's already a code (starts with numbers), return as is
-	if len(stationNameOrCode) > 0 && stationNameOrCode[0] >= '0' && stationNameOrCode[0] <= '9' {
+	code, err := s.ResolveStationCode(stationNameOrCode)
+	if err != nil {
 		return stationNameOrCode
 	}
+	return code
+}
 
-	// Try to find by name (case-insensitive)
-	lowerName := strings.ToLower(strings.TrimSpace(stationNameOrCode))
-	if code, exists := stationCodes[lowerName]; exists {
-		return code
+// ResolveStationCode returns the code of the station meant by a name or code.
+// Numeric codes missing from the catalog are passed through, since the API
+// may know stations we don't. Other unknown or ambiguous input returns a
+// *StationError.
+func (s *Service) ResolveStationCode(stationNameOrCode string) (string, error) {
+	station, err := s.catalog.Resolve(stationNameOrCode)
+	if err == nil {
+		return station.Code, nil
 	}
 
-	// If not found, return as is (might be a valid code we don't know about)
-	return stationNameOrCode
+	if code := strings.TrimSpace(stationNameOrCode); isStationCode(code) {
+		return code, nil
+	}
+	return "", err
 }
 
-// matchesAlertCriteria checks if a train matches the alert criteria
-func (s *Service) matchesAlertCriteria(train Train, alert TicketAlert) bool {
+// isStationCode reports whether s looks like a numeric station code
+func isStationCode(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildNotification creates a notification payload for a train matching the alert
+// on the given leg, listing only the seat classes that satisfy the alert criteria
+func (s *Service) BuildNotification(alert TicketAlert, train Train, direction string) NotificationPayload {
+	var seats []SeatClass
 	for _, car := range train.Cars {
 		for _, tariff := range car.Tariffs {
-			if tariff.FreeSeats == 0 {
+			if !s.tariffMatchesAlert(car, tariff, alert) {
 				continue
 			}
+			seats = append(seats, SeatClass{
+				Type:        tariff.ClassServiceType,
+				Name:        car.Type,
+				Price:       float64(tariff.Tariff),
+				Currency:    "UZS",
+				Available:   tariff.FreeSeats,
+				Total:       car.FreeSeats,
+				IsAvailable: true,
+			})
+		}
+	}
 
-			// Check seat type preference
-			if len(alert.SeatTypes) > 0 {
-				found := false
-				for _, preferredType := range alert.SeatTypes {
-					if strings.EqualFold(car.Type, preferredType) ||
-						strings.EqualFold(tariff.ClassServiceType, preferredType) {
-						found = true
-						break
-					}
-				}
-				if !found {
-					continue
-				}
-			}
+	return NotificationPayload{
+		Alert:     alert,
+		Train:     train,
+		Seats:     seats,
+		Direction: direction,
+	}
+}
 
-			// Check price range (convert float64 to int for comparison)
-			price := float64(tariff.Tariff)
-			if alert.MinPrice > 0 && price < alert.MinPrice {
-				continue
-			}
-			if alert.MaxPrice > 0 && price > alert.MaxPrice {
-				continue
+// matchesAlertCriteria checks if a train matches the alert criteria
+func (s *Service) matchesAlertCriteria(train Train, alert TicketAlert) bool {
+	for _, car := range train.Cars {
+		for _, tariff := range car.Tariffs {
+			if s.tariffMatchesAlert(car, tariff, alert) {
+				return true
 			}
-
-			// If we reach here, this tariff matches the criteria
-			return true
 		}
 	}
 
 	return false
 }
 
+// tariffMatchesAlert checks if a single tariff has free seats within the alert criteria
+func (s *Service) tariffMatchesAlert(car Car, tariff Tariff, alert TicketAlert) bool {
+	if tariff.FreeSeats == 0 {
+		return false
+	}
+
+	// Check seat type preference
+	if len(alert.SeatTypes) > 0 {
+		found := false
+		for _, preferredType := range alert.SeatTypes {
+			if strings.EqualFold(car.Type, preferredType) ||
+				strings.EqualFold(tariff.ClassServiceType, preferredType) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Check price range (convert float64 to int for comparison)
+	price := float64(tariff.Tariff)
+	if alert.MinPrice > 0 && price < alert.MinPrice {
+		return false
+	}
+	if alert.MaxPrice > 0 && price > alert.MaxPrice {
+		return false
+	}
+
+	return true
+}
+
+// FormatPrice formats price with thousands separator
+func (s *Service) FormatPrice(price int) string {
+	return s.formatPrice(price)
+}
+
 // formatPrice formats price with thousands separator
 func (s *Service) formatPrice(price int) string {
 	priceStr := fmt.Sprintf("%d", price)
@@ -272,22 +618,19 @@ func (s *Service) formatPrice(price int) string {
 
 // GetStationSuggestions returns station name suggestions for autocomplete
 func (s *Service) GetStationSuggestions(query string) []string {
-	stations := []string{
-		"Andijon", "Buxoro", "Guliston", "Jizzax", "Margilon",
-		"Namangan", "Navoiy", "Nukus", "Pop", "Qarshi",
-		"Qoqon", "Samarqand", "Termiz", "Toshkent", "Urgench", "Xiva",
-	}
-
-	if query == "" {
-		return stations
-	}
-
 	var suggestions []string
-	lowerQuery := strings.ToLower(query)
+	lowerQuery := strings.ToLower(strings.TrimSpace(query))
+
+	for _, station := range s.catalog.All() {
+		if !station.IsActive {
+			continue
+		}
 
-	for _, station := range stations {
-		if strings.Contains(strings.ToLower(station), lowerQuery) {
-			suggestions = append(suggestions, station)
+		for _, name := range station.Names() {
+			if name != "" && strings.Contains(strings.ToLower(name), lowerQuery) {
+				suggestions = append(suggestions, station.Name)
+				break
+			}
 		}
 	}
 
@@ -0,0 +1,189 @@
+package train_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train/railwaytest"
+)
+
+// travelDate is the date the sample trains run on
+var travelDate = time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+
+func TestServiceFindsAvailableTrainsByStationName(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetTrains("2900000", "2900700", "2025-09-02",
+		sampleTrain("778Ф", "06:03", 77),
+		sampleTrain("710Ф", "08:00", 0),
+	)
+
+	service := fake.NewService()
+	trains, err := service.FindAvailableTrains(context.Background(), train.TrainSearchParams{
+		From: "Toshkent",
+		To:   "Самарканд",
+		Date: travelDate,
+	})
+	if err != nil {
+		t.Fatalf("FindAvailableTrains: %v", err)
+	}
+
+	if len(trains) != 1 || trains[0].Number != "778Ф" {
+		t.Errorf("got trains %+v, want only 778Ф", trains)
+	}
+
+	searches := fake.Searches()
+	if len(searches) != 1 {
+		t.Fatalf("got %d searches, want 1", len(searches))
+	}
+	if forward := searches[0].Directions.Forward; forward.DepStationCode != "2900000" || forward.ArvStationCode != "2900700" {
+		t.Errorf("searched %s -> %s, want 2900000 -> 2900700", forward.DepStationCode, forward.ArvStationCode)
+	}
+}
+
+func TestServiceRejectsUnknownStationWithoutRequest(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+
+	service := fake.NewService()
+	_, err := service.SearchTrains(context.Background(), train.TrainSearchParams{
+		From: "Atlantis",
+		To:   "Samarqand",
+		Date: travelDate,
+	})
+
+	var stationErr *train.StationError
+	if !errors.As(err, &stationErr) || stationErr.Query != "Atlantis" {
+		t.Fatalf("got %v, want a StationError for Atlantis", err)
+	}
+	if fake.SearchRequests() != 0 || fake.CSRFRequests() != 0 {
+		t.Errorf("unknown station reached the API")
+	}
+}
+
+func TestServiceSearchesRoundTripInOneRequest(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetTrains("2900000", "2900700", "2025-09-02", sampleTrain("778Ф", "06:03", 10))
+	fake.SetTrains("2900700", "2900000", "2025-09-05", sampleTrain("767Ф", "17:00", 4))
+
+	service := fake.NewService()
+	trips, err := service.FindAvailableRoundTrip(context.Background(), train.TrainSearchParams{
+		From:       "Toshkent",
+		To:         "Samarqand",
+		Date:       travelDate,
+		ReturnDate: travelDate.AddDate(0, 0, 3),
+	})
+	if err != nil {
+		t.Fatalf("FindAvailableRoundTrip: %v", err)
+	}
+
+	if len(trips.Forward) != 1 || len(trips.Return) != 1 {
+		t.Errorf("got %d forward and %d return trains, want 1 each", len(trips.Forward), len(trips.Return))
+	}
+	if got := fake.SearchRequests(); got != 1 {
+		t.Errorf("got %d search requests, want 1", got)
+	}
+}
+
+func TestServiceSharesIdenticalSearches(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.QueueSearchReplies(railwaytest.Reply{Delay: 50 * time.Millisecond})
+
+	service := fake.NewService()
+	params := train.TrainSearchParams{From: "Toshkent", To: "Samarqand", Date: travelDate}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.SearchTrains(context.Background(), params); err != nil {
+				t.Errorf("concurrent search: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Cached within the TTL
+	if _, err := service.SearchTrains(context.Background(), params); err != nil {
+		t.Fatalf("cached search: %v", err)
+	}
+	if got := fake.SearchRequests(); got != 1 {
+		t.Errorf("got %d search requests, want 1", got)
+	}
+
+	// Another language is another response
+	params.Language = train.LanguageRussian
+	if _, err := service.SearchTrains(context.Background(), params); err != nil {
+		t.Fatalf("search in Russian: %v", err)
+	}
+	if got := fake.SearchRequests(); got != 2 {
+		t.Errorf("got %d search requests, want 2", got)
+	}
+}
+
+func TestServiceKeepsLastResultsWhileDown(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetTrains("2900000", "2900700", "2025-09-02", sampleTrain("778Ф", "06:03", 10))
+
+	service := fake.NewService()
+	service.SetSearchCacheTTL(0)
+	service.SetRetryPolicy(train.RetryPolicy{MaxAttempts: 1})
+	service.SetBreakerPolicy(train.BreakerPolicy{Threshold: 1, Cooldown: time.Hour})
+	params := train.TrainSearchParams{From: "Toshkent", To: "Samarqand", Date: travelDate}
+
+	if _, err := service.FindAvailableTrains(context.Background(), params); err != nil {
+		t.Fatalf("first search: %v", err)
+	}
+
+	fake.QueueSearchReplies(railwaytest.Reply{Status: 502})
+	if _, err := service.FindAvailableTrains(context.Background(), params); err == nil {
+		t.Fatalf("search succeeded, want a server error")
+	}
+
+	_, err := service.FindAvailableTrains(context.Background(), params)
+	var downErr *train.UnavailableError
+	if !errors.As(err, &downErr) {
+		t.Fatalf("got %v, want an UnavailableError", err)
+	}
+
+	trains, fetchedAt, ok := service.LastAvailableTrains(params)
+	if !ok || len(trains) != 1 || trains[0].Number != "778Ф" {
+		t.Fatalf("got %+v (ok %v), want the last results", trains, ok)
+	}
+	if age := time.Since(fetchedAt); age < 0 || age > time.Minute {
+		t.Errorf("results fetched %v ago", age)
+	}
+}
+
+func TestServicesKeepTheirOwnStations(t *testing.T) {
+	fake := railwaytest.NewServer()
+	defer fake.Close()
+	fake.SetStations(train.LanguageUzbek,
+		train.Station{Code: "2900000", Name: "Toshkent"},
+		train.Station{Code: "2900111", Name: "Yangi bekat"},
+	)
+
+	synced := fake.NewService()
+	other := fake.NewService()
+	if err := synced.RefreshStations(context.Background()); err != nil {
+		t.Fatalf("RefreshStations: %v", err)
+	}
+
+	if station := synced.Stations().ByCode("2900111"); station == nil || station.Name != "Yangi bekat" {
+		t.Errorf("synced service: station 2900111 = %+v, want Yangi bekat", station)
+	}
+	if station := other.Stations().ByCode("2900111"); station != nil {
+		t.Errorf("another service sees the synced station %+v", station)
+	}
+	if code, err := synced.ResolveStationCode("Yangi bekat"); err != nil || code != "2900111" {
+		t.Errorf("ResolveStationCode(Yangi bekat) = %q, %v, want 2900111", code, err)
+	}
+}
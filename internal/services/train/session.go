@@ -0,0 +1,229 @@
+package train
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/fsutil"
+)
+
+// xsrfCookie is the cookie whose value is sent back as the X-XSRF-TOKEN header
+const xsrfCookie = "XSRF-TOKEN"
+
+// sessionRefreshMargin is how long before the XSRF cookie expires the session
+// is refreshed, so requests never go out with a token about to expire
+const sessionRefreshMargin = 5 * time.Minute
+
+// Session is a railway.uz cookie session. It implements http.CookieJar, so
+// every Set-Cookie of every response is kept, and remembers the cookies it
+// has seen so the session can be written to disk and restored after a
+// restart. It is safe for concurrent use.
+type Session struct {
+	jar  *cookiejar.Jar
+	path string // Where the session is persisted, empty to keep it in memory
+
+	mu      sync.Mutex
+	cookies map[string]savedCookie // Live cookies by host, path and name
+	dirty   bool                   // Cookies changed since the last Save
+
+	saveMu sync.Mutex // Serializes writes of the session file
+}
+
+// savedCookie is a cookie with the URL it was set for, as persisted on disk
+type savedCookie struct {
+	URL     string       `json:"url"`
+	Cookie  *http.Cookie `json:"cookie"`
+	Expires time.Time    `json:"expires,omitempty"` // Zero for cookies that last for the session
+}
+
+// NewSession creates an empty session kept in memory
+func NewSession() *Session {
+	// cookiejar.New only fails for a broken PublicSuffixList; none is used here
+	jar, _ := cookiejar.New(nil)
+	return &Session{
+		jar:     jar,
+		cookies: make(map[string]savedCookie),
+	}
+}
+
+// OpenSession creates a session persisted at path, restoring the cookies
+// saved there that have not expired
+func OpenSession(path string) (*Session, error) {
+	s := NewSession()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var saved []savedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to decode session file %s: %w", path, err)
+	}
+
+	now := time.Now()
+	for _, entry := range saved {
+		if entry.Cookie == nil || (!entry.Expires.IsZero() && entry.Expires.Before(now)) {
+			continue
+		}
+		u, err := url.Parse(entry.URL)
+		if err != nil {
+			continue
+		}
+
+		// The jar works out expiry from MaxAge, which is relative to when the
+		// cookie was set, so restore with the absolute expiry instead
+		cookie := *entry.Cookie
+		cookie.MaxAge = 0
+		cookie.Expires = entry.Expires
+		s.SetCookies(u, []*http.Cookie{&cookie})
+	}
+
+	s.mu.Lock()
+	s.dirty = false
+	s.mu.Unlock()
+
+	return s, nil
+}
+
+// SetCookies implements http.CookieJar
+func (s *Session) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	s.jar.SetCookies(u, cookies)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, cookie := range cookies {
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		key := u.Host + path + "|" + cookie.Name
+
+		var expires time.Time
+		switch {
+		case cookie.MaxAge < 0:
+			delete(s.cookies, key)
+			s.dirty = true
+			continue
+		case cookie.MaxAge > 0:
+			expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		case !cookie.Expires.IsZero():
+			if cookie.Expires.Before(now) {
+				delete(s.cookies, key)
+				s.dirty = true
+				continue
+			}
+			expires = cookie.Expires
+		}
+
+		saved := *cookie
+		s.cookies[key] = savedCookie{
+			URL:     (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: path}).String(),
+			Cookie:  &saved,
+			Expires: expires,
+		}
+		s.dirty = true
+	}
+}
+
+// Cookies implements http.CookieJar
+func (s *Session) Cookies(u *url.URL) []*http.Cookie {
+	return s.jar.Cookies(u)
+}
+
+// SetCookieHeader adds the cookies of a raw Cookie header, e.g. credentials
+// copied from a browser, for the given URL
+func (s *Session) SetCookieHeader(rawURL, header string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid session URL: %w", err)
+	}
+
+	request := http.Request{Header: http.Header{"Cookie": {header}}}
+	cookies := request.Cookies()
+	for _, cookie := range cookies {
+		cookie.Path = "/"
+	}
+	s.SetCookies(u, cookies)
+	return nil
+}
+
+// XSRFToken returns the XSRF token the server set for the URL, if any
+func (s *Session) XSRFToken(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	for _, cookie := range s.jar.Cookies(u) {
+		if cookie.Name == xsrfCookie {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// NeedsRefresh reports whether the session has no XSRF token for the URL or
+// its token expires within sessionRefreshMargin
+func (s *Session) NeedsRefresh(rawURL string) bool {
+	if s.XSRFToken(rawURL) == "" {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.cookies {
+		if entry.Cookie.Name == xsrfCookie && !entry.Expires.IsZero() &&
+			time.Until(entry.Expires) < sessionRefreshMargin {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the session to its file if cookies changed since the last save.
+// Sessions kept in memory are not saved.
+func (s *Session) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	saved := make([]savedCookie, 0, len(s.cookies))
+	for _, entry := range s.cookies {
+		saved = append(saved, entry)
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package train
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const sessionURL = "https://eticket.railway.uz/api/v1"
+
+func TestSessionXSRFToken(t *testing.T) {
+	u, _ := url.Parse(sessionURL)
+
+	tests := []struct {
+		name        string
+		cookies     []*http.Cookie
+		wantToken   string
+		wantRefresh bool
+	}{
+		{"empty", nil, "", true},
+		{"session cookie", []*http.Cookie{{Name: xsrfCookie, Value: "abc", Path: "/"}}, "abc", false},
+		{"fresh token", []*http.Cookie{{Name: xsrfCookie, Value: "abc", Path: "/", MaxAge: 3600}}, "abc", false},
+		{"about to expire", []*http.Cookie{{Name: xsrfCookie, Value: "abc", Path: "/", MaxAge: 60}}, "abc", true},
+		{"expired", []*http.Cookie{{Name: xsrfCookie, Value: "abc", Path: "/", Expires: time.Now().Add(-time.Hour)}}, "", true},
+		{"deleted", []*http.Cookie{
+			{Name: xsrfCookie, Value: "abc", Path: "/"},
+			{Name: xsrfCookie, Value: "", Path: "/", MaxAge: -1},
+		}, "", true},
+		{"other cookies only", []*http.Cookie{{Name: "laravel_session", Value: "s", Path: "/"}}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSession()
+			for _, cookie := range tt.cookies {
+				session.SetCookies(u, []*http.Cookie{cookie})
+			}
+			if got := session.XSRFToken(sessionURL); got != tt.wantToken {
+				t.Errorf("XSRFToken() = %q, want %q", got, tt.wantToken)
+			}
+			if got := session.NeedsRefresh(sessionURL); got != tt.wantRefresh {
+				t.Errorf("NeedsRefresh() = %v, want %v", got, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func TestSessionCookieHeader(t *testing.T) {
+	session := NewSession()
+	if err := session.SetCookieHeader(sessionURL, "XSRF-TOKEN=abc; laravel_session=s1"); err != nil {
+		t.Fatalf("SetCookieHeader: %v", err)
+	}
+
+	if got := session.XSRFToken(sessionURL); got != "abc" {
+		t.Errorf("XSRFToken() = %q, want abc", got)
+	}
+	u, _ := url.Parse("https://eticket.railway.uz/other")
+	if got := len(session.Cookies(u)); got != 2 {
+		t.Errorf("got %d cookies on another path, want 2", got)
+	}
+}
+
+func TestSessionSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	u, _ := url.Parse(sessionURL)
+
+	session, err := OpenSession(path)
+	if err != nil {
+		t.Fatalf("OpenSession: %v", err)
+	}
+	session.SetCookies(u, []*http.Cookie{
+		{Name: xsrfCookie, Value: "abc", Path: "/", MaxAge: 3600},
+		{Name: "laravel_session", Value: "s1", Path: "/"},
+		{Name: "old", Value: "gone", Path: "/", MaxAge: 1},
+	})
+	session.SetCookies(u, []*http.Cookie{{Name: "old", Path: "/", MaxAge: -1}})
+	if err := session.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	restored, err := OpenSession(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := restored.XSRFToken(sessionURL); got != "abc" {
+		t.Errorf("restored XSRFToken() = %q, want abc", got)
+	}
+	if restored.NeedsRefresh(sessionURL) {
+		t.Error("restored token needs a refresh, want it to keep its expiry")
+	}
+	names := make(map[string]bool)
+	for _, cookie := range restored.Cookies(u) {
+		names[cookie.Name] = true
+	}
+	if !names["laravel_session"] || names["old"] {
+		t.Errorf("restored cookies %v, want laravel_session without the deleted one", names)
+	}
+
+	// Nothing changed, so nothing is written
+	if restored.dirty {
+		t.Error("restored session is marked as changed")
+	}
+}
@@ -1,18 +1,26 @@
 package train
 
-import "strings"
+import (
+	"math"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/i18n"
+)
 
 // Station represents a railway station with its details
 type StationInfo struct {
-	Code        string   `json:"code"`
-	Name        string   `json:"name"`
-	NameUz      string   `json:"nameUz"`      // Uzbek name
-	NameEn      string   `json:"nameEn"`      // English name
-	Aliases     []string `json:"aliases"`     // Alternative spellings
-	Region      string   `json:"region"`      // Region/Province
-	IsActive    bool     `json:"isActive"`    // Whether station is active
-	IsMajor     bool     `json:"isMajor"`     // Major station flag
-	Coordinates *LatLng  `json:"coordinates"` // GPS coordinates (optional)
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	NameUz       string   `json:"nameUz"`                 // Uzbek name
+	NameEn       string   `json:"nameEn"`                 // English name
+	NameRu       string   `json:"nameRu"`                 // Russian name
+	NameUzCyrl   string   `json:"nameUzCyrl"`             // Uzbek name in Cyrillic script
+	Aliases      []string `json:"aliases"`                // Alternative spellings
+	Region       string   `json:"region"`                 // Region/Province
+	IsActive     bool     `json:"isActive"`               // Whether station is active
+	IsMajor      bool     `json:"isMajor"`                // Major station flag
+	IsHub        bool     `json:"isHub"`                  // Junction where passengers change trains
+	KeyboardRank int      `json:"keyboardRank,omitempty"` // Position on station keyboards, 0 if not offered
+	Coordinates  *LatLng  `json:"coordinates"`            // GPS coordinates (optional)
 }
 
 // LatLng represents GPS coordinates
@@ -21,231 +29,274 @@ type LatLng struct {
 	Longitude float64 `json:"longitude"`
 }
 
-// GetAllStations returns all known railway stations in Uzbekistan
-func GetAllStations() []StationInfo {
-	return []StationInfo{
-		{
-			Code:     "2900680",
-			Name:     "Andijon",
-			NameUz:   "Andijon",
-			NameEn:   "Andijan",
-			Aliases:  []string{"andijan", "andizhan"},
-			Region:   "Andijon",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900800",
-			Name:     "Buxoro",
-			NameUz:   "Buxoro",
-			NameEn:   "Bukhara",
-			Aliases:  []string{"bukhara", "bokhara"},
-			Region:   "Buxoro",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900850",
-			Name:     "Guliston",
-			NameUz:   "Guliston",
-			NameEn:   "Gulistan",
-			Aliases:  []string{"gulistan"},
-			Region:   "Sirdaryo",
-			IsActive: true,
-			IsMajor:  false,
-		},
-		{
-			Code:     "2900720",
-			Name:     "Jizzax",
-			NameUz:   "Jizzax",
-			NameEn:   "Jizzakh",
-			Aliases:  []string{"jizzakh", "djizak"},
-			Region:   "Jizzax",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900920",
-			Name:     "Margilon",
-			NameUz:   "Margilon",
-			NameEn:   "Margilan",
-			Aliases:  []string{"margilan", "marghilan"},
-			Region:   "Farg'ona",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900940",
-			Name:     "Namangan",
-			NameUz:   "Namangan",
-			NameEn:   "Namangan",
-			Aliases:  []string{},
-			Region:   "Namangan",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900930",
-			Name:     "Navoiy",
-			NameUz:   "Navoiy",
-			NameEn:   "Navoi",
-			Aliases:  []string{"navoi", "navoiy"},
-			Region:   "Navoiy",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900970",
-			Name:     "Nukus",
-			NameUz:   "Nukus",
-			NameEn:   "Nukus",
-			Aliases:  []string{},
-			Region:   "Qoraqalpog'iston",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900693",
-			Name:     "Pop",
-			NameUz:   "Pop",
-			NameEn:   "Pop",
-			Aliases:  []string{},
-			Region:   "Namangan",
-			IsActive: true,
-			IsMajor:  false,
-		},
-		{
-			Code:     "2900750",
-			Name:     "Qarshi",
-			NameUz:   "Qarshi",
-			NameEn:   "Karshi",
-			Aliases:  []string{"karshi", "qarshi"},
-			Region:   "Qashqadaryo",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900880",
-			Name:     "Qo'qon",
-			NameUz:   "Qo'qon",
-			NameEn:   "Kokand",
-			Aliases:  []string{"kokand", "qoqon", "kokhand"},
-			Region:   "Farg'ona",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900700",
-			Name:     "Samarqand",
-			NameUz:   "Samarqand",
-			NameEn:   "Samarkand",
-			Aliases:  []string{"samarkand", "samarqand"},
-			Region:   "Samarqand",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900255",
-			Name:     "Termiz",
-			NameUz:   "Termiz",
-			NameEn:   "Termez",
-			Aliases:  []string{"termez", "termiz"},
-			Region:   "Surxondaryo",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900000",
-			Name:     "Toshkent",
-			NameUz:   "Toshkent",
-			NameEn:   "Tashkent",
-			Aliases:  []string{"tashkent", "toshkent"},
-			Region:   "Toshkent",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900790",
-			Name:     "Urgench",
-			NameUz:   "Urganch",
-			NameEn:   "Urgench",
-			Aliases:  []string{"urganch", "urgench"},
-			Region:   "Xorazm",
-			IsActive: true,
-			IsMajor:  true,
-		},
-		{
-			Code:     "2900172",
-			Name:     "Xiva",
-			NameUz:   "Xiva",
-			NameEn:   "Khiva",
-			Aliases:  []string{"khiva", "xiva"},
-			Region:   "Xorazm",
-			IsActive: true,
-			IsMajor:  true,
-		},
-	}
-}
-
-// GetStationByCode returns station information by code
-func GetStationByCode(code string) *StationInfo {
-	stations := GetAllStations()
-	for _, station := range stations {
-		if station.Code == code {
-			return &station
-		}
-	}
-	return nil
-}
-
-// GetStationByName returns station information by name (case-insensitive)
-func GetStationByName(name string) *StationInfo {
-	stations := GetAllStations()
-	lowerName := strings.ToLower(name)
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
 
-	for _, station := range stations {
-		// Check main names
-		if strings.ToLower(station.Name) == lowerName ||
-			strings.ToLower(station.NameUz) == lowerName ||
-			strings.ToLower(station.NameEn) == lowerName {
-			return &station
-		}
+// DistanceTo returns the great-circle distance to another point in kilometers
+func (p LatLng) DistanceTo(q LatLng) float64 {
+	lat1, lat2 := p.Latitude*math.Pi/180, q.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (q.Longitude - p.Longitude) * math.Pi / 180
 
-		// Check aliases
-		for _, alias := range station.Aliases {
-			if strings.ToLower(alias) == lowerName {
-				return &station
-			}
-		}
-	}
-	return nil
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
 }
 
-// GetMajorStations returns only major railway stations
-func GetMajorStations() []StationInfo {
-	stations := GetAllStations()
-	var majorStations []StationInfo
-
-	for _, station := range stations {
-		if station.IsMajor {
-			majorStations = append(majorStations, station)
-		}
-	}
-
-	return majorStations
+// builtinStations seed the station catalog until the railway handbook is loaded.
+// They carry the curated names, aliases and flags that the handbook lacks.
+var builtinStations = []StationInfo{
+	{
+		Code:         "2900680",
+		Name:         "Andijon",
+		NameUz:       "Andijon",
+		NameEn:       "Andijan",
+		NameRu:       "Андижан",
+		NameUzCyrl:   "Андижон",
+		Aliases:      []string{"andijan", "andizhan"},
+		Region:       "Andijon",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 4,
+		Coordinates:  &LatLng{Latitude: 40.7561, Longitude: 72.3539},
+	},
+	{
+		Code:         "2900800",
+		Name:         "Buxoro",
+		NameUz:       "Buxoro",
+		NameEn:       "Bukhara",
+		NameRu:       "Бухара",
+		NameUzCyrl:   "Бухоро",
+		Aliases:      []string{"bukhara", "bokhara"},
+		Region:       "Buxoro",
+		IsActive:     true,
+		IsMajor:      true,
+		IsHub:        true,
+		KeyboardRank: 3,
+		Coordinates:  &LatLng{Latitude: 39.7219, Longitude: 64.5513},
+	},
+	{
+		Code:         "2900850",
+		Name:         "Guliston",
+		NameUz:       "Guliston",
+		NameEn:       "Gulistan",
+		NameRu:       "Гулистан",
+		NameUzCyrl:   "Гулистон",
+		Aliases:      []string{"gulistan"},
+		Region:       "Sirdaryo",
+		IsActive:     true,
+		IsMajor:      false,
+		KeyboardRank: 14,
+		Coordinates:  &LatLng{Latitude: 40.4897, Longitude: 68.7842},
+	},
+	{
+		Code:         "2900720",
+		Name:         "Jizzax",
+		NameUz:       "Jizzax",
+		NameEn:       "Jizzakh",
+		NameRu:       "Джизак",
+		NameUzCyrl:   "Жиззах",
+		Aliases:      []string{"jizzakh", "djizak"},
+		Region:       "Jizzax",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 9,
+		Coordinates:  &LatLng{Latitude: 40.1158, Longitude: 67.8422},
+	},
+	{
+		Code:         "2900920",
+		Name:         "Margilon",
+		NameUz:       "Margilon",
+		NameEn:       "Margilan",
+		NameRu:       "Маргилан",
+		NameUzCyrl:   "Марғилон",
+		Aliases:      []string{"margilan", "marghilan"},
+		Region:       "Farg'ona",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 12,
+		Coordinates:  &LatLng{Latitude: 40.4711, Longitude: 71.7246},
+	},
+	{
+		Code:         "2900940",
+		Name:         "Namangan",
+		NameUz:       "Namangan",
+		NameEn:       "Namangan",
+		NameRu:       "Наманган",
+		NameUzCyrl:   "Наманган",
+		Aliases:      []string{},
+		Region:       "Namangan",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 11,
+		Coordinates:  &LatLng{Latitude: 40.9983, Longitude: 71.6726},
+	},
+	{
+		Code:         "2900930",
+		Name:         "Navoiy",
+		NameUz:       "Navoiy",
+		NameEn:       "Navoi",
+		NameRu:       "Навои",
+		NameUzCyrl:   "Навоий",
+		Aliases:      []string{"navoi", "navoiy"},
+		Region:       "Navoiy",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 10,
+		Coordinates:  &LatLng{Latitude: 40.1030, Longitude: 65.3688},
+	},
+	{
+		Code:         "2900970",
+		Name:         "Nukus",
+		NameUz:       "Nukus",
+		NameEn:       "Nukus",
+		NameRu:       "Нукус",
+		NameUzCyrl:   "Нукус",
+		Aliases:      []string{},
+		Region:       "Qoraqalpog'iston",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 7,
+		Coordinates:  &LatLng{Latitude: 42.4531, Longitude: 59.6103},
+	},
+	{
+		Code:         "2900693",
+		Name:         "Pop",
+		NameUz:       "Pop",
+		NameEn:       "Pop",
+		NameRu:       "Пап",
+		NameUzCyrl:   "Поп",
+		Aliases:      []string{},
+		Region:       "Namangan",
+		IsActive:     true,
+		IsMajor:      false,
+		KeyboardRank: 16,
+		Coordinates:  &LatLng{Latitude: 40.8736, Longitude: 71.1089},
+	},
+	{
+		Code:         "2900750",
+		Name:         "Qarshi",
+		NameUz:       "Qarshi",
+		NameEn:       "Karshi",
+		NameRu:       "Карши",
+		NameUzCyrl:   "Қарши",
+		Aliases:      []string{"karshi", "qarshi"},
+		Region:       "Qashqadaryo",
+		IsActive:     true,
+		IsMajor:      true,
+		IsHub:        true,
+		KeyboardRank: 5,
+		Coordinates:  &LatLng{Latitude: 38.8606, Longitude: 65.7847},
+	},
+	{
+		Code:         "2900880",
+		Name:         "Qo'qon",
+		NameUz:       "Qo'qon",
+		NameEn:       "Kokand",
+		NameRu:       "Коканд",
+		NameUzCyrl:   "Қўқон",
+		Aliases:      []string{"kokand", "qoqon", "kokhand"},
+		Region:       "Farg'ona",
+		IsActive:     true,
+		IsMajor:      true,
+		IsHub:        true,
+		KeyboardRank: 13,
+		Coordinates:  &LatLng{Latitude: 40.5286, Longitude: 70.9425},
+	},
+	{
+		Code:         "2900700",
+		Name:         "Samarqand",
+		NameUz:       "Samarqand",
+		NameEn:       "Samarkand",
+		NameRu:       "Самарканд",
+		NameUzCyrl:   "Самарқанд",
+		Aliases:      []string{"samarkand", "samarqand"},
+		Region:       "Samarqand",
+		IsActive:     true,
+		IsMajor:      true,
+		IsHub:        true,
+		KeyboardRank: 2,
+		Coordinates:  &LatLng{Latitude: 39.6775, Longitude: 66.9237},
+	},
+	{
+		Code:         "2900255",
+		Name:         "Termiz",
+		NameUz:       "Termiz",
+		NameEn:       "Termez",
+		NameRu:       "Термез",
+		NameUzCyrl:   "Термиз",
+		Aliases:      []string{"termez", "termiz"},
+		Region:       "Surxondaryo",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 6,
+		Coordinates:  &LatLng{Latitude: 37.2242, Longitude: 67.2783},
+	},
+	{
+		Code:         "2900000",
+		Name:         "Toshkent",
+		NameUz:       "Toshkent",
+		NameEn:       "Tashkent",
+		NameRu:       "Ташкент",
+		NameUzCyrl:   "Тошкент",
+		Aliases:      []string{"tashkent", "toshkent"},
+		Region:       "Toshkent",
+		IsActive:     true,
+		IsMajor:      true,
+		IsHub:        true,
+		KeyboardRank: 1,
+		Coordinates:  &LatLng{Latitude: 41.2920, Longitude: 69.2873},
+	},
+	{
+		Code:         "2900790",
+		Name:         "Urgench",
+		NameUz:       "Urganch",
+		NameEn:       "Urgench",
+		NameRu:       "Ургенч",
+		NameUzCyrl:   "Урганч",
+		Aliases:      []string{"urganch", "urgench"},
+		Region:       "Xorazm",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 15,
+		Coordinates:  &LatLng{Latitude: 41.5500, Longitude: 60.6333},
+	},
+	{
+		Code:         "2900172",
+		Name:         "Xiva",
+		NameUz:       "Xiva",
+		NameEn:       "Khiva",
+		NameRu:       "Хива",
+		NameUzCyrl:   "Хива",
+		Aliases:      []string{"khiva", "xiva"},
+		Region:       "Xorazm",
+		IsActive:     true,
+		IsMajor:      true,
+		KeyboardRank: 8,
+		Coordinates:  &LatLng{Latitude: 41.3783, Longitude: 60.3639},
+	},
 }
 
-// GetStationsByRegion returns stations in a specific region
-func GetStationsByRegion(region string) []StationInfo {
-	stations := GetAllStations()
-	var regionStations []StationInfo
+// LocalizedName returns the station name for a locale, falling back to the canonical name
+func (s StationInfo) LocalizedName(locale string) string {
+	var name string
+	switch locale {
+	case i18n.Uzbek:
+		name = s.NameUz
+	case i18n.UzbekCyrillic:
+		name = s.NameUzCyrl
+	case i18n.Russian:
+		name = s.NameRu
+	case i18n.English:
+		name = s.NameEn
+	}
 
-	lowerRegion := strings.ToLower(region)
-	for _, station := range stations {
-		if strings.ToLower(station.Region) == lowerRegion {
-			regionStations = append(regionStations, station)
-		}
+	if name == "" {
+		return s.Name
 	}
+	return name
+}
 
-	return regionStations
+// Names returns every name and alias of the station
+func (s StationInfo) Names() []string {
+	return append([]string{s.Name, s.NameUz, s.NameEn, s.NameRu, s.NameUzCyrl}, s.Aliases...)
 }
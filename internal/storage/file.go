@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/fsutil"
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// DefaultFilePath is used by the file backend when no path is configured
+const DefaultFilePath = "data/chiptatop.json"
+
+// DefaultFlushDelay is how long the file backend waits to write changes, so
+// that the changes made meanwhile are written together
+const DefaultFlushDelay = time.Second
+
+// FileStore keeps data in memory and persists it to a single JSON file, so
+// state survives process and container restarts. Changes are written
+// together at most once per flush delay instead of one write per change; a
+// crash loses the changes of the last delay at most. Close writes what is
+// pending.
+type FileStore struct {
+	*MemoryStore
+
+	path    string
+	writeMu sync.Mutex // Serializes writes so the file always reflects the latest change
+
+	mu     sync.Mutex // Guards the fields below
+	delay  time.Duration
+	timer  *time.Timer // Pending write, nil if none
+	closed bool        // Changes after Close are written at once
+}
+
+// fileSnapshot is the on-disk representation of the store
+type fileSnapshot struct {
+	UserStates  map[int64]*UserState         `json:"userStates"`
+	Alerts      map[string]train.TicketAlert `json:"alerts"`
+	Preferences map[int64]Preferences        `json:"preferences"`
+}
+
+// NewFileStore opens the store at path, loading any previously saved data
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		path = DefaultFilePath
+	}
+
+	f := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
+		delay:       DefaultFlushDelay,
+	}
+
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Storage loaded from %s (%d alerts, %d user states)", path, len(f.alerts), len(f.userStates))
+	return f, nil
+}
+
+// SaveUserState stores the conversation state and persists it
+func (f *FileStore) SaveUserState(chatID int64, state *UserState) error {
+	_ = f.MemoryStore.SaveUserState(chatID, state)
+	return f.scheduleFlush()
+}
+
+// DeleteUserState removes the conversation state and persists the change
+func (f *FileStore) DeleteUserState(chatID int64) error {
+	_ = f.MemoryStore.DeleteUserState(chatID)
+	return f.scheduleFlush()
+}
+
+// SaveAlert creates or replaces an alert and persists it
+func (f *FileStore) SaveAlert(alert train.TicketAlert) error {
+	_ = f.MemoryStore.SaveAlert(alert)
+	return f.scheduleFlush()
+}
+
+// DeleteAlert removes an alert and persists the change
+func (f *FileStore) DeleteAlert(id string) error {
+	if err := f.MemoryStore.DeleteAlert(id); err != nil {
+		return err
+	}
+	return f.scheduleFlush()
+}
+
+// SavePreferences stores the preferences and persists them
+func (f *FileStore) SavePreferences(chatID int64, prefs Preferences) error {
+	_ = f.MemoryStore.SavePreferences(chatID, prefs)
+	return f.scheduleFlush()
+}
+
+// SetFlushDelay changes how long changes wait to be written together. Zero
+// or less writes every change at once.
+func (f *FileStore) SetFlushDelay(delay time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delay = delay
+}
+
+// Close writes the pending changes to disk. Changes made afterwards are
+// written at once.
+func (f *FileStore) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+	f.mu.Unlock()
+
+	return f.flush()
+}
+
+// scheduleFlush writes the data once the flush delay has passed, unless a
+// write is already pending
+func (f *FileStore) scheduleFlush() error {
+	f.mu.Lock()
+	if f.closed || f.delay <= 0 {
+		f.mu.Unlock()
+		return f.flush()
+	}
+	if f.timer == nil {
+		f.timer = time.AfterFunc(f.delay, f.flushPending)
+	}
+	f.mu.Unlock()
+	return nil
+}
+
+// flushPending makes a scheduled write, trying again later if it fails
+func (f *FileStore) flushPending() {
+	f.mu.Lock()
+	f.timer = nil
+	f.mu.Unlock()
+
+	if err := f.flush(); err != nil {
+		log.Printf("Failed to save storage, retrying: %v", err)
+		if err := f.scheduleFlush(); err != nil {
+			log.Printf("Failed to save storage: %v", err)
+		}
+	}
+}
+
+// load reads the data file if it exists
+func (f *FileStore) load() error {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read storage file: %w", err)
+	}
+
+	var snapshot fileSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode storage file %s: %w", f.path, err)
+	}
+
+	for chatID, state := range snapshot.UserStates {
+		if state != nil {
+			f.userStates[chatID] = state
+		}
+	}
+	for id, alert := range snapshot.Alerts {
+		f.alerts[id] = alert
+	}
+	for chatID, prefs := range snapshot.Preferences {
+		f.preferences[chatID] = prefs
+	}
+
+	return nil
+}
+
+// flush atomically writes all data to disk
+func (f *FileStore) flush() error {
+	f.writeMu.Lock()
+	defer f.writeMu.Unlock()
+
+	f.MemoryStore.mu.RLock()
+	data, err := json.MarshalIndent(fileSnapshot{
+		UserStates:  f.userStates,
+		Alerts:      f.alerts,
+		Preferences: f.preferences,
+	}, "", "  ")
+	f.MemoryStore.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode storage data: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(f.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "store.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	state := &UserState{CurrentStep: "select_date", Mode: "alert", MinPrice: 100000}
+	alert := testAlert("a", 1)
+	if err := store.SaveUserState(1, state); err != nil {
+		t.Fatalf("SaveUserState: %v", err)
+	}
+	if err := store.SaveUserState(2, state); err != nil {
+		t.Fatalf("SaveUserState: %v", err)
+	}
+	if err := store.DeleteUserState(2); err != nil {
+		t.Fatalf("DeleteUserState: %v", err)
+	}
+	if err := store.SaveAlert(alert); err != nil {
+		t.Fatalf("SaveAlert: %v", err)
+	}
+	if err := store.SaveAlert(testAlert("b", 2)); err != nil {
+		t.Fatalf("SaveAlert: %v", err)
+	}
+	if err := store.DeleteAlert("b"); err != nil {
+		t.Fatalf("DeleteAlert: %v", err)
+	}
+	if err := store.SavePreferences(1, Preferences{Language: "uz"}); err != nil {
+		t.Fatalf("SavePreferences: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	if got, err := reopened.GetUserState(1); err != nil || !reflect.DeepEqual(got, state) {
+		t.Errorf("user state after reopen = %+v, %v, want %+v", got, err, state)
+	}
+	if _, err := reopened.GetUserState(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted user state after reopen: got %v, want ErrNotFound", err)
+	}
+	alerts, err := reopened.ListAlerts()
+	if err != nil {
+		t.Fatalf("ListAlerts: %v", err)
+	}
+	if len(alerts) != 1 || !reflect.DeepEqual(alerts[0], alert) {
+		t.Errorf("alerts after reopen = %+v, want only %+v", alerts, alert)
+	}
+	if prefs, _ := reopened.GetPreferences(1); prefs.Language != "uz" {
+		t.Errorf("language after reopen = %q, want uz", prefs.Language)
+	}
+}
+
+func TestFileStoreWritesChangesAfterDelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	defer store.Close()
+	store.SetFlushDelay(50 * time.Millisecond)
+
+	for chatID := int64(1); chatID <= 100; chatID++ {
+		if err := store.SaveUserState(chatID, &UserState{CurrentStep: "select_date"}); err != nil {
+			t.Fatalf("SaveUserState: %v", err)
+		}
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("file written before the flush delay: %v", err)
+	}
+
+	// Without Close, the changes are on disk once the delay has passed
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		reopened, err := NewFileStore(path)
+		if err != nil {
+			t.Fatalf("reopen: %v", err)
+		}
+		if _, err := reopened.GetUserState(100); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("changes were never written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFileStoreWritesChangesAfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	store.SetFlushDelay(time.Hour)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Work that outlived a shutdown timeout still saves its changes
+	if err := store.SaveUserState(1, &UserState{CurrentStep: "select_date"}); err != nil {
+		t.Fatalf("SaveUserState after Close: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	if _, err := reopened.GetUserState(1); err != nil {
+		t.Errorf("change made after Close was not written: %v", err)
+	}
+}
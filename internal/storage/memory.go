@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// MemoryStore keeps all data in memory. Everything is lost on restart,
+// which makes it suitable for tests and local development.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	userStates  map[int64]*UserState
+	alerts      map[string]train.TicketAlert
+	preferences map[int64]Preferences
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		userStates:  make(map[int64]*UserState),
+		alerts:      make(map[string]train.TicketAlert),
+		preferences: make(map[int64]Preferences),
+	}
+}
+
+// GetUserState returns a copy of the saved conversation state
+func (m *MemoryStore) GetUserState(chatID int64) (*UserState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state, exists := m.userStates[chatID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return copyUserState(state), nil
+}
+
+// SaveUserState stores a copy of the conversation state
+func (m *MemoryStore) SaveUserState(chatID int64, state *UserState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.userStates[chatID] = copyUserState(state)
+	return nil
+}
+
+// DeleteUserState removes the conversation state
+func (m *MemoryStore) DeleteUserState(chatID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.userStates, chatID)
+	return nil
+}
+
+// GetAlert returns the alert with the given ID
+func (m *MemoryStore) GetAlert(id string) (train.TicketAlert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alert, exists := m.alerts[id]
+	if !exists {
+		return train.TicketAlert{}, ErrNotFound
+	}
+	return copyAlert(alert), nil
+}
+
+// ListAlerts returns all alerts ordered by creation time
+func (m *MemoryStore) ListAlerts() ([]train.TicketAlert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alerts := make([]train.TicketAlert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		alerts = append(alerts, copyAlert(alert))
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+	})
+
+	return alerts, nil
+}
+
+// SaveAlert creates or replaces an alert
+func (m *MemoryStore) SaveAlert(alert train.TicketAlert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.alerts[alert.ID] = copyAlert(alert)
+	return nil
+}
+
+// DeleteAlert removes an alert
+func (m *MemoryStore) DeleteAlert(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.alerts[id]; !exists {
+		return ErrNotFound
+	}
+	delete(m.alerts, id)
+	return nil
+}
+
+// GetPreferences returns the saved preferences
+func (m *MemoryStore) GetPreferences(chatID int64) (Preferences, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.preferences[chatID], nil
+}
+
+// SavePreferences stores the preferences
+func (m *MemoryStore) SavePreferences(chatID int64, prefs Preferences) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.preferences[chatID] = prefs
+	return nil
+}
+
+// Close is a no-op for the in-memory store
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// Supported storage backends
+const (
+	BackendFile   = "file"
+	BackendMemory = "memory"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// Store persists user conversation state, ticket alerts and user preferences
+type Store interface {
+	// GetUserState returns the saved conversation state or ErrNotFound
+	GetUserState(chatID int64) (*UserState, error)
+	SaveUserState(chatID int64, state *UserState) error
+	DeleteUserState(chatID int64) error
+
+	// GetAlert returns the alert with the given ID or ErrNotFound
+	GetAlert(id string) (train.TicketAlert, error)
+	ListAlerts() ([]train.TicketAlert, error)
+	SaveAlert(alert train.TicketAlert) error
+	DeleteAlert(id string) error
+
+	// GetPreferences returns the saved preferences, or zero preferences if none were saved
+	GetPreferences(chatID int64) (Preferences, error)
+	SavePreferences(chatID int64, prefs Preferences) error
+
+	// Close persists pending changes. The store stays usable: work that
+	// outlived a shutdown timeout may still save changes, which are
+	// persisted at once.
+	Close() error
+}
+
+// UserState tracks where a user is in a multi-step bot conversation
+type UserState struct {
+	CurrentStep string    `json:"currentStep"`
+	Mode        string    `json:"mode,omitempty"` // Flow the user is in, e.g. alert creation; empty for plain searches
+	FromStation string    `json:"fromStation"`
+	ToStation   string    `json:"toStation"`
+	SearchDate  time.Time `json:"searchDate"`
+	ReturnDate  time.Time `json:"returnDate"` // Way back of a round trip, zero for one-way
+
+	// Alert creation flow
+	SeatTypes []string `json:"seatTypes,omitempty"`
+	MinPrice  float64  `json:"minPrice,omitempty"`
+	MaxPrice  float64  `json:"maxPrice,omitempty"`
+	Direction string   `json:"direction,omitempty"` // Round trip legs the alert watches
+}
+
+// Preferences holds per-user settings
+type Preferences struct {
+	Language string `json:"language,omitempty"`
+}
+
+// Open creates a store for the given backend. The path is only used by the file backend.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendFile, "":
+		return NewFileStore(path)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
+
+// copyUserState returns a deep copy of the state
+func copyUserState(state *UserState) *UserState {
+	c := *state
+	c.SeatTypes = append([]string(nil), state.SeatTypes...)
+	return &c
+}
+
+// copyAlert returns a deep copy of the alert
+func copyAlert(alert train.TicketAlert) train.TicketAlert {
+	alert.SeatTypes = append([]string(nil), alert.SeatTypes...)
+	return alert
+}
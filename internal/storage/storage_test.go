@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AlibekAbdunasimov/chiptatop/internal/services/train"
+)
+
+// backends opens an empty store of every backend
+var backends = []struct {
+	name string
+	open func(t *testing.T) Store
+}{
+	{BackendMemory, func(t *testing.T) Store { return NewMemoryStore() }},
+	{BackendFile, func(t *testing.T) Store {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+		if err != nil {
+			t.Fatalf("NewFileStore: %v", err)
+		}
+		return store
+	}},
+}
+
+// testAlert returns an alert created at the given minute of a fixed day
+func testAlert(id string, minute int) train.TicketAlert {
+	return train.TicketAlert{
+		ID:        id,
+		ChatID:    42,
+		From:      "Toshkent",
+		To:        "Samarqand",
+		Date:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		SeatTypes: []string{"Kupe"},
+		IsActive:  true,
+		CreatedAt: time.Date(2026, 2, 1, 12, minute, 0, 0, time.UTC),
+	}
+}
+
+func TestUserStates(t *testing.T) {
+	state := &UserState{
+		CurrentStep: "select_to_station",
+		FromStation: "Toshkent",
+		SearchDate:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		SeatTypes:   []string{"Kupe", "SV"},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t)
+			defer store.Close()
+
+			if _, err := store.GetUserState(1); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("GetUserState of a new chat: got %v, want ErrNotFound", err)
+			}
+
+			if err := store.SaveUserState(1, state); err != nil {
+				t.Fatalf("SaveUserState: %v", err)
+			}
+			got, err := store.GetUserState(1)
+			if err != nil {
+				t.Fatalf("GetUserState: %v", err)
+			}
+			if !reflect.DeepEqual(got, state) {
+				t.Errorf("GetUserState() = %+v, want %+v", got, state)
+			}
+
+			// The store keeps its own copy
+			got.SeatTypes[0] = "changed"
+			if again, _ := store.GetUserState(1); again.SeatTypes[0] != "Kupe" {
+				t.Errorf("changing a returned state changed the stored one")
+			}
+
+			if err := store.DeleteUserState(1); err != nil {
+				t.Fatalf("DeleteUserState: %v", err)
+			}
+			if _, err := store.GetUserState(1); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetUserState after delete: got %v, want ErrNotFound", err)
+			}
+			if err := store.DeleteUserState(1); err != nil {
+				t.Errorf("deleting a missing state: %v", err)
+			}
+		})
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	tests := []struct {
+		name    string
+		save    []train.TicketAlert
+		delete  []string
+		wantIDs []string
+	}{
+		{"empty", nil, nil, []string{}},
+		{"ordered by creation", []train.TicketAlert{testAlert("b", 2), testAlert("c", 3), testAlert("a", 1)}, nil, []string{"a", "b", "c"}},
+		{"replaced", []train.TicketAlert{testAlert("a", 1), testAlert("a", 5), testAlert("b", 2)}, nil, []string{"b", "a"}},
+		{"deleted", []train.TicketAlert{testAlert("a", 1), testAlert("b", 2)}, []string{"a"}, []string{"b"}},
+	}
+
+	for _, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				store := backend.open(t)
+				defer store.Close()
+
+				for _, alert := range tt.save {
+					if err := store.SaveAlert(alert); err != nil {
+						t.Fatalf("SaveAlert(%s): %v", alert.ID, err)
+					}
+				}
+				for _, id := range tt.delete {
+					if err := store.DeleteAlert(id); err != nil {
+						t.Fatalf("DeleteAlert(%s): %v", id, err)
+					}
+				}
+
+				alerts, err := store.ListAlerts()
+				if err != nil {
+					t.Fatalf("ListAlerts: %v", err)
+				}
+				ids := make([]string, 0, len(alerts))
+				for _, alert := range alerts {
+					ids = append(ids, alert.ID)
+				}
+				if !reflect.DeepEqual(ids, tt.wantIDs) {
+					t.Errorf("ListAlerts() IDs = %v, want %v", ids, tt.wantIDs)
+				}
+
+				for _, id := range tt.wantIDs {
+					if _, err := store.GetAlert(id); err != nil {
+						t.Errorf("GetAlert(%s): %v", id, err)
+					}
+				}
+				for _, id := range tt.delete {
+					if _, err := store.GetAlert(id); !errors.Is(err, ErrNotFound) {
+						t.Errorf("GetAlert(%s) after delete: got %v, want ErrNotFound", id, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMissingAlert(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t)
+			defer store.Close()
+
+			if _, err := store.GetAlert("missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetAlert: got %v, want ErrNotFound", err)
+			}
+			if err := store.DeleteAlert("missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("DeleteAlert: got %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t)
+			defer store.Close()
+
+			// Chats without saved preferences get zero preferences, not an error
+			prefs, err := store.GetPreferences(7)
+			if err != nil || prefs != (Preferences{}) {
+				t.Fatalf("GetPreferences of a new chat = %+v, %v, want zero preferences", prefs, err)
+			}
+
+			if err := store.SavePreferences(7, Preferences{Language: "ru"}); err != nil {
+				t.Fatalf("SavePreferences: %v", err)
+			}
+			if prefs, _ := store.GetPreferences(7); prefs.Language != "ru" {
+				t.Errorf("GetPreferences() language = %q, want ru", prefs.Language)
+			}
+		})
+	}
+}